@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/diff"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+	"github.com/vaguecoder/firefox-backups/pkg/flags"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	pkgText "github.com/vaguecoder/firefox-backups/pkg/text"
+)
+
+const (
+	// Names of the snapshots of places.sqlite in workspace
+	oldPlacesDBFile = `old-places.sqlite`
+	newPlacesDBFile = `new-places.sqlite`
+
+	// diffJSONIndentation is the indentation of changes in JSON format
+	diffJSONIndentation = "\t"
+)
+
+// diffBookmarks runs the diff command, i.e., prints the changes in bookmarks
+// from the old snapshot to the new snapshot on stdout
+func diffBookmarks(ctx context.Context, args []string) {
+	var (
+		err       error
+		logger    logs.Logger
+		diffFlags *flags.DiffFlags
+		changes   []diff.Change
+	)
+
+	// The changes are printed on stdout, so the app logs are written to stderr
+	ctx, logger = logs.NewLogger(ctx, os.Stderr, logs.LevelInfo)
+
+	// Read the input flags of diff command
+	diffFlags, err = flags.NewOperator(args).ParseDiff()
+	if err != nil {
+		logger.Fatal().Err(err).Strs("args", args).Msg("Failed to parse flags from command line args")
+	}
+
+	// When silent mode is enabled in input flags, replace logger with silent logger.
+	if diffFlags.Silent {
+		ctx, logger = logs.SilentLogger(ctx)
+	}
+
+	// Log input flag values
+	logger.Info().Interface("flags", diffFlags).Msg("Input flags")
+
+	changes, err = compareSources(ctx, diffFlags.Old, diffFlags.New)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to compare snapshots")
+	}
+
+	logger.Info().Int("count", len(changes)).Msg("Count of changes")
+
+	if err = printChanges(changes, diffFlags.StdOutFormat); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to print changes")
+	}
+}
+
+// compareSources reads the bookmarks from both the sources and compares them.
+// The places.sqlite sources are read from their snapshots in a private workspace.
+func compareSources(ctx context.Context, oldSource, newSource flags.Source) (changes []diff.Change, err error) {
+	var (
+		workspace                  *files.Workspace
+		oldBookmarks, newBookmarks []bookmark.Bookmark
+	)
+
+	if oldSource.Format == constants.SQLiteFormat || newSource.Format == constants.SQLiteFormat {
+		// When either of the sources is places.sqlite, it is snapshot in workspace
+		workspace, err = files.NewWorkspace(ctx)
+		if err != nil {
+			// When creation of workspace failed
+			return nil, err
+		}
+
+		// Delete the workspace after completion or failure
+		defer func() {
+			if closeErr := workspace.Close(); closeErr != nil && err == nil {
+				// When deletion of workspace failed
+				err = closeErr
+			}
+		}()
+	}
+
+	if oldBookmarks, err = readSource(ctx, workspace, oldSource, oldPlacesDBFile); err != nil {
+		return nil, fmt.Errorf("failed to read --%s: %v", constants.OldFlag, err)
+	}
+
+	if newBookmarks, err = readSource(ctx, workspace, newSource, newPlacesDBFile); err != nil {
+		return nil, fmt.Errorf("failed to read --%s: %v", constants.NewFlag, err)
+	}
+
+	logs.FromContext(ctx).Info().Int("old-count", len(oldBookmarks)).Int("new-count", len(newBookmarks)).
+		Msg("Count of bookmarks read")
+
+	return diff.Compare(oldBookmarks, newBookmarks), nil
+}
+
+// printChanges prints the changes on stdout, either as table or as JSON
+func printChanges(changes []diff.Change, format constants.Constant[constants.OutputFormat]) error {
+	if format == constants.JSONFormat {
+		if changes == nil {
+			// Empty array instead of null when there are no changes
+			changes = []diff.Change{}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", diffJSONIndentation)
+
+		if err := encoder.Encode(changes); err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+
+		return nil
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("No changes found")
+		return nil
+	}
+
+	for _, line := range pkgText.Table(diff.Table(changes), true, "") {
+		fmt.Println(line)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	db "github.com/vaguecoder/firefox-backups/pkg/database"
+	"github.com/vaguecoder/firefox-backups/pkg/database/sqlite"
+	pkgEncoding "github.com/vaguecoder/firefox-backups/pkg/encoding"
+	pkgEncodingCSV "github.com/vaguecoder/firefox-backups/pkg/encoding/csv"
+	pkgEncodingJSON "github.com/vaguecoder/firefox-backups/pkg/encoding/json"
+	pkgEncodingTab "github.com/vaguecoder/firefox-backups/pkg/encoding/tabular"
+	pkgEncodingYAML "github.com/vaguecoder/firefox-backups/pkg/encoding/yaml"
+	"github.com/vaguecoder/firefox-backups/pkg/flags"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+// importBookmarks runs the import command, i.e., writes the missing folders and bookmarks
+// in the input file into places.sqlite of the target
+func importBookmarks(ctx context.Context, args []string) {
+	var (
+		err         error
+		importFlags *flags.ImportFlags
+		bookmarks   []bookmark.Bookmark
+		conn        sqlite.DBWriter
+		result      db.ImportResult
+
+		logger = logs.FromContext(ctx)
+	)
+
+	// Read the input flags of import command
+	importFlags, err = flags.NewOperator(args).ParseImport()
+	if err != nil {
+		logger.Fatal().Err(err).Strs("args", args).Msg("Failed to parse flags from command line args")
+	}
+
+	// When silent mode is enabled in input flags, replace logger with silent logger.
+	if importFlags.Silent {
+		ctx, logger = logs.SilentLogger(ctx)
+	}
+
+	// Log input flag values
+	logger.Info().Interface("flags", importFlags).Msg("Input flags")
+
+	if importFlags.Profile != "" {
+		// When target is a profile, its places.sqlite file is the target file
+		importFlags.TargetSQLiteFile = findProfile(ctx, importFlags.ProfilesRoot, importFlags.Profile).PlacesFile()
+		logger.Info().Str("profile", importFlags.Profile).Str("target-sqlite-file", importFlags.TargetSQLiteFile).
+			Msg("Resolved profile")
+	}
+
+	bookmarks, err = readBookmarks(importFlags.InputFile)
+	if err != nil {
+		// When reading of input file failed
+		logger.Fatal().Err(err).Str("input-file", importFlags.InputFile.Filename).Msg("Failed to read input file")
+	}
+
+	logger.Info().Int("count", len(bookmarks)).Msg("Count of bookmarks read")
+
+	// Open target for writing, which fails while Firefox is running
+	conn, err = sqlite.NewWriteDB(importFlags.TargetSQLiteFile)
+	if err != nil {
+		// When opening of target failed
+		logger.Fatal().Err(err).Str("target-sqlite-file", importFlags.TargetSQLiteFile).
+			Msg("Failed to open target for writing")
+	}
+	defer conn.Close()
+
+	result, err = db.NewImporter(conn).Import(ctx, bookmarks)
+	if err != nil {
+		// When import failed, nothing is written to target
+		logger.Fatal().Err(err).Str("target-sqlite-file", importFlags.TargetSQLiteFile).
+			Msg("Failed to import bookmarks")
+	}
+
+	logger.Info().Int("folders", result.Folders).Int("bookmarks", result.Bookmarks).Int("skipped", result.Skipped).
+		Str("target-sqlite-file", importFlags.TargetSQLiteFile).Msg("Imported bookmarks")
+}
+
+// readBookmarks reads the bookmarks exported in CSV, JSON, table or YAML format
+func readBookmarks(input flags.InputFile) ([]bookmark.Bookmark, error) {
+	var decoder pkgEncoding.Decoder
+
+	file, err := os.Open(input.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %q: %v", input.Filename, err)
+	}
+	defer file.Close()
+
+	switch input.Format {
+	case constants.CSVFormat:
+		decoder = pkgEncodingCSV.NewDecoder(file)
+	case constants.JSONFormat:
+		decoder = pkgEncodingJSON.NewDecoder(file)
+	case constants.TabularFormat:
+		decoder = pkgEncodingTab.NewDecoder(file)
+	case constants.YAMLFormat:
+		decoder = pkgEncodingYAML.NewDecoder(file)
+	default:
+		// Input format is already validated at input flags
+		return nil, fmt.Errorf("unsupported format %q", input.Format)
+	}
+
+	bookmarks, err := decoder.Decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s file %q: %v", input.Format, input.Filename, err)
+	}
+
+	return bookmarks, nil
+}
@@ -2,49 +2,67 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 
 	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
 	"github.com/vaguecoder/firefox-backups/pkg/constants"
 	db "github.com/vaguecoder/firefox-backups/pkg/database"
+	"github.com/vaguecoder/firefox-backups/pkg/database/jsonlz4"
 	"github.com/vaguecoder/firefox-backups/pkg/database/sqlite"
 	pkgEncoding "github.com/vaguecoder/firefox-backups/pkg/encoding"
 	pkgEncodingCSV "github.com/vaguecoder/firefox-backups/pkg/encoding/csv"
+	pkgEncodingHTML "github.com/vaguecoder/firefox-backups/pkg/encoding/html"
 	pkgEncodingJSON "github.com/vaguecoder/firefox-backups/pkg/encoding/json"
+	pkgEncodingJSONL "github.com/vaguecoder/firefox-backups/pkg/encoding/jsonl"
+	pkgEncodingJSONLZ4 "github.com/vaguecoder/firefox-backups/pkg/encoding/jsonlz4"
+	pkgEncodingMarkdown "github.com/vaguecoder/firefox-backups/pkg/encoding/markdown"
+	pkgEncodingOrg "github.com/vaguecoder/firefox-backups/pkg/encoding/org"
+	pkgEncodingSite "github.com/vaguecoder/firefox-backups/pkg/encoding/site"
+	pkgEncodingSQLite "github.com/vaguecoder/firefox-backups/pkg/encoding/sqlite"
 	pkgEncodingTab "github.com/vaguecoder/firefox-backups/pkg/encoding/tabular"
 	pkgEncodingYAML "github.com/vaguecoder/firefox-backups/pkg/encoding/yaml"
 	"github.com/vaguecoder/firefox-backups/pkg/files"
 	"github.com/vaguecoder/firefox-backups/pkg/filters"
+	deadlinks "github.com/vaguecoder/firefox-backups/pkg/filters/dead-links"
+	"github.com/vaguecoder/firefox-backups/pkg/filters/dedupe"
 	"github.com/vaguecoder/firefox-backups/pkg/filters/denormalize"
+	"github.com/vaguecoder/firefox-backups/pkg/filters/folders"
 	ignoredefaults "github.com/vaguecoder/firefox-backups/pkg/filters/ignore-defaults"
+	"github.com/vaguecoder/firefox-backups/pkg/filters/redirects"
+	"github.com/vaguecoder/firefox-backups/pkg/filters/tags"
+	"github.com/vaguecoder/firefox-backups/pkg/filters/where"
 	"github.com/vaguecoder/firefox-backups/pkg/flags"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
 	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/profiles"
+	pkgText "github.com/vaguecoder/firefox-backups/pkg/text"
 )
 
 const (
 	placesDBFile = `places.sqlite`
 	stdout       = `stdout`
+
+	// Prefix of the copies of input files in workspace
+	copyPrefix = `copy-`
 )
 
 func main() {
 	var (
-		err                               error
-		denormalizeOps, ignoredefaultsOps filters.Filter
-		encoder                           pkgEncoding.Encoder
-		encoderManager                    *pkgEncoding.EncodingManager
-		logger                            logs.Logger
-		inputFlags                        *flags.Flags
-		outputFile                        files.File
-		dbConn                            sqlite.DBConnection
-		dbOps                             db.BookmarkOperator
-		bookmarks                         []bookmark.Bookmark
-		fileOps                           files.FileOperator
-		outputFileSet                     flags.OutputFile
-
-		ctx          = context.Background()
-		enableHeader = true
+		err        error
+		logger     logs.Logger
+		inputFlags *flags.Flags
+		list       []profiles.Profile
+		failed     int
+
+		ctx = context.Background()
+		now = time.Now()
 
 		// Initialize flag operator with input arguments
 		flagOps = flags.NewOperator(os.Args[1:])
@@ -53,6 +71,24 @@ func main() {
 	// Create new logger and add to context for easy propagation
 	ctx, logger = logs.NewLogger(ctx, os.Stdout, logs.LevelInfo)
 
+	if len(os.Args) > 1 && os.Args[1] == constants.ImportCommand.String() {
+		// When import command is requested, the bookmarks are written into places.sqlite instead
+		importBookmarks(ctx, os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == constants.DiffCommand.String() {
+		// When diff command is requested, the changes between two snapshots are printed instead
+		diffBookmarks(ctx, os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == constants.MergeCommand.String() {
+		// When merge command is requested, the bookmarks of multiple sources are combined instead
+		mergeBookmarks(ctx, os.Args[2:])
+		return
+	}
+
 	// Read the input flags
 	inputFlags, err = flagOps.Parse()
 	if err != nil {
@@ -67,52 +103,449 @@ func main() {
 	// Log input flag values
 	logger.Info().Interface("flags", inputFlags).Msg("Input flags")
 
+	if inputFlags.ListProfiles {
+		// When listing of profiles is requested, nothing else is done
+		listProfiles(ctx, inputFlags)
+		return
+	}
+
 	// Initiate files operator for file creation, copying, deletion, etc.
-	fileOps = files.NewOperator(ctx)
+	fileOps := files.NewOperator(ctx)
+
+	if !inputFlags.AllProfiles {
+		if inputFlags.Profile != "" {
+			// When input is a profile, its places.sqlite file is the input file
+			inputFlags.SQLiteDBFilename = findProfile(ctx, inputFlags.ProfilesRoot, inputFlags.Profile).PlacesFile()
+			logger.Info().Str("profile", inputFlags.Profile).Str("input-sqlite-file", inputFlags.SQLiteDBFilename).
+				Msg("Resolved profile")
+		}
+
+		if err = run(ctx, *inputFlags, fileOps, inputFlags.Profile, now); err != nil {
+			logger.Fatal().Err(err).Msg("Failed to back up bookmarks")
+		}
 
-	if inputFlags.SQLiteDBFilename != placesDBFile {
-		// When input sqlite DB file is not same as places.sqlite,
-		// i.e., when input file either has a non-default name, or
-		// it is in a different location, the file has to be copied to operate,
-		// to avoid conflicts, locking or corruption.
-		if err = fileOps.Copy(inputFlags.SQLiteDBFilename, placesDBFile); err != nil {
-			// When copying of input file as places.sqlite failed
-			logger.Fatal().Err(err).Str("input-sqlite-file", inputFlags.SQLiteDBFilename).
-				Str("temp-file", placesDBFile).Msg("Failed to copy file")
+		return
+	}
+
+	// When all profiles are requested, the profiles are backed up one after the other
+	list, err = profiles.NewOperator(profileRoots(ctx, inputFlags.ProfilesRoot)...).List()
+	if err != nil {
+		// When discovery of profiles failed
+		logger.Fatal().Err(err).Msg("Failed to list profiles")
+	}
+
+	if len(list) == 0 {
+		logger.Fatal().Msg("No Firefox profiles found")
+	}
+
+	var (
+		// Names of profiles expanded in {profile} template, unique across the roots
+		names = profiles.UniqueNames(list)
+
+		// Profiles against their output filenames, so no profile overwrites the outputs of another
+		claimed = map[string]string{}
+	)
+
+	for i, profile := range list {
+		profileLogger := logs.FromRawLogger(logger.With().Str("profile", names[i]).Str("path", profile.Path).Logger())
+		profileCtx := logs.WithLogger(ctx, profileLogger)
+
+		profileFlags := *inputFlags
+		profileFlags.Profile = profile.Name
+		profileFlags.SQLiteDBFilename = profile.PlacesFile()
+
+		if err = claimOutputs(claimed, inputFlags.OutputFiles, names[i], now); err != nil {
+			// When the outputs of an earlier profile would be overwritten
+			profileLogger.Error().Err(err).Msg("Failed to back up profile")
+			failed++
+			continue
 		}
 
-		// Delete the copied file (and matching files) after completion or failure
+		profileLogger.Info().Msg("Backing up profile")
+
+		if err = run(profileCtx, profileFlags, fileOps, names[i], now); err != nil {
+			// When a profile failed, the rest of the profiles are still backed up
+			profileLogger.Error().Err(err).Msg("Failed to back up profile")
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		logger.Fatal().Int("failed", failed).Int("total", len(list)).Msgf("Failed to back up %d of %d profiles",
+			failed, len(list))
+	}
+}
+
+// claimOutputs records the expanded filenames of outputs against the profile,
+// failing when a filename is already claimed by another profile
+func claimOutputs(claimed map[string]string, outputFiles []flags.OutputFile, profile string, now time.Time) error {
+	filenames := make([]string, 0, len(outputFiles))
+
+	for _, outputFileSet := range outputFiles {
+		filename := filepath.Clean(outputFileSet.ExpandFilename(profile, now))
+		if owner, ok := claimed[filename]; ok {
+			return fmt.Errorf("output file %q of profile %q is already written for profile %q", filename, profile,
+				owner)
+		}
+
+		filenames = append(filenames, filename)
+	}
+
+	for _, filename := range filenames {
+		claimed[filename] = profile
+	}
+
+	return nil
+}
+
+// run fetches the bookmarks or history visits from input, filters them,
+// and encodes them to stdout and output files. The templates in output filenames
+// are expanded by the profile name and the date.
+func run(ctx context.Context, inputFlags flags.Flags, fileOps files.FileOperator, profile string,
+	now time.Time) (err error) {
+	var (
+		encoders         []pkgEncoding.Encoder
+		encoderManager   *pkgEncoding.EncodingManager
+		outputFiles      []files.File
+		dbConn           sqlite.DBConnection
+		dbOps            db.BookmarkOperator
+		bookmarkStreamer db.BookmarkStreamer
+		historyOps       db.HistoryOperator
+		visits           []history.Visit
+		bookmarks        []bookmark.Bookmark
+		checker          *links.Checker
+		workspace        *files.Workspace
+
+		logger = logs.FromContext(ctx)
+	)
+
+	if inputFlags.JSONLZ4Filename != "" {
+		// When input is Firefox's jsonlz4 backup file, it is read as is.
+		// The places.sqlite file is neither required nor copied.
+		dbOps = jsonlz4.NewBackupOperator(inputFlags.JSONLZ4Filename)
+	} else {
+		// The snapshot is taken in a private workspace of the run, so nothing in
+		// the working directory (possibly a profile directory) is touched or deleted.
+		workspace, err = files.NewWorkspace(ctx)
+		if err != nil {
+			// When creation of workspace failed
+			return err
+		}
+
+		// Delete the workspace after completion or failure
 		defer func() {
-			if err = fileOps.Delete(placesDBFile); err != nil {
-				// When deletion of copied input file failed
-				logger.Fatal().Err(err).Str("temp-file", placesDBFile).Msg("Failed to temp files")
+			if closeErr := workspace.Close(); closeErr != nil && err == nil {
+				// When deletion of workspace failed
+				err = closeErr
 			}
 		}()
+
+		// Firefox keeps places.sqlite open with recent transactions in write-ahead log,
+		// so a consistent snapshot of the input is read instead of the input itself.
+		dbConn, err = openSnapshot(ctx, workspace, inputFlags.SQLiteDBFilename, placesDBFile)
+		if err != nil {
+			return err
+		}
+
+		// Close the connection ahead of deleting the workspace
+		defer func() {
+			if closeErr := dbConn.Close(); closeErr != nil {
+				logger.Error().Err(closeErr).Msg("Failed to close DB connection")
+			}
+		}()
+
+		// Initiate database operators
+		dbOps = db.NewDatabaseOperator(dbConn)
+		historyOps = db.NewHistoryOperator(dbConn)
+	}
+
+	// Initialize encoder manager
+	encoderManager = pkgEncoding.NewEncoderManager(ctx)
+
+	if inputFlags.Mode == constants.HistoryMode {
+		// When history mode is enabled, the visits are encoded instead of bookmarks.
+		// The input is validated to be places.sqlite at input flags.
+		visits, err = historyOps.GetVisits(ctx, inputFlags.HistoryRange)
+		if err != nil {
+			// When fetching visits from input failed
+			return fmt.Errorf("failed to fetch history visits from input: %v", err)
+		}
+
+		logger.Info().Int("count", len(visits)).Msg("Count of history visits fetched")
+
+		// Add visits to manager
+		encoderManager = encoderManager.Visits(visits)
+	} else if streamer, ok := dbOps.(db.BookmarkStreamer); ok && streamable(&inputFlags) {
+		// When the bookmarks need no filter and all the outputs are streamed, the bookmarks are
+		// encoded one at a time as they're fetched, once the encoders are added
+		bookmarkStreamer = streamer
+	} else {
+		// The checker is shared by check-links mode and the link filters, so every link is requested once
+		checker = links.NewChecker(ctx, &http.Client{Timeout: inputFlags.CheckTimeout}).
+			Workers(inputFlags.CheckWorkers).
+			HostInterval(inputFlags.CheckHostInterval)
+
+		bookmarks, err = fetchBookmarks(ctx, dbOps, &inputFlags, checker)
+		if err != nil {
+			return err
+		}
+
+		if inputFlags.Mode == constants.CheckLinksMode {
+			// When check-links mode is enabled, the links of bookmarks are encoded instead of bookmarks
+			encoderManager = encoderManager.Links(checker.Check(ctx, bookmarks))
+		} else {
+			// Add fetched and filtered bookmarks to manager
+			encoderManager = encoderManager.Bookmarks(bookmarks)
+		}
+	}
+
+	if inputFlags.StdOutFormat != nil {
+		// When stdout printer is also enabled
+		if encoder, ok := inputFlags.StdOutFormat.(pkgEncoding.FrontMatterEncoder); ok {
+			encoder.SetFrontMatter(frontMatter(inputFlags.FrontMatter, profile, now))
+		}
+
+		encoderManager = encoderManager.Encoder(inputFlags.StdOutFormat)
+	}
+
+	// Close the output files after completion or failure
+	defer func() {
+		if closeErr := closeOutputs(outputFiles); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	// Create the output files, with the templates in filenames expanded, and their encoders
+	outputFiles, encoders, err = openOutputs(fileOps, inputFlags.OutputFiles, inputFlags.FrontMatter,
+		inputFlags.JSONLFields, profile, now)
+	if err != nil {
+		return err
 	}
 
+	for _, encoder := range encoders {
+		// Append the encoder to the list in manager
+		encoderManager = encoderManager.Encoder(encoder)
+	}
+
+	if bookmarkStreamer != nil {
+		// Encode bookmarks against all output formats as they're fetched, without holding them in memory
+		err = encoderManager.Stream(func(encode func(bookmark.Bookmark) error) error {
+			return bookmarkStreamer.StreamBookmarks(ctx, encode)
+		})
+		if err != nil {
+			// When fetching or encoding bookmarks failed
+			return fmt.Errorf("failed to stream to output stream(s): %v", err)
+		}
+
+		return nil
+	}
+
+	// Encode bookmarks against all output formats (stdout or file formats)
+	if err = encoderManager.Write(); err != nil {
+		// When encoding bookmarks failed
+		return fmt.Errorf("failed to encode to output stream(s): %v", err)
+	}
+
+	return nil
+}
+
+// openOutputs creates the output files, with the templates in filenames expanded by the profile name
+// and the date, and initiates the encoder of each file per its format. The caller closes the files,
+// even on failure, as the files created ahead of the failure are returned.
+func openOutputs(fileOps files.FileOperator, outputs []flags.OutputFile, frontMatterFields flags.FrontMatterFields,
+	jsonlFields flags.JSONLFields, profile string, now time.Time) ([]files.File, []pkgEncoding.Encoder, error) {
+	var (
+		outputFiles []files.File
+		encoders    []pkgEncoding.Encoder
+	)
+
+	// Iterate over list of input format-filename flag value sets
+	for _, outputFileSet := range outputs {
+		// Create output file, with the templates in filename expanded
+		filename := outputFileSet.ExpandFilename(profile, now)
+
+		outputFile, err := fileOps.Open(filename)
+		if err != nil {
+			// When creation of output file failed
+			return outputFiles, nil, fmt.Errorf("failed to create %s output file %q: %v", outputFileSet.Format,
+				filename, err)
+		}
+
+		if outputFileSet.Format == constants.SQLiteExportFormat {
+			// New SQLite database of normalized tables, written by the filename. The file is only created
+			// empty here, and closed right away, so SQLite is the only writer to the file.
+			if err = outputFile.Close(); err != nil {
+				return outputFiles, nil, fmt.Errorf("failed to close %s output file %q: %v", outputFileSet.Format,
+					filename, err)
+			}
+
+			encoder := pkgEncodingSQLite.NewEncoder(filename)
+			encoder.SetMetadata(profile, now)
+			encoders = append(encoders, encoder)
+
+			continue
+		}
+
+		outputFiles = append(outputFiles, outputFile)
+
+		// Map output file format against the encoder type
+		switch outputFileSet.Format {
+		case constants.CSVFormat:
+			// CSV format
+			encoders = append(encoders, pkgEncodingCSV.NewEncoder(outputFile, outputFileSet.Header()))
+		case constants.JSONFormat:
+			// JSON format
+			encoders = append(encoders, pkgEncodingJSON.NewEncoder(outputFile))
+		case constants.TabularFormat:
+			// Table format
+			encoders = append(encoders, pkgEncodingTab.NewEncoder(outputFile, outputFileSet.Header()))
+		case constants.YAMLFormat:
+			// YAML format
+			encoders = append(encoders, pkgEncodingYAML.NewEncoder(outputFile))
+		case constants.HTMLFormat:
+			// HTML format
+			encoders = append(encoders, pkgEncodingHTML.NewEncoder(outputFile))
+		case constants.JSONLZ4Format:
+			// Firefox's jsonlz4 backup format
+			encoders = append(encoders, pkgEncodingJSONLZ4.NewEncoder(outputFile))
+		case constants.JSONTreeFormat:
+			// JSON format of folder hierarchy
+			encoders = append(encoders, pkgEncodingJSON.NewTreeEncoder(outputFile))
+		case constants.YAMLTreeFormat:
+			// YAML format of folder hierarchy
+			encoders = append(encoders, pkgEncodingYAML.NewTreeEncoder(outputFile))
+		case constants.MarkdownFormat:
+			// Markdown format of notes
+			encoder := pkgEncodingMarkdown.NewEncoder(outputFile)
+			encoder.SetFrontMatter(frontMatter(outputFileSet.FrontMatter(frontMatterFields), profile, now))
+			encoders = append(encoders, encoder)
+		case constants.OrgFormat:
+			// Org-mode format of notes
+			encoder := pkgEncodingOrg.NewEncoder(outputFile)
+			encoder.SetFrontMatter(frontMatter(outputFileSet.FrontMatter(frontMatterFields), profile, now))
+			encoders = append(encoders, encoder)
+		case constants.SiteFormat:
+			// Self-contained HTML page
+			encoders = append(encoders, pkgEncodingSite.NewEncoder(outputFile))
+		case constants.JSONLFormat:
+			// JSON Lines format
+			encoders = append(encoders, pkgEncodingJSONL.NewEncoder(outputFile, outputFileSet.JSONLFields(jsonlFields)))
+		default:
+			// Input format is already validated at input flags
+		}
+	}
+
+	return outputFiles, encoders, nil
+}
+
+// closeOutputs closes the output files, returning the first failure
+func closeOutputs(outputFiles []files.File) error {
+	var err error
+
+	for _, outputFile := range outputFiles {
+		if closeErr := outputFile.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close output file %q: %v", outputFile.Name(), closeErr)
+		}
+	}
+
+	return err
+}
+
+// openSnapshot takes a consistent snapshot of places.sqlite into the workspace with the name,
+// and opens the database connection to the snapshot. The caller closes the connection.
+func openSnapshot(ctx context.Context, workspace *files.Workspace, sqliteFile, name string) (sqlite.DBConnection,
+	error) {
+	snapshotFile, err := workspace.Path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The copy of input file, only when it's locked by Firefox. Removed right after the snapshot.
+	copyFile, err := workspace.Path(copyPrefix + name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = sqlite.Snapshot(ctx, sqliteFile, snapshotFile, copyFile); err != nil {
+		// When snapshot of input file failed
+		return nil, err
+	}
+
+	if err = workspace.Remove(copyFile); err != nil {
+		return nil, err
+	}
+
+	logs.FromContext(ctx).Info().Str("input-sqlite-file", sqliteFile).Str("snapshot-file", snapshotFile).
+		Msg("Successfully took snapshot of input file")
+
 	// Initiate database connection
-	dbConn, err = sqlite.NewDB(placesDBFile)
+	dbConn, err := sqlite.NewDB(snapshotFile)
 	if err != nil {
 		// When initialization of database connection failed
-		logger.Fatal().Err(err).Str("db-filename", placesDBFile).Msg("Failed to open DB connection")
+		return nil, fmt.Errorf("failed to open DB connection to %q: %v", snapshotFile, err)
 	}
 
-	// Initiate database operator
-	dbOps = db.NewDatabaseOperator(dbConn)
+	return dbConn, nil
+}
+
+// fetchBookmarks fetches the bookmarks from input and applies the filters enabled in input flags.
+// The link filters request the links with the checker.
+func fetchBookmarks(ctx context.Context, dbOps db.BookmarkOperator, inputFlags *flags.Flags,
+	checker *links.Checker) ([]bookmark.Bookmark, error) {
+	var (
+		err                                        error
+		denormalizeOps, ignoredefaultsOps, tagsOps filters.Filter
+		dedupeOps, deadLinksOps, redirectsOps      filters.Filter
+		whereOps, foldersOps                       filters.Filter
+		bookmarks                                  []bookmark.Bookmark
+
+		logger = logs.FromContext(ctx)
+	)
+
 	bookmarks, err = dbOps.GetBookmarks(ctx)
 	if err != nil {
-		// When initialization of database operator failed
-		logger.Fatal().Err(err).Msg("Failed to fetch bookmarks from db")
+		// When fetching bookmarks from input failed
+		return nil, fmt.Errorf("failed to fetch bookmarks from input: %v", err)
 	}
 
 	// When initialization of database operator was successful
 	logger.Info().Int("count", len(bookmarks)).Msg("Count of bookmarks fetched")
 
 	// Filters
+	if inputFlags.FilterTags {
+		// When tags filter is enabled
+		tagsOps = &tags.TagResolver{}
+	}
 	if inputFlags.FilterDenormalize {
 		// When denormalize filter is enabled
 		denormalizeOps = &denormalize.Denormalizer{}
 	}
+	if inputFlags.Where != "" {
+		// When where filter is enabled
+		if whereOps, err = where.NewFilter(inputFlags.Where); err != nil {
+			// When the expression is invalid
+			return nil, fmt.Errorf("failed to initiate where filter: %v", err)
+		}
+	}
+	if len(inputFlags.IncludeFolders) > 0 || len(inputFlags.ExcludeFolders) > 0 {
+		// When folders filter is enabled
+		if foldersOps, err = folders.NewFolderFilter(inputFlags.IncludeFolders, inputFlags.ExcludeFolders); err != nil {
+			// When any of the patterns is invalid
+			return nil, fmt.Errorf("failed to initiate folders filter: %v", err)
+		}
+	}
+	if inputFlags.FilterDedupe {
+		// When dedupe filter is enabled
+		dedupeOps = dedupe.NewDeduplicator(inputFlags.DedupePolicy, inputFlags.DedupeIgnoreFragment)
+	}
+	if inputFlags.FilterDeadLinks {
+		// When dead-links filter is enabled
+		deadLinksOps = deadlinks.NewDeadLinkRemover(checker)
+	}
+	if inputFlags.FilterRedirects {
+		// When redirects filter is enabled
+		redirectsOps = redirects.NewRedirectRewriter(checker)
+	}
 	if inputFlags.FilterIgnoreDefaults {
 		// When ignore-defaults filter is enabled
 		ignoredefaultsOps = &ignoredefaults.DefaultsRemover{}
@@ -121,58 +554,124 @@ func main() {
 	// Filter the resultant bookmarks
 	bookmarks, err = filters.NewFilterManager().
 		Bookmarks(bookmarks).
+		Filter(tagsOps).    // Tags are resolved ahead of denormalization, which treats tag folders as folders
+		Filter(foldersOps). // Folders are filtered ahead of denormalization, so fewer records are denormalized
 		Filter(denormalizeOps).
 		Filter(ignoredefaultsOps).
+		Filter(whereOps).     // Expression is applied ahead of checking links, so only the kept links are requested
+		Filter(deadLinksOps). // Dead links are dropped ahead of rewriting redirects and deduplication,
+		Filter(redirectsOps). // so the rewritten URLs are deduplicated with the existing bookmarks of them
+		Filter(dedupeOps).
 		Apply(ctx)
 	if err != nil {
 		// When filtering failed
-		logger.Fatal().Err(err).Msg("Failed to apply filter(s)")
+		return nil, fmt.Errorf("failed to apply filter(s): %v", err)
 	}
 
-	// Initialize encoder manager and add bookmarks to manager
-	encoderManager = pkgEncoding.NewEncoderManager(ctx).Bookmarks(bookmarks)
+	return bookmarks, nil
+}
 
-	if inputFlags.StdOutFormat != nil {
-		// When stdout printer is also enabled
-		encoderManager = encoderManager.Encoder(inputFlags.StdOutFormat)
+// streamable reports whether the bookmarks can be streamed from input to outputs, i.e., when none of
+// the filters, which need all the bookmarks at once, is enabled, and all the outputs are of stream encoders
+func streamable(inputFlags *flags.Flags) bool {
+	if inputFlags.Mode != constants.BookmarksMode || inputFlags.FilterTags || inputFlags.FilterDenormalize ||
+		inputFlags.FilterIgnoreDefaults || inputFlags.FilterDedupe || inputFlags.FilterDeadLinks ||
+		inputFlags.FilterRedirects || inputFlags.Where != "" || len(inputFlags.IncludeFolders) > 0 ||
+		len(inputFlags.ExcludeFolders) > 0 {
+		return false
 	}
 
-	// Iterate over list of input format-filename flag value sets
-	for _, outputFileSet = range inputFlags.OutputFiles {
-		// Create output file
-		outputFile, err = fileOps.Open(outputFileSet.Filename)
-		if err != nil {
-			// When creation of output file failed
-			logger.Fatal().Err(err).Str("output-filename", outputFileSet.Filename).
-				Stringer("output-format", outputFileSet.Format).Msg("Failed to create output file")
+	if inputFlags.StdOutFormat != nil {
+		if _, ok := inputFlags.StdOutFormat.(pkgEncoding.StreamEncoder); !ok {
+			// When stdout format needs all the bookmarks at once
+			return false
 		}
+	}
 
-		// Map output file format against the encoder type
-		switch outputFileSet.Format {
-		case constants.CSVFormat:
-			// CSV format
-			encoder = pkgEncodingCSV.NewEncoder(outputFile, enableHeader)
-		case constants.JSONFormat:
-			// JSON format
-			encoder = pkgEncodingJSON.NewEncoder(outputFile)
-		case constants.TabularFormat:
-			// Table format
-			encoder = pkgEncodingTab.NewEncoder(outputFile, enableHeader)
-		case constants.YAMLFormat:
-			// YAML format
-			encoder = pkgEncodingYAML.NewEncoder(outputFile)
-		default:
-			// Input format is already validated at input flags
+	for _, output := range inputFlags.OutputFiles {
+		if output.Format != constants.JSONLFormat {
+			// When the output format needs all the bookmarks at once
+			return false
 		}
+	}
 
-		// Append the encoder to the list in manager
-		encoderManager = encoderManager.Encoder(encoder)
+	return true
+}
+
+// frontMatter returns the front matter of the fields, with the profile name and the date of export
+func frontMatter(fields flags.FrontMatterFields, profile string, now time.Time) pkgEncoding.FrontMatter {
+	var result pkgEncoding.FrontMatter
+
+	if fields.Date {
+		result.Date = now
 	}
 
-	// Encode bookmarks against all output formats (stdout or file formats)
-	err = encoderManager.Write()
+	if fields.Profile {
+		result.Profile = profile
+	}
+
+	return result
+}
+
+// profileRoots returns the directories to discover profiles from,
+// the root in input flags being ahead of the standard locations
+func profileRoots(ctx context.Context, profilesRoot string) []string {
+	var roots []string
+
+	if profilesRoot != "" {
+		roots = append(roots, profilesRoot)
+	}
+
+	home, err := os.UserHomeDir()
 	if err != nil {
-		// When encoding bookmarks failed
-		logger.Fatal().Err(err).Msg("Failed to encode bookmarks to output stream(s)")
+		// When home directory is unknown, only the root in input flags is searched
+		logs.FromContext(ctx).Warn().Err(err).Msg("Failed to find home directory for profiles")
+		return roots
+	}
+
+	return append(roots, profiles.DefaultRoots(home)...)
+}
+
+// findProfile finds the profile by name in the root in input flags and the standard locations
+func findProfile(ctx context.Context, profilesRoot, name string) profiles.Profile {
+	profile, err := profiles.NewOperator(profileRoots(ctx, profilesRoot)...).Find(name)
+	if err != nil {
+		// When the profile is not found
+		logs.FromContext(ctx).Fatal().Err(err).Str("profile", name).Msg("Failed to find profile")
+	}
+
+	return profile
+}
+
+// listProfiles prints the discovered profiles on stdout as table
+func listProfiles(ctx context.Context, inputFlags *flags.Flags) {
+	var (
+		isDefault string
+		line      string
+		table     = [][]string{{"name", "default", "path"}}
+	)
+
+	list, err := profiles.NewOperator(profileRoots(ctx, inputFlags.ProfilesRoot)...).List()
+	if err != nil {
+		// When discovery of profiles failed
+		logs.FromContext(ctx).Fatal().Err(err).Msg("Failed to list profiles")
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No Firefox profiles found")
+		return
+	}
+
+	for _, profile := range list {
+		isDefault = ""
+		if profile.IsDefault {
+			isDefault = "yes"
+		}
+
+		table = append(table, []string{profile.Name, isDefault, profile.Path})
+	}
+
+	for _, line = range pkgText.Table(table, true, "") {
+		fmt.Println(line)
 	}
 }
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	pkgEncoding "github.com/vaguecoder/firefox-backups/pkg/encoding"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+	"github.com/vaguecoder/firefox-backups/pkg/filters/tags"
+	"github.com/vaguecoder/firefox-backups/pkg/flags"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/merge"
+)
+
+// sourcePlacesDBFile is the name format of the snapshots of places.sqlite sources in workspace
+const sourcePlacesDBFile = `source-%d-places.sqlite`
+
+// mergeBookmarks runs the merge command, i.e., combines the bookmarks of the sources
+// into one tree, and encodes it to stdout and output files
+func mergeBookmarks(ctx context.Context, args []string) {
+	var (
+		err        error
+		logger     = logs.FromContext(ctx)
+		mergeFlags *flags.MergeFlags
+	)
+
+	// Read the input flags of merge command
+	mergeFlags, err = flags.NewOperator(args).ParseMerge()
+	if err != nil {
+		logger.Fatal().Err(err).Strs("args", args).Msg("Failed to parse flags from command line args")
+	}
+
+	// When silent mode is enabled in input flags, replace logger with silent logger.
+	if mergeFlags.Silent {
+		ctx, logger = logs.SilentLogger(ctx)
+	}
+
+	// Log input flag values
+	logger.Info().Interface("flags", mergeFlags).Msg("Input flags")
+
+	if err = runMerge(ctx, *mergeFlags, files.NewOperator(ctx), time.Now()); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to merge bookmarks")
+	}
+}
+
+// runMerge reads the bookmarks of all the sources, merges them as per conflict policy,
+// and encodes the merged tree to stdout and output files
+func runMerge(ctx context.Context, mergeFlags flags.MergeFlags, fileOps files.FileOperator,
+	now time.Time) (err error) {
+	var (
+		workspace   *files.Workspace
+		sourceBooks []bookmark.Bookmark
+		merged      []bookmark.Bookmark
+		result      merge.Result
+		outputFiles []files.File
+		encoders    []pkgEncoding.Encoder
+
+		merger = merge.NewMerger(ctx, mergeFlags.ConflictPolicy)
+		logger = logs.FromContext(ctx)
+	)
+
+	for _, source := range mergeFlags.Sources {
+		if source.Format == constants.SQLiteFormat {
+			// When any of the sources is places.sqlite, it is snapshot in workspace
+			if workspace, err = files.NewWorkspace(ctx); err != nil {
+				// When creation of workspace failed
+				return err
+			}
+
+			break
+		}
+	}
+
+	if workspace != nil {
+		// Delete the workspace after completion or failure
+		defer func() {
+			if closeErr := workspace.Close(); closeErr != nil && err == nil {
+				// When deletion of workspace failed
+				err = closeErr
+			}
+		}()
+	}
+
+	for i, source := range mergeFlags.Sources {
+		sourceBooks, err = readSource(ctx, workspace, source, fmt.Sprintf(sourcePlacesDBFile, i+1))
+		if err != nil {
+			return fmt.Errorf("failed to read --%s %s: %v", constants.SourceFlag, source.String(), err)
+		}
+
+		logger.Info().Stringer("source", &source).Int("count", len(sourceBooks)).Msg("Count of bookmarks read")
+
+		// Tags of places.sqlite are folded onto the bookmarks, so they are merged along with the bookmarks
+		if sourceBooks, err = (&tags.TagResolver{}).Apply(ctx, sourceBooks); err != nil {
+			return fmt.Errorf("failed to resolve tags of --%s %s: %v", constants.SourceFlag, source.String(), err)
+		}
+
+		merger = merger.Source(sourceBooks)
+	}
+
+	if merged, result, err = merger.Merge(); err != nil {
+		return fmt.Errorf("failed to merge sources: %v", err)
+	}
+
+	logger.Info().Int("folders", result.Folders).Int("bookmarks", result.Bookmarks).
+		Int("duplicates", result.Duplicates).Int("conflicts", result.Conflicts).Msg("Merge summary")
+
+	// Close the output files after completion or failure
+	defer func() {
+		if closeErr := closeOutputs(outputFiles); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	// The merged bookmarks aren't of a profile, so only date template is expanded in output filenames
+	outputFiles, encoders, err = openOutputs(fileOps, mergeFlags.OutputFiles, flags.FrontMatterFields{}, nil, "", now)
+	if err != nil {
+		return err
+	}
+
+	encoderManager := pkgEncoding.NewEncoderManager(ctx).Bookmarks(merged)
+	if mergeFlags.StdOutFormat != nil {
+		// When stdout printer is also enabled
+		encoderManager = encoderManager.Encoder(mergeFlags.StdOutFormat)
+	}
+
+	for _, encoder := range encoders {
+		encoderManager = encoderManager.Encoder(encoder)
+	}
+
+	if err = encoderManager.Write(); err != nil {
+		// When encoding bookmarks failed
+		return fmt.Errorf("failed to encode to output stream(s): %v", err)
+	}
+
+	return nil
+}
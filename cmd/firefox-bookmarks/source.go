@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	db "github.com/vaguecoder/firefox-backups/pkg/database"
+	"github.com/vaguecoder/firefox-backups/pkg/database/jsonlz4"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+	"github.com/vaguecoder/firefox-backups/pkg/flags"
+)
+
+// readSource reads the bookmarks from the source of diff and merge commands, as they are stored,
+// i.e., without filters. The places.sqlite sources are read from their snapshots in workspace.
+func readSource(ctx context.Context, workspace *files.Workspace, source flags.Source,
+	snapshotName string) ([]bookmark.Bookmark, error) {
+	switch source.Format {
+	case constants.SQLiteFormat:
+		dbConn, err := openSnapshot(ctx, workspace, source.Filename, snapshotName)
+		if err != nil {
+			return nil, err
+		}
+		defer dbConn.Close()
+
+		return db.NewDatabaseOperator(dbConn).GetBookmarks(ctx)
+	case constants.JSONLZ4Format:
+		return jsonlz4.NewBackupOperator(source.Filename).GetBookmarks(ctx)
+	default:
+		// Exports of this tool, read with decoders
+		return readBookmarks(source.InputFile())
+	}
+}
@@ -0,0 +1,239 @@
+package bookmark
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+)
+
+const (
+	// Types of nodes in Firefox's bookmarks backup, against the type codes.
+	// Type codes are same as the types of bookmark records in places.sqlite.
+	backupTypeBookmark  = `text/x-moz-place`
+	backupTypeFolder    = `text/x-moz-place-container`
+	backupTypeSeparator = `text/x-moz-place-separator`
+
+	// Root name of places root in Firefox's bookmarks backup. Its GUID is PlacesRootGUID.
+	backupPlacesRoot = `placesRoot`
+)
+
+// backupRootNames maps the titles of Firefox built-in root folders
+// to their root names in the backup. Their GUIDs are in RootGUIDs.
+var backupRootNames = map[string]string{
+	MenuRoot:    "bookmarksMenuFolder",
+	ToolbarRoot: "toolbarFolder",
+	TagsRoot:    "tagsFolder",
+	UnfiledRoot: "unfiledBookmarksFolder",
+	MobileRoot:  "mobileFolder",
+}
+
+// BackupNode is a node of the JSON tree in Firefox's bookmarks backup,
+// i.e., the content of bookmarkbackups/*.jsonlz4 files, and the file
+// written by Library > Import and Backup > Backup...
+type BackupNode struct {
+	GUID         string       `json:"guid"`
+	Title        string       `json:"title"`
+	Index        int          `json:"index"`
+	DateAdded    int64        `json:"dateAdded"`
+	LastModified int64        `json:"lastModified"`
+	ID           int          `json:"id"`
+	TypeCode     int          `json:"typeCode"`
+	Type         string       `json:"type"`
+	Root         string       `json:"root,omitempty"`
+	URI          string       `json:"uri,omitempty"`
+	Keyword      string       `json:"keyword,omitempty"`
+	Tags         string       `json:"tags,omitempty"`
+	Children     []BackupNode `json:"children,omitempty"`
+}
+
+// Bookmarks flattens the backup tree to the list of bookmark records,
+// same as the records in places.sqlite, i.e., folders and separators
+// without URL, and parent holding the ID of containing folder.
+func (n BackupNode) Bookmarks() []Bookmark {
+	return n.flatten(0)
+}
+
+// flatten appends the node and its children recursively to the list
+func (n BackupNode) flatten(parent int) []Bookmark {
+	var (
+		child BackupNode
+		b     = Bookmark{
+			Title:        n.Title,
+			ID:           n.ID,
+			Parent:       parent,
+			GUID:         n.GUID,
+			Type:         n.TypeCode,
+			Position:     n.Index,
+			Keyword:      n.Keyword,
+			DateAdded:    FromPRTime(n.DateAdded),
+			LastModified: FromPRTime(n.LastModified),
+		}
+	)
+
+	if n.TypeCode == TypeBookmark {
+		// Only bookmarks have URL. Folders & separators have nil URL.
+		uri := n.URI
+		b.URL = &uri
+	}
+
+	if n.Tags != "" {
+		// Tags are comma-separated in backup, instead of the tag folders in places.sqlite
+		b.Tags = strings.Split(n.Tags, tagsSeparator)
+	}
+
+	bookmarks := []Bookmark{b}
+	for _, child = range n.Children {
+		bookmarks = append(bookmarks, child.flatten(n.ID)...)
+	}
+
+	return bookmarks
+}
+
+// BackupTree builds Firefox's bookmarks backup tree from the bookmark records,
+// based on the folder hierarchy rebuilt from ID and Parent fields.
+// The records missing their built-in root folders, such as filtered records,
+// are placed in the unfiled root, so the tree is always restorable in Firefox.
+func BackupTree(bookmarks []Bookmark) BackupNode {
+	var (
+		node    *Node
+		child   BackupNode
+		unfiled *BackupNode
+		orphans []BackupNode
+
+		root = BackupNode{
+			GUID:     PlacesRootGUID,
+			TypeCode: TypeFolder,
+			Type:     backupTypeFolder,
+			Root:     backupPlacesRoot,
+		}
+		rootChildren = []*Node{}
+	)
+
+	for _, node = range Tree(bookmarks) {
+		switch {
+		case node.IsPlacesRoot():
+			// The built-in root folders are children of places root
+			root.ID = node.ID
+			root.DateAdded = ToPRTime(node.DateAdded)
+			root.LastModified = ToPRTime(node.LastModified)
+			rootChildren = append(rootChildren, node.Children...)
+		case isBackupRoot(node):
+			// The built-in root folder whose places root is filtered
+			rootChildren = append(rootChildren, node)
+		default:
+			orphans = append(orphans, backupNode(node, false))
+		}
+	}
+
+	for _, node = range rootChildren {
+		child = backupNode(node, true)
+		child.Index = len(root.Children)
+		root.Children = append(root.Children, child)
+	}
+
+	if len(orphans) == 0 {
+		// When there are no records outside built-in root folders
+		return root
+	}
+
+	for index := range root.Children {
+		if root.Children[index].Root == backupRootNames[UnfiledRoot] {
+			unfiled = &root.Children[index]
+		}
+	}
+
+	if unfiled == nil {
+		// When unfiled root is missing in the records
+		root.Children = append(root.Children, BackupNode{
+			GUID:     RootGUIDs[UnfiledRoot],
+			Title:    UnfiledRoot,
+			Index:    len(root.Children),
+			TypeCode: TypeFolder,
+			Type:     backupTypeFolder,
+			Root:     backupRootNames[UnfiledRoot],
+		})
+		unfiled = &root.Children[len(root.Children)-1]
+	}
+
+	for _, child = range orphans {
+		child.Index = len(unfiled.Children)
+		unfiled.Children = append(unfiled.Children, child)
+	}
+
+	return root
+}
+
+// isBackupRoot reports whether the node is one of the Firefox built-in root folders
+func isBackupRoot(node *Node) bool {
+	_, ok := backupRootNames[node.Title]
+	return ok && node.IsFolder()
+}
+
+// backupNode converts the node and its children recursively to backup nodes.
+// isRoot is set when the node is a direct child of places root.
+func backupNode(node *Node, isRoot bool) BackupNode {
+	var (
+		rootName string
+		ok       bool
+		child    BackupNode
+
+		result = BackupNode{
+			GUID:         node.GUID,
+			Title:        node.Title,
+			DateAdded:    ToPRTime(node.DateAdded),
+			LastModified: ToPRTime(node.LastModified),
+			ID:           node.ID,
+		}
+	)
+
+	if result.GUID == "" {
+		// When the record has no GUID, such as in older exports
+		result.GUID = backupGUID(node.ID)
+	}
+
+	switch {
+	case node.IsSeparator():
+		result.TypeCode = TypeSeparator
+		result.Type = backupTypeSeparator
+
+		return result
+	case !node.IsFolder():
+		// When the node is a bookmark
+		result.TypeCode = TypeBookmark
+		result.Type = backupTypeBookmark
+		result.Keyword = node.Keyword
+		result.Tags = strings.Join(node.Tags, tagsSeparator)
+		if node.URL != nil {
+			result.URI = *node.URL
+		}
+
+		return result
+	}
+
+	result.TypeCode = TypeFolder
+	result.Type = backupTypeFolder
+
+	if rootName, ok = backupRootNames[node.Title]; ok && isRoot {
+		// When the folder is a built-in root folder
+		result.GUID = RootGUIDs[node.Title]
+		result.Root = rootName
+	}
+
+	for _, childNode := range node.Children {
+		child = backupNode(childNode, false)
+		child.Index = len(result.Children)
+		result.Children = append(result.Children, child)
+	}
+
+	return result
+}
+
+// backupGUID derives a GUID in Firefox's format, i.e., 12 characters
+// of URL-safe base64, from the bookmark ID
+func backupGUID(id int) string {
+	var raw [9]byte
+
+	binary.BigEndian.PutUint64(raw[1:], uint64(id))
+
+	return base64.RawURLEncoding.EncodeToString(raw[:])
+}
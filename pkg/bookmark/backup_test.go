@@ -0,0 +1,209 @@
+package bookmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+func TestBackupNode_Bookmarks(t *testing.T) {
+	var ptrStr = util.PtrStr
+
+	tests := []struct {
+		name string
+		root BackupNode
+		want []Bookmark
+	}{
+		{
+			name: "Empty-Root",
+			root: BackupNode{ID: 1, TypeCode: TypeFolder, Root: "placesRoot"},
+			want: []Bookmark{
+				{URL: nil, Title: "", ID: 1, Parent: 0, Type: TypeFolder},
+			},
+		},
+		{
+			name: "Nested-Folders",
+			root: BackupNode{
+				ID:       1,
+				TypeCode: TypeFolder,
+				Root:     "placesRoot",
+				Children: []BackupNode{
+					{
+						ID:       2,
+						Title:    "menu",
+						TypeCode: TypeFolder,
+						Root:     "bookmarksMenuFolder",
+						Children: []BackupNode{
+							{
+								ID:       6,
+								Title:    "GitHub",
+								TypeCode: TypeFolder,
+								Children: []BackupNode{
+									{
+										GUID:      "8rLXK7Gl2ayV",
+										ID:        7,
+										Title:     "Vague Coder",
+										DateAdded: 1677929826000000,
+										TypeCode:  TypeBookmark,
+										URI:       "https://github.com/vaguecoder",
+										Keyword:   "vc",
+										Tags:      "go,vcs",
+									},
+									{ID: 8, Index: 1, TypeCode: TypeSeparator},
+								},
+							},
+						},
+					},
+					{ID: 3, Title: "toolbar", TypeCode: TypeFolder, Root: "toolbarFolder"},
+				},
+			},
+			want: []Bookmark{
+				{URL: nil, Title: "", ID: 1, Parent: 0, Type: TypeFolder},
+				{URL: nil, Title: "menu", ID: 2, Parent: 1, Type: TypeFolder},
+				{URL: nil, Title: "GitHub", ID: 6, Parent: 2, Type: TypeFolder},
+				{
+					URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 7, Parent: 6,
+					GUID: "8rLXK7Gl2ayV", Type: TypeBookmark, Keyword: "vc", Tags: []string{"go", "vcs"},
+					DateAdded: time.Date(2023, 3, 4, 11, 37, 6, 0, time.UTC),
+				},
+				{URL: nil, Title: "", ID: 8, Parent: 6, Type: TypeSeparator, Position: 1},
+				{URL: nil, Title: "toolbar", ID: 3, Parent: 1, Type: TypeFolder},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.root.Bookmarks(), "Mismatch of flattened bookmarks")
+		})
+	}
+}
+
+func TestBackupTree(t *testing.T) {
+	var ptrStr = util.PtrStr
+
+	tests := []struct {
+		name      string
+		bookmarks []Bookmark
+		want      BackupNode
+	}{
+		{
+			name:      "Empty-Bookmarks",
+			bookmarks: []Bookmark{},
+			want: BackupNode{
+				GUID:     "root________",
+				TypeCode: TypeFolder,
+				Type:     "text/x-moz-place-container",
+				Root:     "placesRoot",
+			},
+		},
+		{
+			name: "Places-Roots",
+			bookmarks: []Bookmark{
+				{URL: nil, Title: "", ID: 1, Parent: 0},
+				{URL: nil, Title: "menu", ID: 2, Parent: 1},
+				{URL: nil, Title: "toolbar", ID: 3, Parent: 1},
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 6, Parent: 3, Tags: []string{"go", "vcs"}},
+				{URL: nil, Title: "menu", ID: 7, Parent: 2},
+			},
+			want: BackupNode{
+				GUID:     "root________",
+				ID:       1,
+				TypeCode: TypeFolder,
+				Type:     "text/x-moz-place-container",
+				Root:     "placesRoot",
+				Children: []BackupNode{
+					{
+						GUID:     "menu________",
+						Title:    "menu",
+						Index:    0,
+						ID:       2,
+						TypeCode: TypeFolder,
+						Type:     "text/x-moz-place-container",
+						Root:     "bookmarksMenuFolder",
+						Children: []BackupNode{
+							{
+								GUID:     "AAAAAAAAAAAH",
+								Title:    "menu",
+								Index:    0,
+								ID:       7,
+								TypeCode: TypeFolder,
+								Type:     "text/x-moz-place-container",
+							},
+						},
+					},
+					{
+						GUID:     "toolbar_____",
+						Title:    "toolbar",
+						Index:    1,
+						ID:       3,
+						TypeCode: TypeFolder,
+						Type:     "text/x-moz-place-container",
+						Root:     "toolbarFolder",
+						Children: []BackupNode{
+							{
+								GUID:     "AAAAAAAAAAAG",
+								Title:    "Vague Coder",
+								Index:    0,
+								ID:       6,
+								TypeCode: TypeBookmark,
+								Type:     "text/x-moz-place",
+								URI:      "https://github.com/vaguecoder",
+								Tags:     "go,vcs",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Orphans-In-Unfiled",
+			bookmarks: []Bookmark{
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 6, Parent: 3},
+				{URL: ptrStr("https://github.com/random"), Title: "Random", ID: 8, Parent: 5},
+			},
+			want: BackupNode{
+				GUID:     "root________",
+				TypeCode: TypeFolder,
+				Type:     "text/x-moz-place-container",
+				Root:     "placesRoot",
+				Children: []BackupNode{
+					{
+						GUID:     "unfiled_____",
+						Title:    "unfiled",
+						Index:    0,
+						TypeCode: TypeFolder,
+						Type:     "text/x-moz-place-container",
+						Root:     "unfiledBookmarksFolder",
+						Children: []BackupNode{
+							{
+								GUID:     "AAAAAAAAAAAG",
+								Title:    "Vague Coder",
+								Index:    0,
+								ID:       6,
+								TypeCode: TypeBookmark,
+								Type:     "text/x-moz-place",
+								URI:      "https://github.com/vaguecoder",
+							},
+							{
+								GUID:     "AAAAAAAAAAAI",
+								Title:    "Random",
+								Index:    1,
+								ID:       8,
+								TypeCode: TypeBookmark,
+								Type:     "text/x-moz-place",
+								URI:      "https://github.com/random",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BackupTree(tt.bookmarks), "Mismatch of backup tree")
+		})
+	}
+}
@@ -2,15 +2,80 @@ package bookmark
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
+const (
+	// Types of bookmark records in places.sqlite
+	TypeBookmark  = 1
+	TypeFolder    = 2
+	TypeSeparator = 3
+
+	// timeFormat is the format of timestamps in bookmarks table,
+	// with the fractional seconds so the table can be read back without loss
+	timeFormat = time.RFC3339Nano
+
+	// tagsSeparator is the separator of tags in bookmarks table
+	tagsSeparator = ","
+)
+
+// TableHeader is the header of bookmarks table based on field order, uppercased
+var TableHeader = []string{"URL", "TITLE", "FOLDER", "ID", "PARENT", "GUID", "PLACE-GUID",
+	"TYPE", "POSITION", "KEYWORD", "DATE-ADDED", "LAST-MODIFIED", "TAGS"}
+
 type Bookmark struct {
-	URL    *string `json:"url" yaml:"url"`
-	Title  string  `json:"title" yaml:"title"`
-	Folder string  `json:"folder" yaml:"folder"`
-	ID     int     `json:"id" yaml:"id"`
-	Parent int     `json:"parent" yaml:"parent"`
+	URL          *string   `json:"url" yaml:"url"`
+	Title        string    `json:"title" yaml:"title"`
+	Folder       string    `json:"folder" yaml:"folder"`
+	ID           int       `json:"id" yaml:"id"`
+	Parent       int       `json:"parent" yaml:"parent"`
+	GUID         string    `json:"guid" yaml:"guid"`
+	PlaceGUID    string    `json:"place-guid" yaml:"place-guid"`
+	Type         int       `json:"type" yaml:"type"`
+	Position     int       `json:"position" yaml:"position"`
+	Keyword      string    `json:"keyword" yaml:"keyword"`
+	DateAdded    time.Time `json:"date-added" yaml:"date-added"`
+	LastModified time.Time `json:"last-modified" yaml:"last-modified"`
+	Tags         []string  `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// FromPRTime converts Firefox's PRTime, i.e., microseconds since epoch, to time.
+// Zero PRTime is converted to zero time, as it denotes missing timestamp.
+func FromPRTime(prTime int64) time.Time {
+	if prTime == 0 {
+		return time.Time{}
+	}
+
+	return time.UnixMicro(prTime).UTC()
+}
+
+// ToPRTime converts time to Firefox's PRTime, i.e., microseconds since epoch.
+// Zero time is converted to zero PRTime.
+func ToPRTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixMicro()
+}
+
+// UnionTags returns the tags of both the lists, in their order, without duplicates
+func UnionTags(tags, other []string) []string {
+	var (
+		union []string
+		seen  = map[string]bool{}
+	)
+
+	for _, tag := range append(append([]string{}, tags...), other...) {
+		if !seen[tag] {
+			seen[tag] = true
+			union = append(union, tag)
+		}
+	}
+
+	return union
 }
 
 // BookmarksTable parses the bookmarks data to 2D string table
@@ -23,15 +88,12 @@ func BookmarksTable(bookmarks []Bookmark, enableHeader bool) [][]string {
 	var (
 		sheet [][]string
 		url   string
-
-		// Header for table based on field order, uppercased
-		header = []string{"URL", "TITLE", "FOLDER", "ID", "PARENT"}
 	)
 
 	if enableHeader {
 		// When header toggle is enabled
-		sheet = append(sheet, header)
-		sheet = append(sheet, headerUnderline(header))
+		sheet = append(sheet, TableHeader)
+		sheet = append(sheet, headerUnderline(TableHeader))
 	}
 
 	for _, b := range bookmarks {
@@ -45,17 +107,185 @@ func BookmarksTable(bookmarks []Bookmark, enableHeader bool) [][]string {
 
 		// Append record to result
 		sheet = append(sheet, []string{
-			trimSpace(url),       // Trim leading and trailing whitespace from URL
-			trimSpace(b.Title),   // Trim leading and trailing whitespace from title
-			trimSpace(b.Folder),  // Trim leading and trailing whitespace from folder name
-			fmt.Sprint(b.ID),     // No whitespace trimming required for ID as int is parsed as string
-			fmt.Sprint(b.Parent), // No whitespace trimming required for parent ID as int is parsed as string
+			trimSpace(url),                      // Trim leading and trailing whitespace from URL
+			trimSpace(b.Title),                  // Trim leading and trailing whitespace from title
+			trimSpace(b.Folder),                 // Trim leading and trailing whitespace from folder name
+			fmt.Sprint(b.ID),                    // No whitespace trimming required for ID as int is parsed as string
+			fmt.Sprint(b.Parent),                // No whitespace trimming required for parent ID as int is parsed as string
+			b.GUID,                              // No whitespace trimming required for GUID as it has no whitespace
+			b.PlaceGUID,                         // No whitespace trimming required for place GUID as it has no whitespace
+			fmt.Sprint(b.Type),                  // No whitespace trimming required for type as int is parsed as string
+			fmt.Sprint(b.Position),              // No whitespace trimming required for position as int is parsed as string
+			trimSpace(b.Keyword),                // Trim leading and trailing whitespace from keyword
+			formatTime(b.DateAdded),             // Formatted timestamp of addition, blank if missing
+			formatTime(b.LastModified),          // Formatted timestamp of last modification, blank if missing
+			strings.Join(b.Tags, tagsSeparator), // Tags joined with separator, blank if untagged
 		})
 	}
 
 	return sheet
 }
 
+// FromTable parses the 2D string table back to bookmarks data, i.e., reverse of BookmarksTable.
+// When the first row is the header, the columns are mapped by their titles in any order,
+// and the header underline following it is skipped. Otherwise, the columns are expected
+// in the order of TableHeader.
+func FromTable(sheet [][]string) ([]Bookmark, error) {
+	if len(sheet) == 0 {
+		// When no records
+		return nil, nil
+	}
+
+	columns, hasHeader := tableColumns(sheet[0])
+	if hasHeader {
+		sheet = sheet[1:]
+
+		if len(sheet) > 0 && isHeaderUnderline(sheet[0]) {
+			// When header is followed by underline
+			sheet = sheet[1:]
+		}
+	}
+
+	bookmarks := make([]Bookmark, 0, len(sheet))
+
+	for i, record := range sheet {
+		b, err := fromRecord(record, columns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse record %d of bookmarks table: %v", i+1, err)
+		}
+
+		bookmarks = append(bookmarks, b)
+	}
+
+	return bookmarks, nil
+}
+
+// tableColumns maps the header titles to column indices. When the row isn't a header,
+// the columns default to the order of TableHeader.
+func tableColumns(row []string) (map[string]int, bool) {
+	known := map[string]bool{}
+	for _, title := range TableHeader {
+		known[title] = true
+	}
+
+	columns := map[string]int{}
+	for i, cell := range row {
+		title := strings.ToUpper(trimSpace(cell))
+		if !known[title] {
+			// When any cell isn't a header title, the row is a record
+			columns = map[string]int{}
+			break
+		}
+
+		columns[title] = i
+	}
+
+	if len(columns) > 0 {
+		return columns, true
+	}
+
+	for i, title := range TableHeader {
+		columns[title] = i
+	}
+
+	return columns, false
+}
+
+// isHeaderUnderline reports whether the row is made of dashes only
+func isHeaderUnderline(row []string) bool {
+	for _, cell := range row {
+		cell = trimSpace(cell)
+		if cell == "" || strings.Trim(cell, "-") != "" {
+			return false
+		}
+	}
+
+	return len(row) > 0
+}
+
+// fromRecord parses a record of bookmarks table with the column indices
+func fromRecord(record []string, columns map[string]int) (Bookmark, error) {
+	var (
+		b   Bookmark
+		err error
+	)
+
+	cell := func(title string) string {
+		i, ok := columns[title]
+		if !ok || i >= len(record) {
+			// When the column is missing in header or record
+			return ""
+		}
+
+		return trimSpace(record[i])
+	}
+
+	if url := cell("URL"); url != "" {
+		// Empty URL is of folders and separators
+		b.URL = &url
+	}
+
+	b.Title = cell("TITLE")
+	b.Folder = cell("FOLDER")
+	b.GUID = cell("GUID")
+	b.PlaceGUID = cell("PLACE-GUID")
+	b.Keyword = cell("KEYWORD")
+
+	for title, field := range map[string]*int{
+		"ID":       &b.ID,
+		"PARENT":   &b.Parent,
+		"TYPE":     &b.Type,
+		"POSITION": &b.Position,
+	} {
+		if *field, err = parseInt(cell(title)); err != nil {
+			return Bookmark{}, fmt.Errorf("invalid %s: %v", title, err)
+		}
+	}
+
+	for title, field := range map[string]*time.Time{
+		"DATE-ADDED":    &b.DateAdded,
+		"LAST-MODIFIED": &b.LastModified,
+	} {
+		if *field, err = parseTime(cell(title)); err != nil {
+			return Bookmark{}, fmt.Errorf("invalid %s: %v", title, err)
+		}
+	}
+
+	if tags := cell("TAGS"); tags != "" {
+		// Blank tags denote untagged bookmark
+		b.Tags = strings.Split(tags, tagsSeparator)
+	}
+
+	return b, nil
+}
+
+// parseInt parses the integer from table, treating blank as zero
+func parseInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(s)
+}
+
+// parseTime parses the timestamp from table, treating blank as zero time
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(timeFormat, s)
+}
+
+// formatTime formats the timestamp for table, leaving zero time blank
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(timeFormat)
+}
+
 func trimSpace(s string) string {
 	return strings.TrimSpace(s)
 }
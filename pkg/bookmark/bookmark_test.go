@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/vaguecoder/firefox-backups/pkg/util"
 )
@@ -61,6 +62,14 @@ func TestBookmarksTable(t *testing.T) {
 					"",
 					"0",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -85,6 +94,14 @@ func TestBookmarksTable(t *testing.T) {
 					"FOLDER",
 					"ID",
 					"PARENT",
+					"GUID",
+					"PLACE-GUID",
+					"TYPE",
+					"POSITION",
+					"KEYWORD",
+					"DATE-ADDED",
+					"LAST-MODIFIED",
+					"TAGS",
 				},
 				{
 					"---",
@@ -92,6 +109,14 @@ func TestBookmarksTable(t *testing.T) {
 					"------",
 					"--",
 					"------",
+					"----",
+					"----------",
+					"----",
+					"--------",
+					"-------",
+					"----------",
+					"-------------",
+					"----",
 				},
 				{
 					"",
@@ -99,6 +124,14 @@ func TestBookmarksTable(t *testing.T) {
 					"",
 					"0",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -123,6 +156,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -147,6 +188,14 @@ func TestBookmarksTable(t *testing.T) {
 					"FOLDER",
 					"ID",
 					"PARENT",
+					"GUID",
+					"PLACE-GUID",
+					"TYPE",
+					"POSITION",
+					"KEYWORD",
+					"DATE-ADDED",
+					"LAST-MODIFIED",
+					"TAGS",
 				},
 				{
 					"---",
@@ -154,6 +203,14 @@ func TestBookmarksTable(t *testing.T) {
 					"------",
 					"--",
 					"------",
+					"----",
+					"----------",
+					"----",
+					"--------",
+					"-------",
+					"----------",
+					"-------------",
+					"----",
 				},
 				{
 					"https://github.com/vaguecoder",
@@ -161,6 +218,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -192,6 +257,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/random",
@@ -199,6 +272,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"2",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -230,6 +311,14 @@ func TestBookmarksTable(t *testing.T) {
 					"FOLDER",
 					"ID",
 					"PARENT",
+					"GUID",
+					"PLACE-GUID",
+					"TYPE",
+					"POSITION",
+					"KEYWORD",
+					"DATE-ADDED",
+					"LAST-MODIFIED",
+					"TAGS",
 				},
 				{
 					"---",
@@ -237,6 +326,14 @@ func TestBookmarksTable(t *testing.T) {
 					"------",
 					"--",
 					"------",
+					"----",
+					"----------",
+					"----",
+					"--------",
+					"-------",
+					"----------",
+					"-------------",
+					"----",
 				},
 				{
 					"https://github.com/vaguecoder",
@@ -244,6 +341,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/random",
@@ -251,6 +356,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"2",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -289,6 +402,14 @@ func TestBookmarksTable(t *testing.T) {
 					"",
 					"0",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/vaguecoder",
@@ -296,6 +417,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/random",
@@ -303,6 +432,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"2",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -341,6 +478,14 @@ func TestBookmarksTable(t *testing.T) {
 					"FOLDER",
 					"ID",
 					"PARENT",
+					"GUID",
+					"PLACE-GUID",
+					"TYPE",
+					"POSITION",
+					"KEYWORD",
+					"DATE-ADDED",
+					"LAST-MODIFIED",
+					"TAGS",
 				},
 				{
 					"---",
@@ -348,6 +493,14 @@ func TestBookmarksTable(t *testing.T) {
 					"------",
 					"--",
 					"------",
+					"----",
+					"----------",
+					"----",
+					"--------",
+					"-------",
+					"----------",
+					"-------------",
+					"----",
 				},
 				{
 					"",
@@ -355,6 +508,14 @@ func TestBookmarksTable(t *testing.T) {
 					"",
 					"0",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/vaguecoder",
@@ -362,6 +523,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/random",
@@ -369,6 +538,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"2",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -407,6 +584,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"",
@@ -414,6 +599,14 @@ func TestBookmarksTable(t *testing.T) {
 					"",
 					"0",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/random",
@@ -421,6 +614,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"2",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -459,6 +660,14 @@ func TestBookmarksTable(t *testing.T) {
 					"FOLDER",
 					"ID",
 					"PARENT",
+					"GUID",
+					"PLACE-GUID",
+					"TYPE",
+					"POSITION",
+					"KEYWORD",
+					"DATE-ADDED",
+					"LAST-MODIFIED",
+					"TAGS",
 				},
 				{
 					"---",
@@ -466,6 +675,14 @@ func TestBookmarksTable(t *testing.T) {
 					"------",
 					"--",
 					"------",
+					"----",
+					"----------",
+					"----",
+					"--------",
+					"-------",
+					"----------",
+					"-------------",
+					"----",
 				},
 				{
 					"https://github.com/vaguecoder",
@@ -473,6 +690,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"",
@@ -480,6 +705,14 @@ func TestBookmarksTable(t *testing.T) {
 					"",
 					"0",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/random",
@@ -487,6 +720,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"2",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -525,6 +766,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/random",
@@ -532,6 +781,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"2",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"",
@@ -539,6 +796,14 @@ func TestBookmarksTable(t *testing.T) {
 					"",
 					"0",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -577,6 +842,14 @@ func TestBookmarksTable(t *testing.T) {
 					"FOLDER",
 					"ID",
 					"PARENT",
+					"GUID",
+					"PLACE-GUID",
+					"TYPE",
+					"POSITION",
+					"KEYWORD",
+					"DATE-ADDED",
+					"LAST-MODIFIED",
+					"TAGS",
 				},
 				{
 					"---",
@@ -584,6 +857,14 @@ func TestBookmarksTable(t *testing.T) {
 					"------",
 					"--",
 					"------",
+					"----",
+					"----------",
+					"----",
+					"--------",
+					"-------",
+					"----------",
+					"-------------",
+					"----",
 				},
 				{
 					"https://github.com/vaguecoder",
@@ -591,6 +872,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/random",
@@ -598,6 +887,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"2",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"",
@@ -605,6 +902,14 @@ func TestBookmarksTable(t *testing.T) {
 					"",
 					"0",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -636,6 +941,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/random",
@@ -643,6 +956,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"2",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -674,6 +995,14 @@ func TestBookmarksTable(t *testing.T) {
 					"FOLDER",
 					"ID",
 					"PARENT",
+					"GUID",
+					"PLACE-GUID",
+					"TYPE",
+					"POSITION",
+					"KEYWORD",
+					"DATE-ADDED",
+					"LAST-MODIFIED",
+					"TAGS",
 				},
 				{
 					"---",
@@ -681,6 +1010,14 @@ func TestBookmarksTable(t *testing.T) {
 					"------",
 					"--",
 					"------",
+					"----",
+					"----------",
+					"----",
+					"--------",
+					"-------",
+					"----------",
+					"-------------",
+					"----",
 				},
 				{
 					"https://github.com/vaguecoder",
@@ -688,6 +1025,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"1",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 				{
 					"https://github.com/random",
@@ -695,6 +1040,14 @@ func TestBookmarksTable(t *testing.T) {
 					"Profiles/GitHub",
 					"2",
 					"0",
+					"",
+					"",
+					"0",
+					"0",
+					"",
+					"",
+					"",
+					"",
 				},
 			},
 		},
@@ -708,6 +1061,47 @@ func TestBookmarksTable(t *testing.T) {
 	}
 }
 
+func TestUnionTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		tags  []string
+		other []string
+		want  []string
+	}{
+		{
+			name:  "Disjoint-Tags",
+			tags:  []string{"code", "go"},
+			other: []string{"profile"},
+			want:  []string{"code", "go", "profile"},
+		},
+		{
+			name:  "Common-Tags",
+			tags:  []string{"code", "go"},
+			other: []string{"go", "profile", "code"},
+			want:  []string{"code", "go", "profile"},
+		},
+		{
+			name:  "Duplicates-In-List",
+			tags:  []string{"go", "go"},
+			other: nil,
+			want:  []string{"go"},
+		},
+		{
+			name:  "No-Tags",
+			tags:  nil,
+			other: nil,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnionTags(tt.tags, tt.other); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnionTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_trimSpace(t *testing.T) {
 	str := `Luffy!`
 	type args struct {
@@ -814,3 +1208,135 @@ func Test_headerUnderline(t *testing.T) {
 		})
 	}
 }
+
+func TestFromTable(t *testing.T) {
+	type args struct {
+		sheet [][]string
+	}
+
+	var (
+		timestamp = time.Date(2023, time.March, 4, 5, 6, 7, 891011000, time.UTC)
+		url       = "https://github.com/vaguecoder"
+	)
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []Bookmark
+		wantErr bool
+	}{
+		{
+			name: "Empty-Input",
+			args: args{
+				sheet: nil,
+			},
+			want: nil,
+		},
+		{
+			name: "Header-With-Underline",
+			args: args{
+				sheet: [][]string{
+					TableHeader,
+					headerUnderline(TableHeader),
+					{url, "Vague Coder", "Profiles", "8", "7", "bookmarkguid", "placeguid123",
+						"1", "0", "gh", "2023-03-04T05:06:07.891011Z", "2023-03-04T05:06:07.891011Z", "code,profile"},
+				},
+			},
+			want: []Bookmark{
+				{
+					URL:          &url,
+					Title:        "Vague Coder",
+					Folder:       "Profiles",
+					ID:           8,
+					Parent:       7,
+					GUID:         "bookmarkguid",
+					PlaceGUID:    "placeguid123",
+					Type:         TypeBookmark,
+					Keyword:      "gh",
+					DateAdded:    timestamp,
+					LastModified: timestamp,
+					Tags:         []string{"code", "profile"},
+				},
+			},
+		},
+		{
+			name: "Without-Header",
+			args: args{
+				sheet: [][]string{
+					{"", "Profiles", "Toolbar", "7", "3", "folder-guid1", "", "2", "1", "", "", "", ""},
+				},
+			},
+			want: []Bookmark{
+				{
+					Title:    "Profiles",
+					Folder:   "Toolbar",
+					ID:       7,
+					Parent:   3,
+					GUID:     "folder-guid1",
+					Type:     TypeFolder,
+					Position: 1,
+				},
+			},
+		},
+		{
+			name: "Reordered-Partial-Header",
+			args: args{
+				sheet: [][]string{
+					{"title", " Url ", "ID"},
+					{"Vague Coder", url, "8"},
+				},
+			},
+			want: []Bookmark{
+				{
+					URL:   &url,
+					Title: "Vague Coder",
+					ID:    8,
+				},
+			},
+		},
+		{
+			name: "Header-Only",
+			args: args{
+				sheet: [][]string{
+					TableHeader,
+					headerUnderline(TableHeader),
+				},
+			},
+			want: []Bookmark{},
+		},
+		{
+			name: "Invalid-Integer",
+			args: args{
+				sheet: [][]string{
+					{"URL", "ID"},
+					{url, "eight"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid-Timestamp",
+			args: args{
+				sheet: [][]string{
+					{"URL", "DATE-ADDED"},
+					{url, "yesterday"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromTable(tt.args.sheet)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FromTable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromTable() = %v , want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package bookmark
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// Titles of the Firefox's built-in root folders.
+	// These are the direct children of places root, i.e., the root record with empty title.
+	MenuRoot    = `menu`
+	ToolbarRoot = `toolbar`
+	TagsRoot    = `tags`
+	UnfiledRoot = `unfiled`
+	MobileRoot  = `mobile`
+
+	// PlacesRootGUID is the Firefox's GUID of places root
+	PlacesRootGUID = `root________`
+)
+
+// RootGUIDs are the Firefox's GUIDs of the built-in root folders, against their titles
+var RootGUIDs = map[string]string{
+	MenuRoot:    `menu________`,
+	ToolbarRoot: `toolbar_____`,
+	TagsRoot:    `tags________`,
+	UnfiledRoot: `unfiled_____`,
+	MobileRoot:  `mobile______`,
+}
+
+// Node is a bookmark record along with its child records
+// in the folder hierarchy rebuilt from ID and Parent fields
+type Node struct {
+	Bookmark
+	Children []*Node
+}
+
+// IsFolder reports whether the node is a folder.
+// In absence of type, such as in older exports, the records without URL are folders.
+func (n *Node) IsFolder() bool {
+	return n.Type == TypeFolder || (n.Type == 0 && n.URL == nil)
+}
+
+// IsSeparator reports whether the node is a separator
+func (n *Node) IsSeparator() bool {
+	return n.Type == TypeSeparator
+}
+
+// IsPlacesRoot reports whether the node is Firefox's places root,
+// i.e., the top most folder with no parent and no title
+func (n *Node) IsPlacesRoot() bool {
+	return n.IsFolder() && n.Parent == 0 && n.Title == ""
+}
+
+// IsTagsRoot reports whether the node is Firefox's tags root, identified by its GUID,
+// or by its title when GUIDs are missing, such as in older exports. As the title alone
+// is ambiguous, it's the tags root only when it's a top-level folder, i.e., in places root.
+func (n *Node) IsTagsRoot(isTopLevel bool) bool {
+	if !n.IsFolder() {
+		return false
+	}
+
+	if n.GUID != "" {
+		return n.GUID == RootGUIDs[TagsRoot]
+	}
+
+	return isTopLevel && n.Title == TagsRoot
+}
+
+// Tree rebuilds the folder hierarchy of bookmarks from ID and Parent fields.
+// It doesn't rely on the Folder field, which is set only after denormalization.
+// The records whose parent is not in the input are returned as top-level nodes.
+// Both top-level nodes and children of every node are ordered by position, and then by ID.
+func Tree(bookmarks []Bookmark) []*Node {
+	var (
+		node, parent *Node
+		ok           bool
+		roots        []*Node
+
+		nodes = make(map[int]*Node, len(bookmarks))
+		order = make([]*Node, 0, len(bookmarks))
+	)
+
+	// Map all the records against their IDs
+	for _, b := range bookmarks {
+		node = &Node{Bookmark: b}
+		nodes[b.ID] = node
+		order = append(order, node)
+	}
+
+	// Order on position and ID so that both top-level nodes and children are ordered
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].Position != order[j].Position {
+			return order[i].Position < order[j].Position
+		}
+
+		return order[i].ID < order[j].ID
+	})
+
+	// Link every record to its parent
+	for _, node = range order {
+		if parent, ok = nodes[node.Parent]; !ok || parent == node {
+			// When the parent is missing in the input, or the record is its own parent
+			roots = append(roots, node)
+			continue
+		}
+
+		parent.Children = append(parent.Children, node)
+	}
+
+	return roots
+}
+
+// FolderPathDelimiter is the delimiter of folder titles in folder path, same as in denormalized records
+const FolderPathDelimiter = `/`
+
+// JoinPath appends the title to the folder path, i.e., returns the path of the folder titled so in it
+func JoinPath(path, title string) string {
+	if path == "" {
+		return title
+	}
+
+	return path + FolderPathDelimiter + title
+}
+
+// Located is a record along with the path of the folder it's in, and the GUID of its parent
+type Located struct {
+	Bookmark
+	Path       string
+	ParentGUID string
+}
+
+// Locate lists the records in depth-first order of the hierarchy, along with their folder paths.
+// The Folder field is the path of denormalized records, and the path is rebuilt from
+// the hierarchy for the raw records. The places root and the tags, i.e., the records
+// under tags root, are skipped. The parent GUID is blank for the top-level records.
+func Locate(bookmarks []Bookmark) []Located {
+	var (
+		located []Located
+		walk    func(node *Node, path []string, parentGUID string)
+	)
+
+	walk = func(node *Node, path []string, parentGUID string) {
+		if node.IsPlacesRoot() {
+			// Places root has no title, and its children are the top-level folders
+			for _, child := range node.Children {
+				walk(child, path, node.GUID)
+			}
+
+			return
+		}
+
+		if node.IsTagsRoot(len(path) == 0) {
+			// Tags are resolved onto the tagged bookmarks by tags filter, instead of being folders
+			return
+		}
+
+		folder := node.Folder
+		if folder == "" {
+			// When the record isn't denormalized, the path is rebuilt from the hierarchy
+			folder = strings.Join(path, FolderPathDelimiter)
+		}
+
+		located = append(located, Located{Bookmark: node.Bookmark, Path: folder, ParentGUID: parentGUID})
+
+		for _, child := range node.Children {
+			walk(child, append(append([]string{}, path...), node.Title), node.GUID)
+		}
+	}
+
+	for _, root := range Tree(bookmarks) {
+		walk(root, nil, "")
+	}
+
+	return located
+}
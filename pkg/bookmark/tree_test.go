@@ -0,0 +1,234 @@
+package bookmark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+func TestTree(t *testing.T) {
+	var ptrStr = util.PtrStr
+
+	// flatten returns the IDs of the nodes in depth-first order along with depth
+	var flatten func(nodes []*Node, depth int) [][2]int
+	flatten = func(nodes []*Node, depth int) [][2]int {
+		var result [][2]int
+		for _, node := range nodes {
+			result = append(result, [2]int{node.ID, depth})
+			result = append(result, flatten(node.Children, depth+1)...)
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		bookmarks []Bookmark
+		want      [][2]int
+	}{
+		{
+			name:      "Empty-Bookmarks",
+			bookmarks: []Bookmark{},
+			want:      nil,
+		},
+		{
+			name: "Nested-Folders-Unordered",
+			bookmarks: []Bookmark{
+				{URL: ptrStr("github.com/vaguecoder/gorilla-mux"), Title: "Gorilla Mux Fork", ID: 4, Parent: 2},
+				{URL: nil, Title: "GitHub", ID: 2, Parent: 1},
+				{URL: ptrStr("github.com/vaguecoder/firefox-backups"), Title: "Firefox Backups", ID: 3, Parent: 2},
+				{URL: nil, Title: "Projects", ID: 1, Parent: 0},
+			},
+			want: [][2]int{{1, 0}, {2, 1}, {3, 2}, {4, 2}},
+		},
+		{
+			name: "Missing-Parents",
+			bookmarks: []Bookmark{
+				{URL: ptrStr("github.com/vaguecoder/firefox-backups"), Title: "Firefox Backups", ID: 3, Parent: 2},
+				{URL: ptrStr("github.com/vaguecoder/gorilla-mux"), Title: "Gorilla Mux Fork", ID: 4, Parent: 2},
+			},
+			want: [][2]int{{3, 0}, {4, 0}},
+		},
+		{
+			name: "Self-Parent",
+			bookmarks: []Bookmark{
+				{URL: nil, Title: "Projects", ID: 1, Parent: 1},
+				{URL: ptrStr("github.com/vaguecoder/firefox-backups"), Title: "Firefox Backups", ID: 2, Parent: 1},
+			},
+			want: [][2]int{{1, 0}, {2, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, flatten(Tree(tt.bookmarks), 0), "Mismatch of tree nodes")
+		})
+	}
+}
+
+func TestNode_IsPlacesRoot(t *testing.T) {
+	tests := []struct {
+		name string
+		node Node
+		want bool
+	}{
+		{
+			name: "Places-Root",
+			node: Node{Bookmark: Bookmark{ID: 1, Parent: 0, Title: ""}},
+			want: true,
+		},
+		{
+			name: "Titled-Folder",
+			node: Node{Bookmark: Bookmark{ID: 2, Parent: 0, Title: MenuRoot}},
+			want: false,
+		},
+		{
+			name: "Nested-Folder",
+			node: Node{Bookmark: Bookmark{ID: 2, Parent: 1, Title: ""}},
+			want: false,
+		},
+		{
+			name: "Bookmark",
+			node: Node{Bookmark: Bookmark{ID: 2, Parent: 0, URL: util.PtrStr("github.com/vaguecoder")}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.node.IsPlacesRoot(), "Mismatch of places root check")
+		})
+	}
+}
+
+func TestNode_IsTagsRoot(t *testing.T) {
+	tests := []struct {
+		name       string
+		node       Node
+		isTopLevel bool
+		want       bool
+	}{
+		{
+			name:       "Tags-Root-GUID",
+			node:       Node{Bookmark: Bookmark{ID: 4, Parent: 1, GUID: RootGUIDs[TagsRoot], Title: TagsRoot}},
+			isTopLevel: true,
+			want:       true,
+		},
+		{
+			name:       "Tags-Root-Title-Without-GUID",
+			node:       Node{Bookmark: Bookmark{ID: 4, Parent: 1, Title: TagsRoot}},
+			isTopLevel: true,
+			want:       true,
+		},
+		{
+			name:       "Nested-Folder-Titled-Tags",
+			node:       Node{Bookmark: Bookmark{ID: 12, Parent: 10, Title: TagsRoot}},
+			isTopLevel: false,
+			want:       false,
+		},
+		{
+			name:       "Folder-Titled-Tags-With-Other-GUID",
+			node:       Node{Bookmark: Bookmark{ID: 12, Parent: 1, GUID: "folder-guid1", Title: TagsRoot}},
+			isTopLevel: true,
+			want:       false,
+		},
+		{
+			name: "Bookmark-Titled-Tags",
+			node: Node{Bookmark: Bookmark{ID: 12, Parent: 1, Title: TagsRoot,
+				URL: util.PtrStr("github.com/vaguecoder")}},
+			isTopLevel: true,
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.node.IsTagsRoot(tt.isTopLevel), "Mismatch of tags root check")
+		})
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		title string
+		want  string
+	}{
+		{
+			name:  "Top-Level-Folder",
+			path:  "",
+			title: ToolbarRoot,
+			want:  "toolbar",
+		},
+		{
+			name:  "Nested-Folder",
+			path:  "toolbar/GitHub",
+			title: "Forks",
+			want:  "toolbar/GitHub/Forks",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, JoinPath(tt.path, tt.title), "Mismatch of folder path")
+		})
+	}
+}
+
+func TestLocate(t *testing.T) {
+	var ptrStr = util.PtrStr
+
+	// paths returns the titles of located records against their paths and parent GUIDs
+	paths := func(located []Located) [][3]string {
+		var result [][3]string
+		for _, l := range located {
+			result = append(result, [3]string{l.Title, l.Path, l.ParentGUID})
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		bookmarks []Bookmark
+		want      [][3]string
+	}{
+		{
+			name:      "Empty-Bookmarks",
+			bookmarks: nil,
+			want:      nil,
+		},
+		{
+			name: "Raw-With-Places-Root-And-Tags",
+			bookmarks: []Bookmark{
+				{Title: "", ID: 1, Parent: 0, GUID: "root________", Type: TypeFolder},
+				{Title: ToolbarRoot, ID: 3, Parent: 1, GUID: "toolbar_____", Type: TypeFolder},
+				{Title: TagsRoot, ID: 4, Parent: 1, GUID: "tags________", Type: TypeFolder, Position: 1},
+				{Title: "code", ID: 5, Parent: 4, GUID: "tag-folder01", Type: TypeFolder},
+				{Title: "GitHub", ID: 6, Parent: 3, GUID: "folder-guid1", Type: TypeFolder},
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 7, Parent: 6,
+					GUID: "bookmarkgd01", Type: TypeBookmark},
+				{URL: ptrStr("https://github.com/vaguecoder"), ID: 8, Parent: 5, Type: TypeBookmark},
+			},
+			want: [][3]string{
+				{ToolbarRoot, "", "root________"},
+				{"GitHub", "toolbar", "toolbar_____"},
+				{"Vague Coder", "toolbar/GitHub", "folder-guid1"},
+			},
+		},
+		{
+			name: "Denormalized-Without-Folders",
+			bookmarks: []Bookmark{
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", Folder: "toolbar/GitHub", ID: 7,
+					Parent: 6},
+			},
+			want: [][3]string{
+				{"Vague Coder", "toolbar/GitHub", ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, paths(Locate(tt.bookmarks)), "Mismatch of located records")
+		})
+	}
+}
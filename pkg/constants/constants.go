@@ -1,14 +1,17 @@
 package constants
 
 type (
-	OutputFormat string // Output format constants: JSON, YAML, CSV, Tabular
-	Filter       string // Bookmark filter constants: denormalize, ignore-defaults
+	OutputFormat string // Output format constants: JSON, YAML, CSV, Tabular, HTML, JSONLZ4, JSON/YAML trees, Markdown, Org, Site, SQLite export, JSON Lines, and input-only SQLite
+	Filter       string // Bookmark filter constants: denormalize, ignore-defaults, tags, dedupe, dead-links, redirects, where, folders
 	Flag         string // Input flag name constants: input-sqlite-file, output-filename, etc.
+	Mode         string // Mode constants: bookmarks, history, check-links
+	Command      string // Command constants: import, diff, merge
+	Policy       string // Merge conflict and dedupe policy constants: newest, prefer-first, keep-both, first, report
 )
 
 // stringer is a custom stringer interface which defines String method on underlying types
 type stringer interface {
-	OutputFormat | Filter | Flag
+	OutputFormat | Filter | Flag | Mode | Command | Policy
 }
 
 // Constant is a stringer type wound on string
@@ -26,17 +29,90 @@ const (
 	YAMLFormat    Constant[OutputFormat] = `yaml`
 	CSVFormat     Constant[OutputFormat] = `csv`
 	TabularFormat Constant[OutputFormat] = `table`
+	HTMLFormat    Constant[OutputFormat] = `html`
+	JSONLZ4Format Constant[OutputFormat] = `jsonlz4`
+	SQLiteFormat  Constant[OutputFormat] = `sqlite` // Input-only, places.sqlite
+
+	// Output formats of the folder hierarchy, with folders nesting their children
+	JSONTreeFormat Constant[OutputFormat] = `json-tree`
+	YAMLTreeFormat Constant[OutputFormat] = `yaml-tree`
+
+	// Output formats of notes, with folders as headings
+	MarkdownFormat Constant[OutputFormat] = `markdown`
+	OrgFormat      Constant[OutputFormat] = `org`
+
+	// Output format of self-contained searchable HTML page, to be published on a static host
+	SiteFormat Constant[OutputFormat] = `site`
+
+	// Output format of new SQLite database with normalized tables, unlike Firefox's places.sqlite
+	SQLiteExportFormat Constant[OutputFormat] = `sqlite-export`
+
+	// Output format of JSON Lines, i.e., a compact object per line, to be streamed
+	JSONLFormat Constant[OutputFormat] = `jsonl`
 
 	// Bookmark filter constants
 	DenormalizeFilter    Constant[Filter] = `denormalize`
 	IgnoreDefaultsFilter Constant[Filter] = `ignore-defaults`
+	TagsFilter           Constant[Filter] = `tags`
+	DedupeFilter         Constant[Filter] = `dedupe`
+	DeadLinksFilter      Constant[Filter] = `dead-links`
+	RedirectsFilter      Constant[Filter] = `redirects`
+	WhereFilter          Constant[Filter] = `where`
+	FoldersFilter        Constant[Filter] = `folders`
 
 	// Input flag name constants
-	InputSQLiteFileFlag Constant[Flag] = `input-sqlite-file`
-	RawFlag             Constant[Flag] = `raw`
-	IgnoreDefaultsFlag  Constant[Flag] = `ignore-defaults`
-	SilentFlag          Constant[Flag] = `silent`
-	StdOutFormatFlag    Constant[Flag] = `stdout-format`
-	DenormalizeFlag     Constant[Flag] = `denormalize`
-	OutputFiles         Constant[Flag] = `output-files`
+	InputSQLiteFileFlag  Constant[Flag] = `input-sqlite-file`
+	InputJSONLZ4FileFlag Constant[Flag] = `input-jsonlz4-file`
+	RawFlag              Constant[Flag] = `raw`
+	IgnoreDefaultsFlag   Constant[Flag] = `ignore-defaults`
+	SilentFlag           Constant[Flag] = `silent`
+	StdOutFormatFlag     Constant[Flag] = `stdout-format`
+	DenormalizeFlag      Constant[Flag] = `denormalize`
+	TagsFlag             Constant[Flag] = `tags`
+	OutputFiles          Constant[Flag] = `output-files`
+	ModeFlag             Constant[Flag] = `mode`
+	HistoryRangeFlag     Constant[Flag] = `history-range`
+	ProfileFlag          Constant[Flag] = `profile`
+	ListProfilesFlag     Constant[Flag] = `list-profiles`
+	ProfilesRootFlag     Constant[Flag] = `profiles-root`
+	AllProfilesFlag      Constant[Flag] = `all-profiles`
+	InputFileFlag        Constant[Flag] = `input-file`
+	TargetSQLiteFileFlag Constant[Flag] = `target-sqlite-file`
+	OldFlag              Constant[Flag] = `old`
+	NewFlag              Constant[Flag] = `new`
+	SourceFlag           Constant[Flag] = `source`
+	ConflictPolicyFlag   Constant[Flag] = `conflict-policy`
+	DedupeFlag           Constant[Flag] = `dedupe`
+	DedupeKeepFlag       Constant[Flag] = `dedupe-keep`
+	DedupeFragmentFlag   Constant[Flag] = `dedupe-ignore-fragment`
+	DropDeadLinksFlag    Constant[Flag] = `drop-dead-links`
+	RewriteRedirectsFlag Constant[Flag] = `rewrite-redirects`
+	CheckWorkersFlag     Constant[Flag] = `check-workers`
+	CheckIntervalFlag    Constant[Flag] = `check-host-interval`
+	CheckTimeoutFlag     Constant[Flag] = `check-timeout`
+	WhereFlag            Constant[Flag] = `where`
+	IncludeFolderFlag    Constant[Flag] = `include-folder`
+	ExcludeFolderFlag    Constant[Flag] = `exclude-folder`
+	ConfigFlag           Constant[Flag] = `config`
+	FrontMatterFlag      Constant[Flag] = `front-matter`
+	JSONLFieldsFlag      Constant[Flag] = `jsonl-fields`
+
+	// Mode constants
+	BookmarksMode  Constant[Mode] = `bookmarks`
+	HistoryMode    Constant[Mode] = `history`
+	CheckLinksMode Constant[Mode] = `check-links`
+
+	// Command constants
+	ImportCommand Constant[Command] = `import`
+	DiffCommand   Constant[Command] = `diff`
+	MergeCommand  Constant[Command] = `merge`
+
+	// Merge conflict policy constants
+	NewestPolicy      Constant[Policy] = `newest`
+	PreferFirstPolicy Constant[Policy] = `prefer-first`
+	KeepBothPolicy    Constant[Policy] = `keep-both`
+
+	// Dedupe policy constants, along with newest
+	KeepFirstPolicy Constant[Policy] = `first`
+	ReportPolicy    Constant[Policy] = `report`
 )
@@ -40,6 +40,56 @@ func TestConstant_stringer_OutputFormat_String(t *testing.T) {
 			stringer: TabularFormat,
 			want:     `table`,
 		},
+		{
+			name:     "OutputFormat_HTML-Format",
+			stringer: HTMLFormat,
+			want:     `html`,
+		},
+		{
+			name:     "OutputFormat_JSONLZ4-Format",
+			stringer: JSONLZ4Format,
+			want:     `jsonlz4`,
+		},
+		{
+			name:     "OutputFormat_SQLite-Format",
+			stringer: SQLiteFormat,
+			want:     `sqlite`,
+		},
+		{
+			name:     "OutputFormat_JSON-Tree-Format",
+			stringer: JSONTreeFormat,
+			want:     `json-tree`,
+		},
+		{
+			name:     "OutputFormat_YAML-Tree-Format",
+			stringer: YAMLTreeFormat,
+			want:     `yaml-tree`,
+		},
+		{
+			name:     "OutputFormat_Markdown-Format",
+			stringer: MarkdownFormat,
+			want:     `markdown`,
+		},
+		{
+			name:     "OutputFormat_Org-Format",
+			stringer: OrgFormat,
+			want:     `org`,
+		},
+		{
+			name:     "OutputFormat_Site-Format",
+			stringer: SiteFormat,
+			want:     `site`,
+		},
+		{
+			name:     "OutputFormat_SQLite-Export-Format",
+			stringer: SQLiteExportFormat,
+			want:     `sqlite-export`,
+		},
+		{
+			name:     "OutputFormat_JSONL-Format",
+			stringer: JSONLFormat,
+			want:     `jsonl`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -77,6 +127,36 @@ func TestConstant_stringer_Filter_String(t *testing.T) {
 			stringer: IgnoreDefaultsFilter,
 			want:     `ignore-defaults`,
 		},
+		{
+			name:     "Filter_Tags-Filter",
+			stringer: TagsFilter,
+			want:     `tags`,
+		},
+		{
+			name:     "Filter_Dedupe-Filter",
+			stringer: DedupeFilter,
+			want:     `dedupe`,
+		},
+		{
+			name:     "Filter_Dead-Links-Filter",
+			stringer: DeadLinksFilter,
+			want:     `dead-links`,
+		},
+		{
+			name:     "Filter_Redirects-Filter",
+			stringer: RedirectsFilter,
+			want:     `redirects`,
+		},
+		{
+			name:     "Filter_Where-Filter",
+			stringer: WhereFilter,
+			want:     `where`,
+		},
+		{
+			name:     "Filter_Folders-Filter",
+			stringer: FoldersFilter,
+			want:     `folders`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -139,6 +219,136 @@ func TestConstant_stringer_Flag_String(t *testing.T) {
 			stringer: OutputFiles,
 			want:     `output-files`,
 		},
+		{
+			name:     "Flag_Mode-Flag",
+			stringer: ModeFlag,
+			want:     `mode`,
+		},
+		{
+			name:     "Flag_History-Range-Flag",
+			stringer: HistoryRangeFlag,
+			want:     `history-range`,
+		},
+		{
+			name:     "Flag_Profile-Flag",
+			stringer: ProfileFlag,
+			want:     `profile`,
+		},
+		{
+			name:     "Flag_List-Profiles-Flag",
+			stringer: ListProfilesFlag,
+			want:     `list-profiles`,
+		},
+		{
+			name:     "Flag_Profiles-Root-Flag",
+			stringer: ProfilesRootFlag,
+			want:     `profiles-root`,
+		},
+		{
+			name:     "Flag_All-Profiles-Flag",
+			stringer: AllProfilesFlag,
+			want:     `all-profiles`,
+		},
+		{
+			name:     "Flag_Input-File-Flag",
+			stringer: InputFileFlag,
+			want:     `input-file`,
+		},
+		{
+			name:     "Flag_Target-SQLite-File-Flag",
+			stringer: TargetSQLiteFileFlag,
+			want:     `target-sqlite-file`,
+		},
+		{
+			name:     "Flag_Old-Flag",
+			stringer: OldFlag,
+			want:     `old`,
+		},
+		{
+			name:     "Flag_New-Flag",
+			stringer: NewFlag,
+			want:     `new`,
+		},
+		{
+			name:     "Flag_Source-Flag",
+			stringer: SourceFlag,
+			want:     `source`,
+		},
+		{
+			name:     "Flag_Conflict-Policy-Flag",
+			stringer: ConflictPolicyFlag,
+			want:     `conflict-policy`,
+		},
+		{
+			name:     "Flag_Dedupe-Flag",
+			stringer: DedupeFlag,
+			want:     `dedupe`,
+		},
+		{
+			name:     "Flag_Dedupe-Keep-Flag",
+			stringer: DedupeKeepFlag,
+			want:     `dedupe-keep`,
+		},
+		{
+			name:     "Flag_Dedupe-Fragment-Flag",
+			stringer: DedupeFragmentFlag,
+			want:     `dedupe-ignore-fragment`,
+		},
+		{
+			name:     "Flag_Drop-Dead-Links-Flag",
+			stringer: DropDeadLinksFlag,
+			want:     `drop-dead-links`,
+		},
+		{
+			name:     "Flag_Rewrite-Redirects-Flag",
+			stringer: RewriteRedirectsFlag,
+			want:     `rewrite-redirects`,
+		},
+		{
+			name:     "Flag_Check-Workers-Flag",
+			stringer: CheckWorkersFlag,
+			want:     `check-workers`,
+		},
+		{
+			name:     "Flag_Check-Interval-Flag",
+			stringer: CheckIntervalFlag,
+			want:     `check-host-interval`,
+		},
+		{
+			name:     "Flag_Check-Timeout-Flag",
+			stringer: CheckTimeoutFlag,
+			want:     `check-timeout`,
+		},
+		{
+			name:     "Flag_Where-Flag",
+			stringer: WhereFlag,
+			want:     `where`,
+		},
+		{
+			name:     "Flag_Include-Folder-Flag",
+			stringer: IncludeFolderFlag,
+			want:     `include-folder`,
+		},
+		{
+			name:     "Flag_Exclude-Folder-Flag",
+			stringer: ExcludeFolderFlag,
+			want:     `exclude-folder`,
+		},
+		{
+			name:     "Flag_Config-Flag",
+			stringer: ConfigFlag,
+			want:     `config`,
+		},
+		{
+			name:     "Flag_Front-Matter-Flag",
+			stringer: FrontMatterFlag,
+			want:     `front-matter`,
+		},
+		{
+			name:     "Flag_JSONL-Fields-Flag",
+			stringer: JSONLFieldsFlag,
+			want:     `jsonl-fields`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -149,3 +359,139 @@ func TestConstant_stringer_Flag_String(t *testing.T) {
 		})
 	}
 }
+
+func TestConstant_stringer_Mode_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		stringer Constant[Mode]
+		want     string
+	}{
+		{
+			name:     "Empty-String",
+			stringer: "",
+			want:     "",
+		},
+		{
+			name:     "String-Value",
+			stringer: "Luffy",
+			want:     "Luffy",
+		},
+		{
+			name:     "Mode_Bookmarks-Mode",
+			stringer: BookmarksMode,
+			want:     `bookmarks`,
+		},
+		{
+			name:     "Mode_History-Mode",
+			stringer: HistoryMode,
+			want:     `history`,
+		},
+		{
+			name:     "Mode_Check-Links-Mode",
+			stringer: CheckLinksMode,
+			want:     `check-links`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Constant[Mode](tt.stringer).String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstant_stringer_Command_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		stringer Constant[Command]
+		want     string
+	}{
+		{
+			name:     "Empty-String",
+			stringer: "",
+			want:     "",
+		},
+		{
+			name:     "String-Value",
+			stringer: "Luffy",
+			want:     "Luffy",
+		},
+		{
+			name:     "Command_Import-Command",
+			stringer: ImportCommand,
+			want:     `import`,
+		},
+		{
+			name:     "Command_Diff-Command",
+			stringer: DiffCommand,
+			want:     `diff`,
+		},
+		{
+			name:     "Command_Merge-Command",
+			stringer: MergeCommand,
+			want:     `merge`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Constant[Command](tt.stringer).String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstant_stringer_Policy_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		stringer Constant[Policy]
+		want     string
+	}{
+		{
+			name:     "Empty-String",
+			stringer: "",
+			want:     "",
+		},
+		{
+			name:     "String-Value",
+			stringer: "Luffy",
+			want:     "Luffy",
+		},
+		{
+			name:     "Policy_Newest-Policy",
+			stringer: NewestPolicy,
+			want:     `newest`,
+		},
+		{
+			name:     "Policy_Prefer-First-Policy",
+			stringer: PreferFirstPolicy,
+			want:     `prefer-first`,
+		},
+		{
+			name:     "Policy_Keep-Both-Policy",
+			stringer: KeepBothPolicy,
+			want:     `keep-both`,
+		},
+		{
+			name:     "Policy_Keep-First-Policy",
+			stringer: KeepFirstPolicy,
+			want:     `first`,
+		},
+		{
+			name:     "Policy_Report-Policy",
+			stringer: ReportPolicy,
+			want:     `report`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Constant[Policy](tt.stringer).String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,8 +18,19 @@ type BookmarkOperator interface {
 	GetBookmarks(context.Context) ([]bookmark.Bookmark, error)
 }
 
+// BookmarkStreamer streams the bookmarks one at a time, so they aren't held in memory
+type BookmarkStreamer interface {
+	StreamBookmarks(ctx context.Context, fn func(bookmark.Bookmark) error) error
+}
+
 const (
-	queryStr = `SELECT bookmarks.id, bookmarks.parent, places.URL, bookmarks.title
+	queryStr = `SELECT bookmarks.id, bookmarks.parent, places.URL, COALESCE(bookmarks.title, ''),
+				COALESCE(bookmarks.guid, ''), COALESCE(places.guid, ''),
+				bookmarks.type, bookmarks.position,
+				COALESCE((SELECT keywords.keyword FROM moz_keywords as keywords
+					WHERE keywords.id = bookmarks.keyword_id OR keywords.place_id = places.id
+					ORDER BY keywords.id LIMIT 1), ''),
+				COALESCE(bookmarks.dateAdded, 0), COALESCE(bookmarks.lastModified, 0)
 				FROM moz_places as places
 				RIGHT JOIN moz_bookmarks as bookmarks 
 				ON places.id = bookmarks.fk`
@@ -32,6 +43,24 @@ func NewDatabaseOperator(conn sqlite.DBConnection) BookmarkOperator {
 }
 
 func (d *DatabaseOperator) GetBookmarks(ctx context.Context) ([]bookmark.Bookmark, error) {
+	var bookmarks []bookmark.Bookmark
+
+	err := d.StreamBookmarks(ctx, func(bm bookmark.Bookmark) error {
+		bookmarks = append(bookmarks, bm)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logs.FromContext(ctx).Debug().Interface("bookmarks", bookmarks).Msg("Resultant bookmarks")
+
+	return bookmarks, nil
+}
+
+// StreamBookmarks queries the bookmarks, and calls fn with each bookmark as it's scanned.
+// Streaming stops at the first error of fn, and the error is returned as is.
+func (d *DatabaseOperator) StreamBookmarks(ctx context.Context, fn func(bookmark.Bookmark) error) error {
 	logger := logs.FromContext(ctx)
 
 	logger.Info().Str("query", util.StrWhitespacesCleanup(queryStr)).Msg("Bookmarks query string")
@@ -39,24 +68,38 @@ func (d *DatabaseOperator) GetBookmarks(ctx context.Context) ([]bookmark.Bookmar
 	rows, err := d.db.Query(queryStr)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to query DB")
-		return nil, fmt.Errorf("failed to query db: %v", err)
+		return fmt.Errorf("failed to query db: %v", err)
 	}
+	defer rows.Close()
 
-	var bookmarks []bookmark.Bookmark
 	for rows.Next() {
-		var bm bookmark.Bookmark
+		var (
+			bm                      bookmark.Bookmark
+			dateAdded, lastModified int64
+		)
 
-		err = rows.Scan(&bm.ID, &bm.Parent, &bm.URL, &bm.Title)
+		err = rows.Scan(&bm.ID, &bm.Parent, &bm.URL, &bm.Title, &bm.GUID, &bm.PlaceGUID,
+			&bm.Type, &bm.Position, &bm.Keyword, &dateAdded, &lastModified)
 		if err != nil {
 			logger.Error().Err(err).Msg("Failed to execute query")
-			return nil, fmt.Errorf("failed to execute query: %v", err)
+			return fmt.Errorf("failed to execute query: %v", err)
 		}
 
-		bookmarks = append(bookmarks, bm)
+		// Timestamps are stored as PRTime, i.e., microseconds since epoch
+		bm.DateAdded = bookmark.FromPRTime(dateAdded)
+		bm.LastModified = bookmark.FromPRTime(lastModified)
+
+		if err = fn(bm); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		logger.Error().Err(err).Msg("Failed to read rows")
+		return fmt.Errorf("failed to read rows: %v", err)
 	}
 
-	logger.Debug().Interface("bookmarks", bookmarks).Msg("Resultant bookmarks")
 	logger.Info().Msg("Successfully executed query and scanned fields")
 
-	return bookmarks, nil
+	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
@@ -21,7 +22,7 @@ var ptrStr = util.PtrStr
 func TestDatabaseOperator_GetBookmarks(t *testing.T) {
 	ctx := context.Background()
 
-	sqlDB, mockServer, err := sqlMock.New()
+	sqlDB, mockServer, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
 	require.NoError(t, err, "unexpected error at DB mock server creation")
 
 	type fields struct {
@@ -43,18 +44,32 @@ func TestDatabaseOperator_GetBookmarks(t *testing.T) {
 			},
 			want: []bookmark.Bookmark{
 				{
-					URL:    ptrStr("https://github.com/vaguecoder"),
-					Title:  "Vague Coder",
-					Folder: "",
-					ID:     1,
-					Parent: 0,
+					URL:          ptrStr("https://github.com/vaguecoder"),
+					Title:        "Vague Coder",
+					Folder:       "",
+					ID:           1,
+					Parent:       0,
+					GUID:         "8rLXK7Gl2ayV",
+					PlaceGUID:    "pwbqKjp4F1Nl",
+					Type:         bookmark.TypeBookmark,
+					Position:     0,
+					Keyword:      "vc",
+					DateAdded:    time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC),
+					LastModified: time.Date(2023, time.March, 4, 11, 37, 7, 0, time.UTC),
 				},
 				{
-					URL:    ptrStr("https://github.com/random"),
-					Title:  "Random",
-					Folder: "",
-					ID:     2,
-					Parent: 0,
+					URL:          ptrStr("https://github.com/random"),
+					Title:        "Random",
+					Folder:       "",
+					ID:           2,
+					Parent:       0,
+					GUID:         "Lqk2dvFzb0Ju",
+					PlaceGUID:    "kIv6j4vXyYzU",
+					Type:         bookmark.TypeBookmark,
+					Position:     1,
+					Keyword:      "",
+					DateAdded:    time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC),
+					LastModified: time.Date(2023, time.March, 4, 11, 37, 7, 0, time.UTC),
 				},
 			},
 			rows: [][]string{
@@ -63,18 +78,39 @@ func TestDatabaseOperator_GetBookmarks(t *testing.T) {
 					"parent",
 					"url",
 					"title",
+					"guid",
+					"place_guid",
+					"type",
+					"position",
+					"keyword",
+					"dateAdded",
+					"lastModified",
 				},
 				{
 					"1",
 					"0",
 					"https://github.com/vaguecoder",
 					"Vague Coder",
+					"8rLXK7Gl2ayV",
+					"pwbqKjp4F1Nl",
+					"1",
+					"0",
+					"vc",
+					"1677929826000000",
+					"1677929827000000",
 				},
 				{
 					"2",
 					"0",
 					"https://github.com/random",
 					"Random",
+					"Lqk2dvFzb0Ju",
+					"kIv6j4vXyYzU",
+					"1",
+					"1",
+					"",
+					"1677929826000000",
+					"1677929827000000",
 				},
 			},
 			wantErr:    false,
@@ -92,18 +128,39 @@ func TestDatabaseOperator_GetBookmarks(t *testing.T) {
 					"parent",
 					"url",
 					"title",
+					"guid",
+					"place_guid",
+					"type",
+					"position",
+					"keyword",
+					"dateAdded",
+					"lastModified",
 				},
 				{
 					"1",
 					"0",
 					"https://github.com/vaguecoder",
 					"Vague Coder",
+					"8rLXK7Gl2ayV",
+					"pwbqKjp4F1Nl",
+					"1",
+					"0",
+					"vc",
+					"1677929826000000",
+					"1677929827000000",
 				},
 				{
 					"2",
 					"0",
 					"https://github.com/random",
 					"Random",
+					"Lqk2dvFzb0Ju",
+					"kIv6j4vXyYzU",
+					"1",
+					"1",
+					"",
+					"1677929826000000",
+					"1677929827000000",
 				},
 			},
 			wantErr:    true,
@@ -121,18 +178,39 @@ func TestDatabaseOperator_GetBookmarks(t *testing.T) {
 					"parent",
 					"url",
 					"title",
+					"guid",
+					"place_guid",
+					"type",
+					"position",
+					"keyword",
+					"dateAdded",
+					"lastModified",
 				},
 				{
 					"1APPLE",
 					"0",
 					"https://github.com/vaguecoder",
 					"Vague Coder",
+					"8rLXK7Gl2ayV",
+					"pwbqKjp4F1Nl",
+					"1",
+					"0",
+					"vc",
+					"1677929826000000",
+					"1677929827000000",
 				},
 				{
 					"2",
 					"0",
 					"https://github.com/random",
 					"Random",
+					"Lqk2dvFzb0Ju",
+					"kIv6j4vXyYzU",
+					"1",
+					"1",
+					"",
+					"1677929826000000",
+					"1677929827000000",
 				},
 			},
 			wantErr:    true,
@@ -147,7 +225,7 @@ func TestDatabaseOperator_GetBookmarks(t *testing.T) {
 			if tt.dbQueryErr {
 				tt.fields.db.On("Query", queryStr).Return(nil, fmt.Errorf("some error"))
 			} else {
-				rows, err := sqlRows(sqlDB, mockServer, tt.rows)
+				rows, err := sqlRows(sqlDB, mockServer, queryStr, tt.rows)
 				require.NoError(t, err, "Unexpected error at mock rows creation")
 
 				tt.fields.db.On("Query", queryStr).Return(rows, nil)
@@ -166,7 +244,43 @@ func TestDatabaseOperator_GetBookmarks(t *testing.T) {
 	}
 }
 
-func sqlRows(db *sql.DB, mockServer sqlMock.Sqlmock, data [][]string) (*sql.Rows, error) {
+func TestDatabaseOperator_StreamBookmarks(t *testing.T) {
+	ctx := context.Background()
+
+	sqlDB, mockServer, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	require.NoError(t, err, "unexpected error at DB mock server creation")
+
+	rows, err := sqlRows(sqlDB, mockServer, queryStr, [][]string{
+		{"id", "parent", "url", "title", "guid", "place_guid", "type", "position", "keyword", "dateAdded", "lastModified"},
+		{"1", "0", "https://github.com/vaguecoder", "Vague Coder", "8rLXK7Gl2ayV", "pwbqKjp4F1Nl", "1", "0", "vc",
+			"1677929826000000", "1677929827000000"},
+		{"2", "0", "https://github.com/random", "Random", "Lqk2dvFzb0Ju", "kIv6j4vXyYzU", "1", "1", "",
+			"1677929826000000", "1677929827000000"},
+	})
+	require.NoError(t, err, "Unexpected error at mock rows creation")
+
+	db := new(mocks.DBConnection)
+	db.On("Query", queryStr).Return(rows, nil)
+
+	streamer, ok := NewDatabaseOperator(db).(BookmarkStreamer)
+	require.True(t, ok, "Expected database operator to stream bookmarks")
+
+	// Streaming stops at the first error of the callback
+	var (
+		streamed []int
+		someErr  = fmt.Errorf("some error")
+	)
+
+	err = streamer.StreamBookmarks(ctx, func(bm bookmark.Bookmark) error {
+		streamed = append(streamed, bm.ID)
+		return someErr
+	})
+
+	require.ErrorIs(t, err, someErr, "Expected error of the callback")
+	require.Equal(t, []int{1}, streamed, "Mismatch of streamed bookmarks")
+}
+
+func sqlRows(db *sql.DB, mockServer sqlMock.Sqlmock, query string, data [][]string) (*sql.Rows, error) {
 	if len(data) == 0 {
 		return &sql.Rows{}, nil
 	}
@@ -183,9 +297,9 @@ func sqlRows(db *sql.DB, mockServer sqlMock.Sqlmock, data [][]string) (*sql.Rows
 		mockRows = mockRows.AddRow(values...)
 	}
 
-	mockServer.ExpectQuery(queryStr).WillReturnRows(mockRows)
+	mockServer.ExpectQuery(query).WillReturnRows(mockRows)
 
-	resultRows, err := db.Query(queryStr)
+	resultRows, err := db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to query the mock rows: %v", err)
 	}
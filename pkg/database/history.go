@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/database/sqlite"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+type HistoryOperator interface {
+	GetVisits(context.Context, history.Range) ([]history.Visit, error)
+}
+
+const (
+	// historyQueryStr takes the visit date range in PRTime, lower bound inclusive and upper bound exclusive
+	historyQueryStr = `SELECT visits.id, COALESCE(visits.from_visit, 0), places.url,
+				COALESCE(places.title, ''), visits.visit_date, visits.visit_type
+				FROM moz_historyvisits as visits
+				INNER JOIN moz_places as places
+				ON places.id = visits.place_id
+				WHERE visits.visit_date >= ? AND visits.visit_date < ?
+				ORDER BY visits.visit_date, visits.id`
+)
+
+func NewHistoryOperator(conn sqlite.DBConnection) HistoryOperator {
+	return &DatabaseOperator{
+		db: conn,
+	}
+}
+
+func (d *DatabaseOperator) GetVisits(ctx context.Context, dateRange history.Range) ([]history.Visit, error) {
+	var (
+		from int64 = 0
+		to   int64 = math.MaxInt64
+
+		logger = logs.FromContext(ctx)
+	)
+
+	// Zero time on either end leaves the range open on that end
+	if !dateRange.From.IsZero() {
+		from = bookmark.ToPRTime(dateRange.From)
+	}
+	if !dateRange.To.IsZero() {
+		to = bookmark.ToPRTime(dateRange.To)
+	}
+
+	logger.Info().Str("query", util.StrWhitespacesCleanup(historyQueryStr)).Int64("from", from).
+		Int64("to", to).Msg("History query string")
+
+	rows, err := d.db.Query(historyQueryStr, from, to)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to query DB")
+		return nil, fmt.Errorf("failed to query db: %v", err)
+	}
+	defer rows.Close()
+
+	var visits []history.Visit
+	for rows.Next() {
+		var (
+			visit     history.Visit
+			visitDate int64
+		)
+
+		err = rows.Scan(&visit.ID, &visit.FromVisit, &visit.URL, &visit.Title, &visitDate, &visit.VisitType)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to execute query")
+			return nil, fmt.Errorf("failed to execute query: %v", err)
+		}
+
+		// Visit dates are stored as PRTime, i.e., microseconds since epoch
+		visit.VisitDate = bookmark.FromPRTime(visitDate)
+
+		visits = append(visits, visit)
+	}
+
+	if err = rows.Err(); err != nil {
+		logger.Error().Err(err).Msg("Failed to read rows")
+		return nil, fmt.Errorf("failed to read rows: %v", err)
+	}
+
+	logger.Debug().Interface("visits", visits).Msg("Resultant visits")
+	logger.Info().Msg("Successfully executed query and scanned fields")
+
+	return visits, nil
+}
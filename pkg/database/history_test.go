@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/mocks"
+
+	sqlMock "github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestDatabaseOperator_GetVisits(t *testing.T) {
+	ctx := context.Background()
+
+	sqlDB, mockServer, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	require.NoError(t, err, "unexpected error at DB mock server creation")
+
+	type fields struct {
+		db *mocks.DBConnection
+	}
+
+	var (
+		header = []string{"id", "from_visit", "url", "title", "visit_date", "visit_type"}
+		from   = time.Date(2023, time.March, 4, 0, 0, 0, 0, time.UTC)
+		to     = time.Date(2023, time.March, 5, 0, 0, 0, 0, time.UTC)
+	)
+
+	tests := []struct {
+		name       string
+		fields     fields
+		dateRange  history.Range
+		queryArgs  []interface{}
+		want       []history.Visit
+		rows       [][]string
+		wantErr    bool
+		dbQueryErr bool
+	}{
+		{
+			name: "Valid-Case-With-Date-Range",
+			fields: fields{
+				db: new(mocks.DBConnection),
+			},
+			dateRange: history.Range{From: from, To: to},
+			queryArgs: []interface{}{int64(1677888000000000), int64(1677974400000000)},
+			want: []history.Visit{
+				{
+					ID:        1,
+					FromVisit: 0,
+					URL:       "https://github.com/vaguecoder",
+					Title:     "Vague Coder",
+					VisitDate: time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC),
+					VisitType: 1,
+				},
+				{
+					ID:        2,
+					FromVisit: 1,
+					URL:       "https://github.com/random",
+					Title:     "",
+					VisitDate: time.Date(2023, time.March, 4, 11, 37, 7, 0, time.UTC),
+					VisitType: 2,
+				},
+			},
+			rows: [][]string{
+				header,
+				{"1", "0", "https://github.com/vaguecoder", "Vague Coder", "1677929826000000", "1"},
+				{"2", "1", "https://github.com/random", "", "1677929827000000", "2"},
+			},
+			wantErr:    false,
+			dbQueryErr: false,
+		},
+		{
+			name: "Valid-Case-With-Open-Range",
+			fields: fields{
+				db: new(mocks.DBConnection),
+			},
+			dateRange: history.Range{},
+			queryArgs: []interface{}{int64(0), int64(math.MaxInt64)},
+			want: []history.Visit{
+				{
+					ID:        1,
+					FromVisit: 0,
+					URL:       "https://github.com/vaguecoder",
+					Title:     "Vague Coder",
+					VisitDate: time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC),
+					VisitType: 1,
+				},
+			},
+			rows: [][]string{
+				header,
+				{"1", "0", "https://github.com/vaguecoder", "Vague Coder", "1677929826000000", "1"},
+			},
+			wantErr:    false,
+			dbQueryErr: false,
+		},
+		{
+			name: "Failure-At-DB-Query",
+			fields: fields{
+				db: new(mocks.DBConnection),
+			},
+			dateRange:  history.Range{},
+			queryArgs:  []interface{}{int64(0), int64(math.MaxInt64)},
+			want:       []history.Visit(nil),
+			rows:       nil,
+			wantErr:    true,
+			dbQueryErr: true,
+		},
+		{
+			name: "Failure-At-Scan-Due-To-Invalid-Data-Type",
+			fields: fields{
+				db: new(mocks.DBConnection),
+			},
+			dateRange: history.Range{},
+			queryArgs: []interface{}{int64(0), int64(math.MaxInt64)},
+			want:      []history.Visit(nil),
+			rows: [][]string{
+				header,
+				{"1APPLE", "0", "https://github.com/vaguecoder", "Vague Coder", "1677929826000000", "1"},
+			},
+			wantErr:    true,
+			dbQueryErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewHistoryOperator(tt.fields.db)
+			queryArgs := append([]interface{}{historyQueryStr}, tt.queryArgs...)
+
+			if tt.dbQueryErr {
+				tt.fields.db.On("Query", queryArgs...).Return(nil, fmt.Errorf("some error"))
+			} else {
+				rows, err := sqlRows(sqlDB, mockServer, historyQueryStr, tt.rows)
+				require.NoError(t, err, "Unexpected error at mock rows creation")
+
+				tt.fields.db.On("Query", queryArgs...).Return(rows, nil)
+			}
+
+			got, err := d.GetVisits(ctx, tt.dateRange)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DatabaseOperator.GetVisits() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DatabaseOperator.GetVisits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDatabaseOperator_GetVisits_RowError(t *testing.T) {
+	ctx := context.Background()
+
+	sqlDB, mockServer, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	require.NoError(t, err, "unexpected error at DB mock server creation")
+
+	// The second row fails to be read, i.e., the result is truncated
+	mockRows := sqlMock.NewRows([]string{"id", "from_visit", "url", "title", "visit_date", "visit_type"}).
+		AddRow("1", "0", "https://github.com/vaguecoder", "Vague Coder", "1677929826000000", "1").
+		AddRow("2", "1", "https://go.dev/", "Go", "1677929827000000", "1").
+		RowError(1, fmt.Errorf("some error"))
+	mockServer.ExpectQuery(historyQueryStr).WillReturnRows(mockRows)
+
+	rows, err := sqlDB.Query(historyQueryStr)
+	require.NoError(t, err, "Unexpected error at mock rows creation")
+
+	db := new(mocks.DBConnection)
+	db.On("Query", historyQueryStr, int64(0), int64(math.MaxInt64)).Return(rows, nil)
+
+	got, err := NewHistoryOperator(db).GetVisits(ctx, history.Range{})
+	require.ErrorContains(t, err, "failed to read rows", "Expected error of truncated result")
+	require.Nil(t, got, "Unexpected visits of truncated result")
+}
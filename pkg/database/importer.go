@@ -0,0 +1,654 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/database/sqlite"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+const (
+	targetQueryStr = `SELECT bookmarks.id, bookmarks.parent, bookmarks.type, COALESCE(bookmarks.title, ''),
+	COALESCE(bookmarks.guid, ''), places.url, bookmarks.position FROM moz_bookmarks AS bookmarks
+	LEFT JOIN moz_places AS places ON bookmarks.fk = places.id`
+	placeGUIDsQueryStr = `SELECT COALESCE(guid, '') FROM moz_places`
+	placeQueryStr      = `SELECT id FROM moz_places WHERE url_hash = ? AND url = ?`
+	insertBookmarkStr  = `INSERT INTO moz_bookmarks (type, fk, parent, position, title, dateAdded, lastModified, guid, syncStatus, syncChangeCounter) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0, 1)`
+	insertKeywordStr   = `INSERT OR IGNORE INTO moz_keywords (keyword, place_id) VALUES (?, ?)`
+	foreignCountStr    = `UPDATE moz_places SET foreign_count = foreign_count + 1 WHERE id = ?`
+	originQueryStr     = `SELECT id FROM moz_origins WHERE prefix = ? AND host = ?`
+	tableColumnsStr    = `SELECT name FROM pragma_table_info(?)`
+
+	// Length of Firefox's GUIDs in bytes, before base64 encoding
+	guidBytes = 9
+)
+
+var (
+	// Firefox's GUIDs are 12 characters of URL-safe base64
+	guidPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{12}$`)
+)
+
+// ImportResult is the count of records imported and skipped
+type ImportResult struct {
+	Folders   int `json:"folders"`
+	Bookmarks int `json:"bookmarks"`
+	Skipped   int `json:"skipped"`
+}
+
+// BookmarkImporter imports bookmarks into the database
+type BookmarkImporter interface {
+	Import(ctx context.Context, bookmarks []bookmark.Bookmark) (ImportResult, error)
+}
+
+// Importer writes the bookmarks into places.sqlite
+type Importer struct {
+	db  sqlite.DBWriter
+	now func() time.Time
+}
+
+// NewImporter initializes new importer on write-capable connection to places.sqlite
+func NewImporter(conn sqlite.DBWriter) BookmarkImporter {
+	return &Importer{
+		db:  conn,
+		now: time.Now,
+	}
+}
+
+// importItem is a bookmark record along with the titles of its folders,
+// starting from the built-in root, e.g., [toolbar, GitHub]
+type importItem struct {
+	path []string
+	bookmark.Bookmark
+}
+
+// Import inserts the missing folders and bookmarks in a single transaction.
+// Folders are matched on title under the same parent, and the bookmarks already in the folder
+// with the same URL are skipped. New records are appended to the folders in the order of position.
+// Records in the tags root and separators are not imported.
+func (i *Importer) Import(ctx context.Context, bookmarks []bookmark.Bookmark) (ImportResult, error) {
+	var (
+		err    error
+		result ImportResult
+		target *importTarget
+
+		logger = logs.FromContext(ctx)
+	)
+
+	tx, err := i.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+
+	defer func() {
+		if err != nil {
+			// Nothing is written when the import failed midway
+			_ = tx.Rollback()
+		}
+	}()
+
+	if target, err = loadTarget(tx, i.now()); err != nil {
+		return result, err
+	}
+
+	for _, item := range importItems(bookmarks) {
+		if err = target.add(item, &result); err != nil {
+			return result, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return result, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	logger.Info().Interface("result", result).Msg("Successfully imported bookmarks")
+
+	return result, nil
+}
+
+// importItems returns the records with their folder paths, parents ahead of the children.
+// The paths are rebuilt from ID and Parent fields, unless the records are denormalized.
+func importItems(bookmarks []bookmark.Bookmark) []importItem {
+	var items []importItem
+
+	for _, b := range bookmarks {
+		if b.Folder == "" {
+			continue
+		}
+
+		// When the records are denormalized, folder paths are already in the records
+		for _, b = range bookmarks {
+			items = append(items, importItem{path: splitFolder(b.Folder), Bookmark: b})
+		}
+
+		sort.SliceStable(items, func(i, j int) bool {
+			if len(items[i].path) != len(items[j].path) {
+				return len(items[i].path) < len(items[j].path)
+			}
+
+			return items[i].Position < items[j].Position
+		})
+
+		return items
+	}
+
+	var walk func(nodes []*bookmark.Node, path []string)
+	walk = func(nodes []*bookmark.Node, path []string) {
+		for _, node := range nodes {
+			if node.IsPlacesRoot() {
+				// The places root is the parent of built-in roots, and is never imported
+				walk(node.Children, nil)
+				continue
+			}
+
+			items = append(items, importItem{path: path, Bookmark: node.Bookmark})
+
+			if len(node.Children) > 0 {
+				walk(node.Children, append(append([]string{}, path...), node.Title))
+			}
+		}
+	}
+
+	walk(bookmark.Tree(bookmarks), nil)
+
+	return items
+}
+
+// splitFolder splits the denormalized folder path into titles
+func splitFolder(folder string) []string {
+	if folder = strings.Trim(folder, bookmark.FolderPathDelimiter); folder == "" {
+		return nil
+	}
+
+	return strings.Split(folder, bookmark.FolderPathDelimiter)
+}
+
+// importTarget is the state of places.sqlite being written in the transaction
+type importTarget struct {
+	tx  *sql.Tx
+	now int64
+
+	roots     map[string]int         // Built-in root folder IDs against their titles
+	folders   map[int]map[string]int // Folder IDs against their titles, by parent
+	urls      map[int]map[string]bool
+	positions map[int]int // Next position in folder
+	guids     map[string]bool
+
+	placeColumns  map[string]bool
+	originColumns map[string]bool
+}
+
+// loadTarget reads the existing folders, bookmarks and GUIDs from the database
+func loadTarget(tx *sql.Tx, now time.Time) (*importTarget, error) {
+	var (
+		err                              error
+		id, parent, recordType, position int
+		title, guid                      string
+		recordURL                        sql.NullString
+		placesRoot                       = -1
+		rootTitles                       = make(map[string]string, len(bookmark.RootGUIDs))
+		folderRecords                    []bookmark.Bookmark
+
+		target = &importTarget{
+			tx:        tx,
+			now:       bookmark.ToPRTime(now),
+			roots:     map[string]int{},
+			folders:   map[int]map[string]int{},
+			urls:      map[int]map[string]bool{},
+			positions: map[int]int{},
+			guids:     map[string]bool{},
+		}
+	)
+
+	for title, guid = range bookmark.RootGUIDs {
+		rootTitles[guid] = title
+	}
+
+	rows, err := tx.Query(targetQueryStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bookmarks in target: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&id, &parent, &recordType, &title, &guid, &recordURL, &position); err != nil {
+			return nil, fmt.Errorf("failed to scan bookmarks in target: %v", err)
+		}
+
+		target.guids[guid] = true
+
+		if position >= target.positions[parent] {
+			target.positions[parent] = position + 1
+		}
+
+		switch {
+		case parent == 0:
+			placesRoot = id
+		case recordType == bookmark.TypeFolder:
+			target.folder(parent, title, id)
+			folderRecords = append(folderRecords, bookmark.Bookmark{ID: id, Parent: parent, Title: title, GUID: guid})
+		case recordURL.Valid:
+			target.url(parent, recordURL.String)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks in target: %v", err)
+	}
+
+	// Release the connection of transaction for further queries
+	rows.Close()
+
+	if placesRoot < 0 {
+		return nil, fmt.Errorf("places root is missing in target")
+	}
+
+	// The built-in roots are the folders in places root, identified by GUID, or by title in absence of GUID
+	for _, folder := range folderRecords {
+		if folder.Parent != placesRoot {
+			continue
+		}
+
+		if title, ok := rootTitles[folder.GUID]; ok {
+			target.roots[title] = folder.ID
+		} else if _, ok = bookmark.RootGUIDs[folder.Title]; ok && folder.GUID == "" {
+			target.roots[folder.Title] = folder.ID
+		}
+	}
+
+	if _, ok := target.roots[bookmark.UnfiledRoot]; !ok {
+		return nil, fmt.Errorf("built-in root folder %q is missing in target", bookmark.UnfiledRoot)
+	}
+
+	if err = target.loadPlaceGUIDs(); err != nil {
+		return nil, err
+	}
+
+	if target.placeColumns, err = target.columns("moz_places"); err != nil {
+		return nil, err
+	}
+
+	if target.originColumns, err = target.columns("moz_origins"); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// loadPlaceGUIDs reads the GUIDs of places, so the generated GUIDs are unique
+func (t *importTarget) loadPlaceGUIDs() error {
+	var guid string
+
+	rows, err := t.tx.Query(placeGUIDsQueryStr)
+	if err != nil {
+		return fmt.Errorf("failed to query places in target: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&guid); err != nil {
+			return fmt.Errorf("failed to scan places in target: %v", err)
+		}
+
+		t.guids[guid] = true
+	}
+
+	return rows.Err()
+}
+
+// columns returns the column names of the table, and none when the table doesn't exist
+func (t *importTarget) columns(table string) (map[string]bool, error) {
+	var (
+		name    string
+		columns = map[string]bool{}
+	)
+
+	rows, err := t.tx.Query(tableColumnsStr, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns of %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan columns of %s: %v", table, err)
+		}
+
+		columns[name] = true
+	}
+
+	return columns, rows.Err()
+}
+
+// folder records the folder under the parent
+func (t *importTarget) folder(parent int, title string, id int) {
+	if t.folders[parent] == nil {
+		t.folders[parent] = map[string]int{}
+	}
+
+	if _, ok := t.folders[parent][folderKey(title)]; !ok {
+		// The first of the folders with same title is used
+		t.folders[parent][folderKey(title)] = id
+	}
+}
+
+// url records the URL of bookmark under the parent
+func (t *importTarget) url(parent int, recordURL string) {
+	if t.urls[parent] == nil {
+		t.urls[parent] = map[string]bool{}
+	}
+
+	t.urls[parent][recordURL] = true
+}
+
+// folderKey is the key of folder title, as the titles are matched case-insensitively
+func folderKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+// add imports the record into the folder at its path, unless it exists
+func (t *importTarget) add(item importItem, result *ImportResult) error {
+	var (
+		err    error
+		parent int
+	)
+
+	if len(item.path) > 0 && item.path[0] == bookmark.TagsRoot ||
+		(&bookmark.Node{Bookmark: item.Bookmark}).IsTagsRoot(len(item.path) == 0) {
+		// Tags are folders in tags root, not in the folder hierarchy
+		return nil
+	}
+
+	switch {
+	case item.Type == bookmark.TypeSeparator:
+		// Separators can't be matched against the existing ones
+		return nil
+	case item.Type == bookmark.TypeFolder || item.Type == 0 && item.URL == nil:
+		if len(item.path) == 0 && (bookmark.RootGUIDs[item.Title] != "" || item.Title == "" && item.Parent == 0) {
+			// Places root and the built-in roots always exist
+			return nil
+		}
+
+		_, err = t.ensureFolder(append(append([]string{}, item.path...), item.Title), item.Bookmark, result)
+
+		return err
+	}
+
+	if parent, err = t.ensureFolder(item.path, bookmark.Bookmark{}, result); err != nil {
+		return err
+	}
+
+	if item.URL == nil {
+		// Bookmarks without URL can't be opened, nor matched
+		return nil
+	}
+
+	if t.urls[parent][*item.URL] {
+		// When the folder already has the bookmark
+		result.Skipped++
+		return nil
+	}
+
+	if err = t.insertBookmark(parent, item.Bookmark); err != nil {
+		return err
+	}
+
+	t.url(parent, *item.URL)
+	result.Bookmarks++
+
+	return nil
+}
+
+// ensureFolder returns the folder at the path, creating the missing folders in the path.
+// The path starts at a built-in root, and the paths starting elsewhere are placed in unfiled root.
+// The timestamps and GUID of the record are used for the last folder in path, when it is created.
+func (t *importTarget) ensureFolder(path []string, record bookmark.Bookmark, result *ImportResult) (int, error) {
+	var (
+		id     int
+		err    error
+		ok     bool
+		parent int
+	)
+
+	if parent, ok = t.roots[firstOf(path)]; ok {
+		path = path[1:]
+	} else {
+		parent = t.roots[bookmark.UnfiledRoot]
+	}
+
+	for i, title := range path {
+		if id, ok = t.folders[parent][folderKey(title)]; ok {
+			// When the folder exists
+			parent = id
+			continue
+		}
+
+		folder := bookmark.Bookmark{Title: title}
+		if i == len(path)-1 {
+			folder = record
+			folder.Title = title
+		}
+
+		if id, err = t.insertRecord(bookmark.TypeFolder, nil, parent, folder); err != nil {
+			return 0, err
+		}
+
+		t.folder(parent, title, id)
+		result.Folders++
+		parent = id
+	}
+
+	return parent, nil
+}
+
+// insertBookmark inserts the bookmark in the folder, along with its place and keyword
+func (t *importTarget) insertBookmark(parent int, b bookmark.Bookmark) error {
+	placeID, err := t.ensurePlace(b)
+	if err != nil {
+		return err
+	}
+
+	if _, err = t.insertRecord(bookmark.TypeBookmark, placeID, parent, b); err != nil {
+		return err
+	}
+
+	// Firefox counts the bookmarks and keywords referring to the place
+	if _, err = t.tx.Exec(foreignCountStr, placeID); err != nil {
+		return fmt.Errorf("failed to update place of %q: %v", *b.URL, err)
+	}
+
+	if b.Keyword == "" {
+		return nil
+	}
+
+	res, err := t.tx.Exec(insertKeywordStr, b.Keyword, placeID)
+	if err != nil {
+		return fmt.Errorf("failed to insert keyword %q: %v", b.Keyword, err)
+	}
+
+	if count, _ := res.RowsAffected(); count == 0 {
+		// When the keyword is already in use, it is left as is
+		return nil
+	}
+
+	if _, err = t.tx.Exec(foreignCountStr, placeID); err != nil {
+		return fmt.Errorf("failed to update place of %q: %v", *b.URL, err)
+	}
+
+	return nil
+}
+
+// insertRecord inserts the record at the end of folder, and returns its ID
+func (t *importTarget) insertRecord(recordType int, placeID any, parent int, b bookmark.Bookmark) (int, error) {
+	var (
+		dateAdded    = bookmark.ToPRTime(b.DateAdded)
+		lastModified = bookmark.ToPRTime(b.LastModified)
+	)
+
+	guid, err := t.guid(b.GUID)
+	if err != nil {
+		return 0, err
+	}
+
+	if dateAdded == 0 {
+		dateAdded = t.now
+	}
+
+	if lastModified < dateAdded {
+		lastModified = dateAdded
+	}
+
+	res, err := t.tx.Exec(insertBookmarkStr, recordType, placeID, parent, t.positions[parent], b.Title,
+		dateAdded, lastModified, guid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert %q: %v", b.Title, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch ID of %q: %v", b.Title, err)
+	}
+
+	t.positions[parent]++
+
+	return int(id), nil
+}
+
+// ensurePlace returns the ID of place with the URL of bookmark, inserting the place if missing
+func (t *importTarget) ensurePlace(b bookmark.Bookmark) (int64, error) {
+	var (
+		id      int64
+		columns = []string{"url", "title", "rev_host", "hidden", "typed", "frecency", "guid", "url_hash",
+			"foreign_count"}
+
+		hash = URLHash(*b.URL)
+	)
+
+	err := t.tx.QueryRow(placeQueryStr, hash, *b.URL).Scan(&id)
+	if err == nil {
+		// When the URL is already a place, e.g., visited or bookmarked elsewhere
+		return id, nil
+	} else if err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to query place of %q: %v", *b.URL, err)
+	}
+
+	guid, err := t.guid(b.PlaceGUID)
+	if err != nil {
+		return 0, err
+	}
+
+	values := []any{*b.URL, b.Title, revHost(*b.URL), 0, 0, -1, guid, hash, 0}
+
+	if t.placeColumns["recalc_frecency"] {
+		// Firefox recalculates frecency of the place
+		columns, values = append(columns, "recalc_frecency"), append(values, 1)
+	}
+
+	if t.placeColumns["origin_id"] && len(t.originColumns) > 0 {
+		// Newer versions of Firefox link the places to origins
+		originID, err := t.ensureOrigin(*b.URL)
+		if err != nil {
+			return 0, err
+		}
+
+		if originID != 0 {
+			columns, values = append(columns, "origin_id"), append(values, originID)
+		}
+	}
+
+	query := fmt.Sprintf("INSERT INTO moz_places (%s) VALUES (%s)", strings.Join(columns, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+
+	res, err := t.tx.Exec(query, values...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert place of %q: %v", *b.URL, err)
+	}
+
+	return res.LastInsertId()
+}
+
+// ensureOrigin returns the ID of origin, i.e., prefix and host of the URL, inserting the origin if missing.
+// The URLs without host have no origin.
+func (t *importTarget) ensureOrigin(rawURL string) (int64, error) {
+	var (
+		id      int64
+		columns = []string{"prefix", "host", "frecency"}
+	)
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return 0, nil
+	}
+
+	prefix, host := parsed.Scheme+"://", strings.ToLower(parsed.Host)
+	values := []any{prefix, host, 0}
+
+	if t.originColumns["recalc_frecency"] {
+		columns, values = append(columns, "recalc_frecency"), append(values, 1)
+	}
+
+	query := fmt.Sprintf("INSERT OR IGNORE INTO moz_origins (%s) VALUES (%s)", strings.Join(columns, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+
+	if _, err = t.tx.Exec(query, values...); err != nil {
+		return 0, fmt.Errorf("failed to insert origin of %q: %v", rawURL, err)
+	}
+
+	if err = t.tx.QueryRow(originQueryStr, prefix, host).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to query origin of %q: %v", rawURL, err)
+	}
+
+	return id, nil
+}
+
+// guid returns the preferred GUID when it is valid and unused, else generates new GUID
+func (t *importTarget) guid(preferred string) (string, error) {
+	guid := preferred
+
+	for !guidPattern.MatchString(guid) || t.guids[guid] {
+		random := make([]byte, guidBytes)
+		if _, err := rand.Read(random); err != nil {
+			return "", fmt.Errorf("failed to generate GUID: %v", err)
+		}
+
+		guid = base64.RawURLEncoding.EncodeToString(random)
+	}
+
+	t.guids[guid] = true
+
+	return guid, nil
+}
+
+// revHost returns rev_host of moz_places, i.e., the host of URL reversed, and suffixed with a dot
+func revHost(rawURL string) string {
+	var host string
+
+	if parsed, err := url.Parse(rawURL); err == nil {
+		host = strings.ToLower(parsed.Hostname())
+	}
+
+	reversed := []rune(host)
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+
+	return string(reversed) + "."
+}
+
+// firstOf returns the first title in path, and blank for empty path
+func firstOf(path []string) string {
+	if len(path) == 0 {
+		return ""
+	}
+
+	return path[0]
+}
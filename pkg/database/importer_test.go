@@ -0,0 +1,235 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/database/sqlite"
+)
+
+// targetSchema is the subset of Firefox's places.sqlite schema written by importer,
+// with the built-in roots and a bookmark in toolbar
+var targetSchema = []string{
+	`CREATE TABLE moz_places (id INTEGER PRIMARY KEY, url LONGVARCHAR, title LONGVARCHAR, rev_host LONGVARCHAR,
+	visit_count INTEGER DEFAULT 0, hidden INTEGER DEFAULT 0 NOT NULL, typed INTEGER DEFAULT 0 NOT NULL,
+	frecency INTEGER DEFAULT -1 NOT NULL, last_visit_date INTEGER, guid TEXT, foreign_count INTEGER DEFAULT 0 NOT NULL,
+	url_hash INTEGER DEFAULT 0 NOT NULL, origin_id INTEGER, recalc_frecency INTEGER NOT NULL DEFAULT 0)`,
+	`CREATE TABLE moz_bookmarks (id INTEGER PRIMARY KEY, type INTEGER, fk INTEGER DEFAULT NULL, parent INTEGER,
+	position INTEGER, title LONGVARCHAR, keyword_id INTEGER, folder_type TEXT, dateAdded INTEGER, lastModified INTEGER,
+	guid TEXT, syncStatus INTEGER NOT NULL DEFAULT 0, syncChangeCounter INTEGER NOT NULL DEFAULT 1)`,
+	`CREATE TABLE moz_keywords (id INTEGER PRIMARY KEY AUTOINCREMENT, keyword TEXT UNIQUE, place_id INTEGER,
+	post_data TEXT)`,
+	`CREATE TABLE moz_origins (id INTEGER PRIMARY KEY, prefix TEXT NOT NULL, host TEXT NOT NULL,
+	frecency INTEGER NOT NULL, recalc_frecency INTEGER NOT NULL DEFAULT 0, UNIQUE (prefix, host))`,
+	`INSERT INTO moz_bookmarks (id, type, parent, position, title, guid) VALUES
+	(1, 2, 0, 0, '', 'root________'), (2, 2, 1, 0, 'menu', 'menu________'), (3, 2, 1, 1, 'toolbar', 'toolbar_____'),
+	(4, 2, 1, 2, 'tags', 'tags________'), (5, 2, 1, 3, 'unfiled', 'unfiled_____'), (6, 2, 1, 4, 'mobile', 'mobile______')`,
+	`INSERT INTO moz_places (id, url, title, guid, url_hash, foreign_count) VALUES
+	(1, 'https://go.dev/', 'Go', 'kIv6j4vXyYzU', 47358286887113, 1)`,
+	`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, guid) VALUES
+	(7, 1, 1, 3, 0, 'Go', 'Lqk2dvFzb0Ju')`,
+}
+
+// newTarget creates places.sqlite with target schema in temp directory
+func newTarget(t *testing.T) string {
+	filename := filepath.Join(t.TempDir(), "places.sqlite")
+
+	conn, err := sql.Open("sqlite3", filename)
+	require.NoError(t, err, "Failed to create target DB")
+	defer conn.Close()
+
+	for _, query := range targetSchema {
+		_, err = conn.Exec(query)
+		require.NoError(t, err, "Failed to prepare target DB")
+	}
+
+	return filename
+}
+
+// targetRecord is the record in target, with the titles of its folders
+type targetRecord struct {
+	Path     string
+	Title    string
+	URL      string
+	Position int
+}
+
+// readTarget reads the non-root records in target, ordered by parent and position
+func readTarget(t *testing.T, filename string) []targetRecord {
+	var (
+		record  targetRecord
+		url     sql.NullString
+		records []targetRecord
+	)
+
+	conn, err := sql.Open("sqlite3", filename)
+	require.NoError(t, err, "Failed to open target DB")
+	defer conn.Close()
+
+	rows, err := conn.Query(`WITH RECURSIVE paths(id, path) AS (
+		SELECT id, title FROM moz_bookmarks WHERE parent = 1
+		UNION ALL SELECT b.id, paths.path || '/' || b.title FROM moz_bookmarks b JOIN paths ON b.parent = paths.id)
+	SELECT parent.path, b.title, p.url, b.position FROM moz_bookmarks b
+	JOIN paths parent ON b.parent = parent.id LEFT JOIN moz_places p ON b.fk = p.id
+	ORDER BY parent.path, b.position`)
+	require.NoError(t, err, "Failed to query target DB")
+	defer rows.Close()
+
+	for rows.Next() {
+		require.NoError(t, rows.Scan(&record.Path, &record.Title, &url, &record.Position), "Failed to scan target DB")
+		record.URL = url.String
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestImporter_Import(t *testing.T) {
+	var (
+		ptrStr = func(s string) *string { return &s }
+		added  = time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC)
+
+		// Raw export, i.e., records with ID and Parent fields
+		raw = []bookmark.Bookmark{
+			{ID: 1, Parent: 0, Title: "", Type: bookmark.TypeFolder},
+			{ID: 3, Parent: 1, Title: "toolbar", Type: bookmark.TypeFolder, Position: 1},
+			{ID: 4, Parent: 1, Title: "tags", Type: bookmark.TypeFolder, Position: 2},
+			{ID: 7, Parent: 3, Title: "GitHub", Type: bookmark.TypeFolder, Position: 2, GUID: "ghfolder0001"},
+			{ID: 8, Parent: 7, Title: "Vague Coder", URL: ptrStr("https://github.com/vaguecoder"),
+				Type: bookmark.TypeBookmark, Position: 1, Keyword: "vc", DateAdded: added},
+			{ID: 9, Parent: 3, Title: "Go", URL: ptrStr("https://go.dev/"), Type: bookmark.TypeBookmark, Position: 0},
+			{ID: 10, Parent: 4, Title: "golang", Type: bookmark.TypeFolder},
+			{ID: 11, Parent: 10, URL: ptrStr("https://go.dev/"), Type: bookmark.TypeBookmark},
+			{ID: 12, Parent: 3, Type: bookmark.TypeSeparator, Position: 1},
+			{ID: 13, Parent: 7, Title: "Firefox", URL: ptrStr("https://www.mozilla.org/firefox/"),
+				Type: bookmark.TypeBookmark, Position: 0},
+		}
+
+		// Denormalized export, i.e., records with folder paths
+		denormalized = []bookmark.Bookmark{
+			{Folder: "toolbar/GitHub", Title: "Firefox", URL: ptrStr("https://www.mozilla.org/firefox/"),
+				Type: bookmark.TypeBookmark, Position: 0},
+			{Folder: "toolbar/GitHub", Title: "Vague Coder", URL: ptrStr("https://github.com/vaguecoder"),
+				Type: bookmark.TypeBookmark, Position: 1, Keyword: "vc", DateAdded: added},
+			{Folder: "toolbar", Title: "Go", URL: ptrStr("https://go.dev/"), Type: bookmark.TypeBookmark},
+			{Folder: "Other Machine/Reading", Title: "Go Blog", URL: ptrStr("https://go.dev/blog/"),
+				Type: bookmark.TypeBookmark},
+		}
+	)
+
+	tests := []struct {
+		name      string
+		bookmarks []bookmark.Bookmark
+		want      []targetRecord
+		result    ImportResult
+	}{
+		{
+			name:      "Raw-Export",
+			bookmarks: raw,
+			want: []targetRecord{
+				{Path: "toolbar", Title: "Go", URL: "https://go.dev/", Position: 0},
+				{Path: "toolbar", Title: "GitHub", Position: 1},
+				{Path: "toolbar/GitHub", Title: "Firefox", URL: "https://www.mozilla.org/firefox/", Position: 0},
+				{Path: "toolbar/GitHub", Title: "Vague Coder", URL: "https://github.com/vaguecoder", Position: 1},
+			},
+			result: ImportResult{Folders: 1, Bookmarks: 2, Skipped: 1},
+		},
+		{
+			name:      "Denormalized-Export",
+			bookmarks: denormalized,
+			want: []targetRecord{
+				{Path: "toolbar", Title: "Go", URL: "https://go.dev/", Position: 0},
+				{Path: "toolbar", Title: "GitHub", Position: 1},
+				{Path: "toolbar/GitHub", Title: "Firefox", URL: "https://www.mozilla.org/firefox/", Position: 0},
+				{Path: "toolbar/GitHub", Title: "Vague Coder", URL: "https://github.com/vaguecoder", Position: 1},
+				{Path: "unfiled", Title: "Other Machine", Position: 0},
+				{Path: "unfiled/Other Machine", Title: "Reading", Position: 0},
+				{Path: "unfiled/Other Machine/Reading", Title: "Go Blog", URL: "https://go.dev/blog/", Position: 0},
+			},
+			result: ImportResult{Folders: 3, Bookmarks: 3, Skipped: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := newTarget(t)
+
+			conn, err := sqlite.NewWriteDB(filename)
+			require.NoError(t, err, "Unexpected error from opening target")
+			defer conn.Close()
+
+			importer := NewImporter(conn)
+
+			result, err := importer.Import(context.Background(), tt.bookmarks)
+			require.NoError(t, err, "Unexpected error from import")
+			assert.Equal(t, tt.result, result, "Mismatch of import result")
+			assert.Equal(t, tt.want, readTarget(t, filename), "Mismatch of records in target")
+
+			// Importing again finds all the records in target
+			result, err = importer.Import(context.Background(), tt.bookmarks)
+			require.NoError(t, err, "Unexpected error from repeated import")
+			assert.Equal(t, ImportResult{Skipped: tt.result.Bookmarks + tt.result.Skipped}, result,
+				"Mismatch of repeated import result")
+			assert.Equal(t, tt.want, readTarget(t, filename), "Mismatch of records in target after repeated import")
+		})
+	}
+}
+
+func TestImporter_Import_Places(t *testing.T) {
+	var (
+		guid, placeGUID, revHost, keyword    string
+		urlHash, foreignCount, dateAdded     int64
+		recalcFrecency                       int
+		originPrefix, originHost, folderGUID string
+
+		url      = "https://github.com/vaguecoder"
+		added    = time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC)
+		filename = newTarget(t)
+	)
+
+	conn, err := sqlite.NewWriteDB(filename)
+	require.NoError(t, err, "Unexpected error from opening target")
+	defer conn.Close()
+
+	_, err = NewImporter(conn).Import(context.Background(), []bookmark.Bookmark{
+		{ID: 7, Parent: 2, Title: "GitHub", Type: bookmark.TypeFolder, GUID: "Lqk2dvFzb0Ju"}, // GUID in use
+		{ID: 8, Parent: 7, Title: "Vague Coder", URL: &url, Type: bookmark.TypeBookmark, Keyword: "vc",
+			GUID: "8rLXK7Gl2ayV", PlaceGUID: "not-a-guid", DateAdded: added},
+		{ID: 2, Parent: 1, Title: "menu", Type: bookmark.TypeFolder},
+	})
+	require.NoError(t, err, "Unexpected error from import")
+
+	check, err := sql.Open("sqlite3", filename)
+	require.NoError(t, err, "Failed to open target DB")
+	defer check.Close()
+
+	err = check.QueryRow(`SELECT b.guid, b.dateAdded, p.guid, p.rev_host, p.url_hash, p.foreign_count,
+	p.recalc_frecency, k.keyword, o.prefix, o.host FROM moz_bookmarks b JOIN moz_places p ON b.fk = p.id
+	JOIN moz_keywords k ON k.place_id = p.id JOIN moz_origins o ON p.origin_id = o.id WHERE p.url = ?`, url).
+		Scan(&guid, &dateAdded, &placeGUID, &revHost, &urlHash, &foreignCount, &recalcFrecency, &keyword,
+			&originPrefix, &originHost)
+	require.NoError(t, err, "Failed to query imported bookmark")
+
+	assert.Equal(t, "8rLXK7Gl2ayV", guid, "Mismatch of bookmark GUID")
+	assert.Equal(t, bookmark.ToPRTime(added), dateAdded, "Mismatch of date added")
+	assert.Regexp(t, `^[a-zA-Z0-9_-]{12}$`, placeGUID, "Invalid generated place GUID")
+	assert.Equal(t, "moc.buhtig.", revHost, "Mismatch of reversed host")
+	assert.Equal(t, URLHash(url), urlHash, "Mismatch of URL hash")
+	assert.Equal(t, int64(2), foreignCount, "Mismatch of foreign count, i.e., bookmark and keyword")
+	assert.Equal(t, 1, recalcFrecency, "Frecency is not marked for recalculation")
+	assert.Equal(t, "vc", keyword, "Mismatch of keyword")
+	assert.Equal(t, "https://", originPrefix, "Mismatch of origin prefix")
+	assert.Equal(t, "github.com", originHost, "Mismatch of origin host")
+
+	err = check.QueryRow(`SELECT guid FROM moz_bookmarks WHERE title = 'GitHub'`).Scan(&folderGUID)
+	require.NoError(t, err, "Failed to query imported folder")
+
+	assert.NotEqual(t, "Lqk2dvFzb0Ju", folderGUID, "GUID in use is reused")
+	assert.Regexp(t, `^[a-zA-Z0-9_-]{12}$`, folderGUID, "Invalid generated folder GUID")
+}
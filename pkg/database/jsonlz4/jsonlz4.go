@@ -0,0 +1,62 @@
+package jsonlz4
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/database"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/mozlz4"
+)
+
+// BackupOperator reads bookmarks from Firefox's native
+// bookmarkbackups/bookmarks-YYYY-MM-DD_N_<hash>.jsonlz4 files
+type BackupOperator struct {
+	filename string
+}
+
+// NewBackupOperator initializes new BackupOperator on the jsonlz4 file
+func NewBackupOperator(filename string) database.BookmarkOperator {
+	return &BackupOperator{
+		filename: filename,
+	}
+}
+
+// GetBookmarks decodes the jsonlz4 file and returns the bookmarks,
+// in the same form as the records fetched from places.sqlite
+func (b *BackupOperator) GetBookmarks(ctx context.Context) ([]bookmark.Bookmark, error) {
+	var (
+		err                error
+		data, decompressed []byte
+		root               bookmark.BackupNode
+
+		logger = logs.FromContext(ctx).With().Str("filename", b.filename).Logger()
+	)
+
+	data, err = os.ReadFile(b.filename)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to read backup file")
+		return nil, fmt.Errorf("failed to read backup file %q: %v", b.filename, err)
+	}
+
+	decompressed, err = mozlz4.Decompress(data)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to decompress backup file")
+		return nil, fmt.Errorf("failed to decompress backup file %q: %v", b.filename, err)
+	}
+
+	if err = json.Unmarshal(decompressed, &root); err != nil {
+		logger.Error().Err(err).Msg("Failed to unmarshal backup JSON")
+		return nil, fmt.Errorf("failed to unmarshal backup JSON in %q: %v", b.filename, err)
+	}
+
+	bookmarks := root.Bookmarks()
+
+	logger.Debug().Interface("bookmarks", bookmarks).Msg("Resultant bookmarks")
+	logger.Info().Msg("Successfully decoded backup file")
+
+	return bookmarks, nil
+}
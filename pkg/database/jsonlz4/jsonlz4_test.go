@@ -0,0 +1,104 @@
+package jsonlz4
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+var ptrStr = util.PtrStr
+
+// compress wraps data as a mozlz4 file with a single literals-only LZ4 sequence
+func compress(data string) []byte {
+	var (
+		size   = len(data)
+		result = []byte("mozLz40\x00")
+	)
+
+	result = append(result, byte(size), byte(size>>8), byte(size>>16), byte(size>>24))
+
+	// Token with extended literal length, followed by the length bytes
+	result = append(result, 0xf0)
+	for remaining := size - 15; ; remaining -= 0xff {
+		if remaining < 0xff {
+			result = append(result, byte(remaining))
+			break
+		}
+		result = append(result, 0xff)
+	}
+
+	return append(result, data...)
+}
+
+func TestBackupOperator_GetBookmarks(t *testing.T) {
+	var (
+		ctx = context.Background()
+		dir = t.TempDir()
+	)
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    []bookmark.Bookmark
+		wantErr bool
+	}{
+		{
+			name: "Valid-Case",
+			content: compress(`{"guid":"root________","title":"","index":0,"id":1,"typeCode":2,` +
+				`"type":"text/x-moz-place-container","root":"placesRoot","children":[` +
+				`{"guid":"menu________","title":"menu","index":0,"id":2,"typeCode":2,` +
+				`"type":"text/x-moz-place-container","root":"bookmarksMenuFolder","children":[` +
+				`{"guid":"8rLXK7Gl2ayV","title":"Vague Coder","index":0,"dateAdded":1677929826000000,"id":6,"typeCode":1,` +
+				`"type":"text/x-moz-place","uri":"https://github.com/vaguecoder","keyword":"vc"}]}]}`),
+			want: []bookmark.Bookmark{
+				{URL: nil, Title: "", ID: 1, Parent: 0, GUID: "root________", Type: bookmark.TypeFolder},
+				{URL: nil, Title: "menu", ID: 2, Parent: 1, GUID: "menu________", Type: bookmark.TypeFolder},
+				{
+					URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 6, Parent: 2,
+					GUID: "8rLXK7Gl2ayV", Type: bookmark.TypeBookmark, Keyword: "vc",
+					DateAdded: time.Date(2023, 3, 4, 11, 37, 6, 0, time.UTC),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Not-Compressed",
+			content: []byte(`{"guid":"root________"}`),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Invalid-JSON",
+			content: compress(`{"guid":"root________", "children":[}`),
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, tt.name+".jsonlz4")
+			require.NoError(t, os.WriteFile(filename, tt.content, 0600), "Unexpected error while writing test file")
+
+			got, err := NewBackupOperator(filename).GetBookmarks(ctx)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from GetBookmarks")
+			} else {
+				assert.NoError(t, err, "Unexpected error from GetBookmarks")
+			}
+
+			assert.Equal(t, tt.want, got, "Mismatch of bookmarks")
+		})
+	}
+
+	t.Run("Failure-Missing-File", func(t *testing.T) {
+		_, err := NewBackupOperator(filepath.Join(dir, "missing.jsonlz4")).GetBookmarks(ctx)
+		assert.Error(t, err, "Expected error from GetBookmarks")
+	})
+}
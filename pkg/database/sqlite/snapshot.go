@@ -0,0 +1,248 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+// Suffix of SQLite's write-ahead log file
+const walSuffix = `-wal`
+
+var (
+	// Time (in milliseconds) to wait on the locks held by Firefox, before giving up
+	busyTimeout = 5000
+
+	// Attempts to copy the locked database, while Firefox keeps writing to it
+	copyAttempts = 3
+)
+
+// Snapshot writes a consistent copy of the SQLite database src to dest, using VACUUM INTO.
+// The source is opened read-only, and the copy includes the transactions in write-ahead log
+// (the -wal file), that are not yet checkpointed into the database file.
+// The snapshot is a single file in rollback journal mode, and dest must not exist.
+//
+// When Firefox holds exclusive lock on the source, the snapshot is taken without locking
+// (immutable=1) if the write-ahead log is empty, as the database file holds every transaction then.
+// Otherwise, as the last resort, the database and its -wal file are copied to copyFile and
+// the snapshot is taken from that copy. The copy is retried when the files change while being copied.
+// copyFile is owned by the caller, and is removed along with its -wal file by the caller.
+func Snapshot(ctx context.Context, src, dest, copyFile string) error {
+	if _, err := os.Stat(dest); err == nil {
+		// VACUUM INTO refuses non-empty files, and the file isn't overwritten either way
+		return fmt.Errorf("snapshot file %q already exists", dest)
+	}
+
+	err := vacuumInto(readOnlyURI(src), dest)
+	if isLocked(err) {
+		// When the source is locked exclusively, i.e., while Firefox is running
+		err = snapshotLocked(ctx, src, dest, copyFile)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to snapshot %q to %q: %v", src, dest, err)
+	}
+
+	return nil
+}
+
+// vacuumInto writes the database to dest, and switches dest to rollback journal mode,
+// so the snapshot doesn't need -wal or -shm files alongside
+func vacuumInto(srcDSN, dest string) error {
+	conn, err := sql.Open(sqliteDriverName, srcDSN)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.Exec("VACUUM INTO ?", dest); err != nil {
+		// Remove the partially written snapshot, if any
+		_ = os.Remove(dest)
+		return err
+	}
+
+	snapshot, err := sql.Open(sqliteDriverName, dest)
+	if err != nil {
+		return err
+	}
+	defer snapshot.Close()
+
+	if _, err = snapshot.Exec("PRAGMA journal_mode = DELETE"); err != nil {
+		return fmt.Errorf("failed to change journal mode of snapshot: %v", err)
+	}
+
+	return nil
+}
+
+// snapshotLocked takes the snapshot of the exclusively locked source, without locking
+// when its write-ahead log is empty, or from a copy of the database and its -wal file otherwise
+func snapshotLocked(ctx context.Context, src, dest, copyFile string) error {
+	logger := logs.FromContext(ctx).With().Str("input-sqlite-file", src).Logger()
+
+	before, err := statDB(src)
+	if err != nil {
+		return err
+	}
+
+	if before.walSize == 0 {
+		// When every transaction is checkpointed into the database file
+		if err = vacuumInto(fileURI(src, "immutable=1"), dest); err != nil {
+			return err
+		}
+
+		if after, err := statDB(src); err == nil && after == before {
+			// When the database file didn't change while being read
+			logger.Info().Msg("Took snapshot of locked database without locking")
+			return nil
+		}
+
+		// The database file changed while being read, so the snapshot is discarded
+		_ = os.Remove(dest)
+	}
+
+	logger.Warn().Str("copy-file", copyFile).
+		Msg("Database is locked with uncheckpointed transactions, taking snapshot from a copy of its files")
+
+	if err = copyDB(src, copyFile); err != nil {
+		return err
+	}
+
+	// The write-ahead log is replayed on the copy
+	return vacuumInto(copyFile, dest)
+}
+
+// dbStat is the size and modification time of the database and its -wal file
+type dbStat struct {
+	size, walSize       int64
+	modTime, walModTime time.Time
+}
+
+// statDB returns the size and modification time of the database and its -wal file.
+// A missing -wal file is same as an empty one.
+func statDB(filename string) (dbStat, error) {
+	var result dbStat
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return dbStat{}, fmt.Errorf("failed to stat %q: %v", filename, err)
+	}
+
+	result.size, result.modTime = info.Size(), info.ModTime()
+
+	info, err = os.Stat(filename + walSuffix)
+	if err == nil {
+		result.walSize, result.walModTime = info.Size(), info.ModTime()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return dbStat{}, fmt.Errorf("failed to stat %q: %v", filename+walSuffix, err)
+	}
+
+	return result, nil
+}
+
+// copyDB copies the database and its -wal file to dest, retrying when either of them
+// changed while being copied, so the copy isn't torn between two transactions
+func copyDB(src, dest string) error {
+	for attempt := 1; ; attempt++ {
+		before, err := statDB(src)
+		if err != nil {
+			return err
+		}
+
+		if err = copyDBFiles(src, dest, before.walSize > 0); err != nil {
+			return err
+		}
+
+		after, err := statDB(src)
+		if err != nil {
+			return err
+		}
+
+		if after == before {
+			// When the files didn't change while being copied
+			return nil
+		}
+
+		if attempt == copyAttempts {
+			return fmt.Errorf("%q kept changing while being copied, in %d attempts", src, copyAttempts)
+		}
+	}
+}
+
+// copyDBFiles copies the database, and its -wal file if any, replacing the earlier copies
+func copyDBFiles(src, dest string, hasWAL bool) error {
+	for _, filename := range []string{dest, dest + walSuffix} {
+		if err := os.Remove(filename); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove earlier copy %q: %v", filename, err)
+		}
+	}
+
+	if err := copyFile(src, dest); err != nil {
+		return err
+	}
+
+	if !hasWAL {
+		return nil
+	}
+
+	return copyFile(src+walSuffix, dest+walSuffix)
+}
+
+// readOnlyURI returns the URI filename opening the database read-only
+func readOnlyURI(filename string) string {
+	return fileURI(filename, fmt.Sprintf("mode=ro&_busy_timeout=%d", busyTimeout))
+}
+
+// fileURI returns the URI filename of the database with the query parameters
+func fileURI(filename, params string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+
+	// Characters with special meaning in URI filenames
+	filename = strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23").Replace(filepath.ToSlash(filename))
+
+	return fmt.Sprintf("file:%s?%s", filename, params)
+}
+
+// isLocked reports whether the error is due to the locks on database
+func isLocked(err error) bool {
+	var sqliteErr sqlite3.Error
+
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
+
+// copyFile copies the file as is
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %v", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %q: %v", dest, err)
+	}
+
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %q to %q: %v", src, dest, err)
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+// newWALDB creates the database in WAL mode with the titles committed, but not checkpointed.
+// The connection is returned open, as closing the last connection checkpoints the log.
+func newWALDB(t *testing.T, filename string, exclusive, checkpointed bool, titles ...string) *sql.DB {
+	conn, err := sql.Open(sqliteDriverName, filename)
+	require.NoError(t, err, "Failed to open test DB")
+	conn.SetMaxOpenConns(1)
+
+	pragmas := []string{"PRAGMA journal_mode = WAL", "PRAGMA wal_autocheckpoint = 0"}
+	if exclusive {
+		// Firefox's lock on places.sqlite
+		pragmas = append(pragmas, "PRAGMA locking_mode = EXCLUSIVE")
+	}
+
+	for _, query := range append(pragmas, "CREATE TABLE moz_bookmarks (title TEXT)") {
+		_, err = conn.Exec(query)
+		require.NoError(t, err, "Failed to prepare test DB with %q", query)
+	}
+
+	for _, title := range titles {
+		_, err = conn.Exec("INSERT INTO moz_bookmarks (title) VALUES (?)", title)
+		require.NoError(t, err, "Failed to insert into test DB")
+	}
+
+	if checkpointed {
+		// The log is emptied, while the connection still holds the lock
+		_, err = conn.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+		require.NoError(t, err, "Failed to checkpoint test DB")
+	}
+
+	return conn
+}
+
+// readTitles reads the titles from the snapshot
+func readTitles(t *testing.T, filename string) []string {
+	var (
+		title  string
+		titles []string
+	)
+
+	conn, err := sql.Open(sqliteDriverName, filename)
+	require.NoError(t, err, "Failed to open snapshot")
+	defer conn.Close()
+
+	rows, err := conn.Query("SELECT title FROM moz_bookmarks")
+	require.NoError(t, err, "Failed to query snapshot")
+	defer rows.Close()
+
+	for rows.Next() {
+		require.NoError(t, rows.Scan(&title), "Failed to scan snapshot")
+		titles = append(titles, title)
+	}
+
+	return titles
+}
+
+func TestSnapshot(t *testing.T) {
+	// Locks are retried only briefly, to keep the test fast
+	defer func(timeout int) { busyTimeout = timeout }(busyTimeout)
+	busyTimeout = 10
+
+	tests := []struct {
+		name         string
+		exclusive    bool
+		checkpointed bool
+		existing     bool
+		missingSrc   bool
+		want         []string
+		wantCopy     bool
+		wantErr      bool
+	}{
+		{
+			name:     "Uncheckpointed-WAL",
+			want:     []string{"Mozilla Firefox", "Get Involved"},
+			wantCopy: false,
+		},
+		{
+			name:      "Exclusive-Lock",
+			exclusive: true,
+			want:      []string{"Mozilla Firefox", "Get Involved"},
+			wantCopy:  true,
+		},
+		{
+			name:         "Exclusive-Lock-Checkpointed-WAL",
+			exclusive:    true,
+			checkpointed: true,
+			want:         []string{"Mozilla Firefox", "Get Involved"},
+			wantCopy:     false,
+		},
+		{
+			name:     "Failure-Existing-Destination",
+			existing: true,
+			wantErr:  true,
+		},
+		{
+			name:       "Failure-Missing-Source",
+			missingSrc: true,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				dir      = t.TempDir()
+				src      = filepath.Join(dir, "places.sqlite")
+				dest     = filepath.Join(dir, "snapshot.sqlite")
+				copyFile = filepath.Join(dir, "copy-places.sqlite")
+			)
+
+			if !tt.missingSrc {
+				conn := newWALDB(t, src, tt.exclusive, tt.checkpointed, "Mozilla Firefox", "Get Involved")
+				defer conn.Close()
+			}
+
+			if tt.existing {
+				require.NoError(t, os.WriteFile(dest, []byte("bookmarks"), 0600), "Failed to create destination")
+			}
+
+			// The fallbacks are logged, which aren't asserted here
+			ctx, _ := logs.SilentLogger(context.Background())
+
+			err := Snapshot(ctx, src, dest, copyFile)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from snapshot")
+
+				if tt.existing {
+					content, _ := os.ReadFile(dest)
+					assert.Equal(t, "bookmarks", string(content), "Existing destination is overwritten")
+				}
+				return
+			}
+
+			require.NoError(t, err, "Unexpected error from snapshot")
+			assert.Equal(t, tt.want, readTitles(t, dest), "Mismatch of rows in snapshot")
+			assert.NoFileExists(t, dest+walSuffix, "Snapshot isn't a single file")
+
+			if tt.wantCopy {
+				assert.FileExists(t, copyFile, "Expected snapshot from a copy")
+			} else {
+				assert.NoFileExists(t, copyFile, "Unexpected copy of source")
+			}
+		})
+	}
+}
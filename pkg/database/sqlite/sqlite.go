@@ -7,6 +7,7 @@ import (
 
 type DBConnection interface {
 	Query(query string, args ...any) (*sql.Rows, error)
+	Close() error
 }
 
 const sqliteDriverName = `sqlite3`
@@ -22,3 +23,44 @@ func NewDB(dbFilename string) (DBConnection, error) {
 	// When connection to DB was successful
 	return conn, nil
 }
+
+// DBWriter is the connection to SQLite DB, which also writes in transactions
+type DBWriter interface {
+	DBConnection
+	Begin() (*sql.Tx, error)
+}
+
+// NewWriteDB opens the existing SQLite DB for writing. The transactions lock the DB exclusively,
+// and fail right away when the DB is in use by another process, e.g., a running Firefox.
+func NewWriteDB(dbFilename string) (DBWriter, error) {
+	// Ignore connection err as ping returns the actual error here
+	conn, _ := sql.Open(sqliteDriverName, fileURI(dbFilename, "mode=rw&_txlock=exclusive&_busy_timeout=0"))
+
+	// A single connection, so the transactions and the queries don't lock each other
+	conn.SetMaxOpenConns(1)
+
+	if err := conn.Ping(); err != nil {
+		// When ping to DB failed
+		conn.Close()
+		return nil, fmt.Errorf("failed to ping SQLite DB: %v", err)
+	}
+
+	// Take the exclusive lock once, to fail early when the DB is in use
+	tx, err := conn.Begin()
+	if err != nil {
+		conn.Close()
+
+		if isLocked(err) {
+			return nil, fmt.Errorf("SQLite DB %q is in use, close Firefox and retry: %v", dbFilename, err)
+		}
+
+		return nil, fmt.Errorf("failed to lock SQLite DB %q: %v", dbFilename, err)
+	}
+
+	if err = tx.Rollback(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to release lock on SQLite DB %q: %v", dbFilename, err)
+	}
+
+	return conn, nil
+}
@@ -2,9 +2,13 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"path/filepath"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/vaguecoder/firefox-backups/pkg/files"
 	"github.com/vaguecoder/firefox-backups/pkg/util"
 )
@@ -70,3 +74,60 @@ func TestNewDB(t *testing.T) {
 		})
 	}
 }
+
+func TestNewWriteDB(t *testing.T) {
+	tests := []struct {
+		name    string
+		locked  bool
+		missing bool
+		wantErr bool
+	}{
+		{
+			name:    "Closed-DB",
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Locked-DB",
+			locked:  true,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Missing-DB",
+			missing: true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "places.sqlite")
+
+			if !tt.missing {
+				firefox, err := sql.Open(sqliteDriverName, filename)
+				require.NoError(t, err, "Failed to create test DB")
+				defer firefox.Close()
+
+				firefox.SetMaxOpenConns(1)
+
+				queries := []string{"CREATE TABLE moz_bookmarks (title TEXT)"}
+				if tt.locked {
+					// Firefox holds exclusive lock on places.sqlite while running
+					queries = append(queries, "PRAGMA locking_mode = EXCLUSIVE", "BEGIN EXCLUSIVE", "COMMIT")
+				}
+
+				for _, query := range queries {
+					_, err = firefox.Exec(query)
+					require.NoError(t, err, "Failed to prepare test DB with %q", query)
+				}
+			}
+
+			conn, err := NewWriteDB(filename)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from NewWriteDB")
+				return
+			}
+
+			require.NoError(t, err, "Unexpected error from NewWriteDB")
+			assert.NoError(t, conn.Close(), "Unexpected error from closing DB")
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"math/bits"
+	"strings"
+)
+
+const (
+	// Golden ratio constant of Mozilla's string hash
+	goldenRatioU32 = 0x9E3779B9
+
+	// Length of the head of URL, searched for the scheme
+	maxPrefixLength = 50
+)
+
+// URLHash returns the url_hash of moz_places, i.e., Firefox's hash() SQL function on the URL.
+// URLs with scheme are hashed into 48 bits: the hash of scheme in upper 16 bits,
+// and the hash of whole URL in lower 32 bits. Rest of the strings are hashed into 32 bits.
+func URLHash(url string) int64 {
+	var (
+		head    = url
+		urlHash = uint64(hashString(url))
+	)
+
+	if len(head) > maxPrefixLength {
+		head = head[:maxPrefixLength]
+	}
+
+	index := strings.IndexByte(head, ':')
+	if index < 0 {
+		// When the URL has no scheme
+		return int64(urlHash)
+	}
+
+	prefixHash := uint64(hashString(head[:index]) & 0x0000FFFF)
+
+	return int64(prefixHash<<32 + urlHash)
+}
+
+// hashString is Mozilla's HashString on the bytes of the string
+func hashString(s string) uint32 {
+	var hash uint32
+
+	for i := 0; i < len(s); i++ {
+		hash = goldenRatioU32 * (bits.RotateLeft32(hash, 5) ^ uint32(s[i]))
+	}
+
+	return hash
+}
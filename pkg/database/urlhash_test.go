@@ -0,0 +1,51 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLHash(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int64
+	}{
+		{
+			name:  "Empty",
+			input: "",
+			want:  0,
+		},
+		{
+			name:  "HTTPS",
+			input: "https://www.mozilla.org/",
+			want:  47358155560141,
+		},
+		{
+			name:  "HTTPS-Path",
+			input: "https://github.com/vaguecoder",
+			want:  47356899467322,
+		},
+		{
+			name:  "About",
+			input: "about:blank",
+			want:  175532304468422,
+		},
+		{
+			name:  "Without-Scheme",
+			input: "no scheme here",
+			want:  3962201030,
+		},
+		{
+			name:  "Scheme-Beyond-Prefix-Length",
+			input: "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx:y",
+			want:  1165879805,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, URLHash(tt.input), "Mismatch of URL hash")
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+)
+
+// Kind is the kind of change in a bookmark between two snapshots
+type Kind string
+
+const (
+	// Kinds of changes, in the order they are reported
+	Removed    Kind = `removed`
+	Added      Kind = `added`
+	Moved      Kind = `moved`
+	Retitled   Kind = `retitled`
+	URLChanged Kind = `url-changed`
+)
+
+// kindOrder is the order of kinds in the report
+var kindOrder = map[Kind]int{Removed: 0, Added: 1, Moved: 2, Retitled: 3, URLChanged: 4}
+
+// Change is a change in a bookmark, folder or separator between old and new snapshots.
+// The Old and New fields hold the changed value, i.e., folder path, title or URL per kind,
+// and only one of them is set for removed and added records.
+type Change struct {
+	Kind   Kind   `json:"kind"`
+	GUID   string `json:"guid"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Folder string `json:"folder"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// entry is a located record of a snapshot, marked once matched
+type entry struct {
+	bookmark.Located
+	matched bool
+}
+
+// Compare reports the changes from the old snapshot to the new snapshot.
+// The records are matched by GUID, falling back to URL for the records whose GUID
+// isn't in the other snapshot, e.g., exports of other tools or another profile.
+// The snapshots may be raw or denormalized, as the folder paths are rebuilt from
+// ID and Parent fields when the Folder field isn't set. The parents are compared by their GUIDs,
+// as the IDs differ across the profiles synced with each other, and the folder paths are compared
+// only when the parents aren't known. The tags are not compared.
+func Compare(oldBookmarks, newBookmarks []bookmark.Bookmark) []Change {
+	var (
+		changes []Change
+		match   *entry
+		ok      bool
+
+		oldEntries = entries(oldBookmarks)
+		newEntries = entries(newBookmarks)
+		byGUID     = map[string]*entry{}
+		byURL      = map[string][]*entry{}
+	)
+
+	for _, old := range oldEntries {
+		if old.GUID != "" {
+			byGUID[old.GUID] = old
+		}
+
+		if old.URL != nil {
+			byURL[*old.URL] = append(byURL[*old.URL], old)
+		}
+	}
+
+	// Match the records by GUID first, so the URL fallback doesn't take the records matching by GUID
+	unmatched := make([]*entry, 0, len(newEntries))
+	for _, current := range newEntries {
+		if match, ok = byGUID[current.GUID]; ok && current.GUID != "" && !match.matched {
+			match.matched = true
+			changes = append(changes, compareEntries(match, current)...)
+			continue
+		}
+
+		unmatched = append(unmatched, current)
+	}
+
+	for _, current := range unmatched {
+		if match = firstUnmatched(current, byURL); match != nil {
+			match.matched = true
+			changes = append(changes, compareEntries(match, current)...)
+			continue
+		}
+
+		changes = append(changes, newChange(Added, current, "", current.Path))
+	}
+
+	for _, old := range oldEntries {
+		if !old.matched {
+			changes = append(changes, newChange(Removed, old, old.Path, ""))
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return kindOrder[changes[i].Kind] < kindOrder[changes[j].Kind]
+		}
+
+		if changes[i].Folder != changes[j].Folder {
+			return changes[i].Folder < changes[j].Folder
+		}
+
+		return changes[i].Title < changes[j].Title
+	})
+
+	return changes
+}
+
+// Table converts the changes to 2D string table, with the header
+func Table(changes []Change) [][]string {
+	table := [][]string{{"CHANGE", "TITLE", "URL", "FOLDER", "OLD", "NEW"}}
+
+	for _, change := range changes {
+		table = append(table, []string{string(change.Kind), change.Title, change.URL, change.Folder,
+			change.Old, change.New})
+	}
+
+	return table
+}
+
+// compareEntries reports the changes between the matched records
+func compareEntries(old, current *entry) []Change {
+	var (
+		changes   []Change
+		oldURL    = urlOf(old.Bookmark)
+		newURL    = urlOf(current.Bookmark)
+		oldFolder = old.Path
+		newFolder = current.Path
+	)
+
+	if old.ParentGUID != "" && current.ParentGUID != "" {
+		// When the parents are known, the record moved only if its parent changed,
+		// so the records under a retitled folder aren't reported as moved
+		if old.ParentGUID != current.ParentGUID {
+			if oldFolder == newFolder {
+				// When the record moved to another folder of the same path
+				oldFolder = fmt.Sprintf("%s (parent %s)", oldFolder, old.ParentGUID)
+				newFolder = fmt.Sprintf("%s (parent %s)", newFolder, current.ParentGUID)
+			}
+
+			changes = append(changes, newChange(Moved, current, oldFolder, newFolder))
+		}
+	} else if oldFolder != newFolder {
+		// When the parents aren't known, e.g., with partial exports, the folder paths are compared
+		changes = append(changes, newChange(Moved, current, oldFolder, newFolder))
+	}
+
+	if strings.TrimSpace(old.Title) != strings.TrimSpace(current.Title) {
+		changes = append(changes, newChange(Retitled, current, old.Title, current.Title))
+	}
+
+	if oldURL != newURL {
+		changes = append(changes, newChange(URLChanged, current, oldURL, newURL))
+	}
+
+	return changes
+}
+
+// newChange creates the change of the kind for the record
+func newChange(kind Kind, e *entry, old, current string) Change {
+	return Change{
+		Kind:   kind,
+		GUID:   e.GUID,
+		Title:  e.Title,
+		URL:    urlOf(e.Bookmark),
+		Folder: e.Path,
+		Old:    old,
+		New:    current,
+	}
+}
+
+// firstUnmatched returns the first record with the same URL not matched yet, if any
+func firstUnmatched(current *entry, byURL map[string][]*entry) *entry {
+	if current.URL == nil {
+		// Folders and separators are matched only by GUID
+		return nil
+	}
+
+	for _, old := range byURL[*current.URL] {
+		if !old.matched {
+			return old
+		}
+	}
+
+	return nil
+}
+
+// entries lists the located records of the snapshot
+func entries(bookmarks []bookmark.Bookmark) []*entry {
+	located := bookmark.Locate(bookmarks)
+	list := make([]*entry, 0, len(located))
+
+	for _, l := range located {
+		list = append(list, &entry{Located: l})
+	}
+
+	return list
+}
+
+// urlOf returns the URL of the record, blank for folders and separators
+func urlOf(b bookmark.Bookmark) string {
+	if b.URL == nil {
+		return ""
+	}
+
+	return *b.URL
+}
@@ -0,0 +1,154 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+var ptrStr = util.PtrStr
+
+// snapshot returns the places root, menu and toolbar roots, and the records under them
+func snapshot(records ...bookmark.Bookmark) []bookmark.Bookmark {
+	return append([]bookmark.Bookmark{
+		{ID: 1, Parent: 0, GUID: "root________", Type: bookmark.TypeFolder},
+		{ID: 2, Parent: 1, GUID: "menu________", Title: bookmark.MenuRoot, Type: bookmark.TypeFolder},
+		{ID: 3, Parent: 1, GUID: "toolbar_____", Title: bookmark.ToolbarRoot, Type: bookmark.TypeFolder, Position: 1},
+		{ID: 4, Parent: 1, GUID: "tags________", Title: bookmark.TagsRoot, Type: bookmark.TypeFolder, Position: 2},
+	}, records...)
+}
+
+func TestCompare(t *testing.T) {
+	type args struct {
+		oldBookmarks []bookmark.Bookmark
+		newBookmarks []bookmark.Bookmark
+	}
+
+	var (
+		folder = bookmark.Bookmark{ID: 10, Parent: 3, GUID: "folder-guid1", Title: "Profiles", Type: bookmark.TypeFolder}
+		github = bookmark.Bookmark{ID: 11, Parent: 10, GUID: "bookmarkgd01", Title: "Vague Coder",
+			URL: ptrStr("https://github.com/vaguecoder"), Type: bookmark.TypeBookmark}
+		golang = bookmark.Bookmark{ID: 12, Parent: 2, GUID: "bookmarkgd02", Title: "Go",
+			URL: ptrStr("https://go.dev/"), Type: bookmark.TypeBookmark}
+		tag = bookmark.Bookmark{ID: 13, Parent: 4, GUID: "tag-folder01", Title: "code", Type: bookmark.TypeFolder}
+	)
+
+	// with returns copy of the record, modified
+	with := func(b bookmark.Bookmark, modify func(*bookmark.Bookmark)) bookmark.Bookmark {
+		modify(&b)
+		return b
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []Change
+	}{
+		{
+			name: "No-Changes",
+			args: args{
+				oldBookmarks: snapshot(folder, github, golang, tag),
+				newBookmarks: snapshot(folder, github, golang),
+			},
+			want: nil,
+		},
+		{
+			name: "Added-And-Removed",
+			args: args{
+				oldBookmarks: snapshot(folder, github),
+				newBookmarks: snapshot(folder, golang),
+			},
+			want: []Change{
+				{Kind: Removed, GUID: "bookmarkgd01", Title: "Vague Coder", URL: "https://github.com/vaguecoder",
+					Folder: "toolbar/Profiles", Old: "toolbar/Profiles"},
+				{Kind: Added, GUID: "bookmarkgd02", Title: "Go", URL: "https://go.dev/", Folder: "menu",
+					New: "menu"},
+			},
+		},
+		{
+			name: "Moved-Retitled-URL-Changed",
+			args: args{
+				oldBookmarks: snapshot(folder, github),
+				newBookmarks: snapshot(folder, with(github, func(b *bookmark.Bookmark) {
+					b.Parent = 2
+					b.Title = "GitHub"
+					b.URL = ptrStr("https://github.com/vaguecoder?tab=repositories")
+				})),
+			},
+			want: []Change{
+				{Kind: Moved, GUID: "bookmarkgd01", Title: "GitHub", URL: "https://github.com/vaguecoder?tab=repositories",
+					Folder: "menu", Old: "toolbar/Profiles", New: "menu"},
+				{Kind: Retitled, GUID: "bookmarkgd01", Title: "GitHub", URL: "https://github.com/vaguecoder?tab=repositories",
+					Folder: "menu", Old: "Vague Coder", New: "GitHub"},
+				{Kind: URLChanged, GUID: "bookmarkgd01", Title: "GitHub", URL: "https://github.com/vaguecoder?tab=repositories",
+					Folder: "menu", Old: "https://github.com/vaguecoder", New: "https://github.com/vaguecoder?tab=repositories"},
+			},
+		},
+		{
+			name: "Retitled-Folder-Does-Not-Move-Children",
+			args: args{
+				oldBookmarks: snapshot(folder, github),
+				newBookmarks: snapshot(with(folder, func(b *bookmark.Bookmark) { b.Title = "GitHub" }), github),
+			},
+			want: []Change{
+				{Kind: Retitled, GUID: "folder-guid1", Title: "GitHub", Folder: "toolbar", Old: "Profiles", New: "GitHub"},
+			},
+		},
+		{
+			name: "Fallback-To-URL-Across-Profiles",
+			args: args{
+				oldBookmarks: snapshot(folder, github),
+				newBookmarks: snapshot(
+					with(folder, func(b *bookmark.Bookmark) { b.ID, b.GUID = 20, "folder-guid2" }),
+					with(github, func(b *bookmark.Bookmark) { b.ID, b.Parent, b.GUID = 21, 20, "bookmarkgd03" }),
+				),
+			},
+			want: []Change{
+				{Kind: Removed, GUID: "folder-guid1", Title: "Profiles", Folder: "toolbar", Old: "toolbar"},
+				{Kind: Added, GUID: "folder-guid2", Title: "Profiles", Folder: "toolbar", New: "toolbar"},
+				{Kind: Moved, GUID: "bookmarkgd03", Title: "Vague Coder", URL: "https://github.com/vaguecoder",
+					Folder: "toolbar/Profiles", Old: "toolbar/Profiles (parent folder-guid1)",
+					New: "toolbar/Profiles (parent folder-guid2)"},
+			},
+		},
+		{
+			name: "Denormalized-Against-Raw",
+			args: args{
+				oldBookmarks: []bookmark.Bookmark{
+					with(github, func(b *bookmark.Bookmark) { b.Folder = "toolbar/Profiles" }),
+					with(golang, func(b *bookmark.Bookmark) { b.Folder = "menu" }),
+				},
+				newBookmarks: snapshot(folder, github, with(golang, func(b *bookmark.Bookmark) { b.Parent = 10 })),
+			},
+			want: []Change{
+				{Kind: Added, GUID: "menu________", Title: "menu", Folder: ""},
+				{Kind: Added, GUID: "toolbar_____", Title: "toolbar", Folder: ""},
+				{Kind: Added, GUID: "folder-guid1", Title: "Profiles", Folder: "toolbar", New: "toolbar"},
+				{Kind: Moved, GUID: "bookmarkgd02", Title: "Go", URL: "https://go.dev/", Folder: "toolbar/Profiles",
+					Old: "menu", New: "toolbar/Profiles"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.args.oldBookmarks, tt.args.newBookmarks)
+			assert.Equal(t, tt.want, got, "Mismatch of changes")
+		})
+	}
+}
+
+func TestTable(t *testing.T) {
+	changes := []Change{
+		{Kind: Retitled, GUID: "folder-guid1", Title: "GitHub", Folder: "toolbar", Old: "Profiles", New: "GitHub"},
+	}
+
+	want := [][]string{
+		{"CHANGE", "TITLE", "URL", "FOLDER", "OLD", "NEW"},
+		{"retitled", "GitHub", "", "toolbar", "Profiles", "GitHub"},
+	}
+
+	assert.Equal(t, want, Table(changes), "Mismatch of table")
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/vaguecoder/firefox-backups/pkg/constants"
 	"github.com/vaguecoder/firefox-backups/pkg/encoding"
 	"github.com/vaguecoder/firefox-backups/pkg/files"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
 )
 
 // EncoderName is name of the encoder in current package, i.e., CSV.
@@ -18,6 +20,9 @@ var EncoderName = encoding.ToEncoder(constants.CSVFormat)
 func init() {
 	// Register the encoder name in pkg/encoding.AllEncoders
 	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+
+	// Register the decoder name in pkg/encoding.AllDecoders
+	encoding.AllDecoders = append(encoding.AllDecoders, EncoderName)
 }
 
 // Encoder is the manager for CSV encoder
@@ -56,6 +61,30 @@ func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
 	return nil
 }
 
+// EncodeVisits encodes the input history visits in CSV format to already set output stream
+func (e *Encoder) EncodeVisits(visits []history.Visit) error {
+	records := history.VisitsTable(visits, e.enableHeader)
+
+	err := e.csvEncoder.WriteAll(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal CSV: %v", err)
+	}
+
+	return nil
+}
+
+// EncodeLinks encodes the input links of bookmarks in CSV format to already set output stream
+func (e *Encoder) EncodeLinks(checkedLinks []links.Link) error {
+	records := links.LinksTable(checkedLinks, e.enableHeader)
+
+	err := e.csvEncoder.WriteAll(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal CSV: %v", err)
+	}
+
+	return nil
+}
+
 // String returns the encoder name derived in EncoderName.
 // This returns the same value as EncoderName, but using the receiver.
 func (e *Encoder) String() string {
@@ -67,3 +96,36 @@ func (e *Encoder) String() string {
 func (e *Encoder) Filename() string {
 	return e.filename
 }
+
+// Decoder is the manager for CSV decoder
+type Decoder struct {
+	csvDecoder *csv.Reader
+}
+
+// NewDecoder initializes new Decoder
+func NewDecoder(in io.Reader) *Decoder {
+	return &Decoder{
+		csvDecoder: csv.NewReader(in),
+	}
+}
+
+// Decode decodes the bookmarks in CSV format from already set input stream.
+// The header, if any, is detected from the first record.
+func (d *Decoder) Decode() ([]bookmark.Bookmark, error) {
+	records, err := d.csvDecoder.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal CSV: %v", err)
+	}
+
+	bookmarks, err := bookmark.FromTable(records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal CSV: %v", err)
+	}
+
+	return bookmarks, nil
+}
+
+// String returns the decoder name, same as EncoderName
+func (d *Decoder) String() string {
+	return EncoderName.String()
+}
@@ -1,9 +1,11 @@
 package csv
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
@@ -86,8 +88,8 @@ func TestEncoder_Encode(t *testing.T) {
 				isErrorAtCSVWriteAll: false,
 			},
 			expected: []string{
-				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0`,
-				`https://github.com/random,Random,Profiles/GitHub,2,0`,
+				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0,,,0,0,,,,`,
+				`https://github.com/random,Random,Profiles/GitHub,2,0,,,0,0,,,,`,
 			},
 		},
 		{
@@ -116,8 +118,8 @@ func TestEncoder_Encode(t *testing.T) {
 				},
 			},
 			expected: []string{
-				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0`,
-				`https://github.com/random,Random,Profiles/GitHub,2,0`,
+				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0,,,0,0,,,,`,
+				`https://github.com/random,Random,Profiles/GitHub,2,0,,,0,0,,,,`,
 			},
 			toggles: toggles{
 				isExpectedError:      false,
@@ -190,10 +192,10 @@ func TestEncoder_Encode(t *testing.T) {
 				isErrorAtCSVWriteAll: false,
 			},
 			expected: []string{
-				`URL,TITLE,FOLDER,ID,PARENT`,
-				`---,-----,------,--,------`,
-				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0`,
-				`https://github.com/random,Random,Profiles/GitHub,2,0`,
+				`URL,TITLE,FOLDER,ID,PARENT,GUID,PLACE-GUID,TYPE,POSITION,KEYWORD,DATE-ADDED,LAST-MODIFIED,TAGS`,
+				`---,-----,------,--,------,----,----------,----,--------,-------,----------,-------------,----`,
+				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0,,,0,0,,,,`,
+				`https://github.com/random,Random,Profiles/GitHub,2,0,,,0,0,,,,`,
 			},
 		},
 		{
@@ -222,10 +224,10 @@ func TestEncoder_Encode(t *testing.T) {
 				},
 			},
 			expected: []string{
-				`URL,TITLE,FOLDER,ID,PARENT`,
-				`---,-----,------,--,------`,
-				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0`,
-				`https://github.com/random,Random,Profiles/GitHub,2,0`,
+				`URL,TITLE,FOLDER,ID,PARENT,GUID,PLACE-GUID,TYPE,POSITION,KEYWORD,DATE-ADDED,LAST-MODIFIED,TAGS`,
+				`---,-----,------,--,------,----,----------,----,--------,-------,----------,-------------,----`,
+				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0,,,0,0,,,,`,
+				`https://github.com/random,Random,Profiles/GitHub,2,0,,,0,0,,,,`,
 			},
 			toggles: toggles{
 				isExpectedError:      false,
@@ -293,10 +295,10 @@ func TestEncoder_Encode(t *testing.T) {
 				},
 			},
 			expected: []string{
-				`URL,TITLE,FOLDER,ID,PARENT`,
-				`---,-----,------,--,------`,
-				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0`,
-				`https://github.com/random,Random,Profiles/GitHub,2,0`,
+				`URL,TITLE,FOLDER,ID,PARENT,GUID,PLACE-GUID,TYPE,POSITION,KEYWORD,DATE-ADDED,LAST-MODIFIED,TAGS`,
+				`---,-----,------,--,------,----,----------,----,--------,-------,----------,-------------,----`,
+				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0,,,0,0,,,,`,
+				`https://github.com/random,Random,Profiles/GitHub,2,0,,,0,0,,,,`,
 			},
 			toggles: toggles{
 				isExpectedError:      true,
@@ -330,10 +332,10 @@ func TestEncoder_Encode(t *testing.T) {
 				},
 			},
 			expected: []string{
-				`URL,TITLE,FOLDER,ID,PARENT`,
-				`---,-----,------,--,------`,
-				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0`,
-				`https://github.com/random,Random,Profiles/GitHub,2,0`,
+				`URL,TITLE,FOLDER,ID,PARENT,GUID,PLACE-GUID,TYPE,POSITION,KEYWORD,DATE-ADDED,LAST-MODIFIED,TAGS`,
+				`---,-----,------,--,------,----,----------,----,--------,-------,----------,-------------,----`,
+				`https://github.com/vaguecoder,Vague Coder,Profiles/GitHub,1,0,,,0,0,,,,`,
+				`https://github.com/random,Random,Profiles/GitHub,2,0,,,0,0,,,,`,
 			},
 			toggles: toggles{
 				isExpectedError:      true,
@@ -377,3 +379,87 @@ func TestEncoder_Encode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecoder_Decode(t *testing.T) {
+	type testData struct {
+		name         string
+		bookmarks    []bookmark.Bookmark
+		enableHeader bool
+	}
+
+	var (
+		err       error
+		buffer    bytes.Buffer
+		decoded   []bookmark.Bookmark
+		testCase  testData
+		timestamp = time.Date(2023, time.March, 4, 5, 6, 7, 891011000, time.UTC)
+
+		bookmarks = []bookmark.Bookmark{
+			{
+				Title:        "Profiles",
+				Folder:       "Bookmarks Toolbar",
+				ID:           7,
+				Parent:       3,
+				GUID:         "folder-guid1",
+				Type:         bookmark.TypeFolder,
+				Position:     1,
+				DateAdded:    timestamp,
+				LastModified: timestamp.Add(time.Hour),
+			},
+			{
+				URL:          ptrStr("https://github.com/vaguecoder?tab=repositories"),
+				Title:        "Vague Coder · GitHub",
+				Folder:       "Bookmarks Toolbar/Profiles",
+				ID:           8,
+				Parent:       7,
+				GUID:         "bookmarkguid",
+				PlaceGUID:    "placeguid123",
+				Type:         bookmark.TypeBookmark,
+				Position:     0,
+				Keyword:      "gh",
+				DateAdded:    timestamp,
+				LastModified: timestamp,
+				Tags:         []string{"code", "profile"},
+			},
+			{
+				Folder:   "Bookmarks Toolbar/Profiles",
+				ID:       9,
+				Parent:   7,
+				GUID:     "separatorgd",
+				Type:     bookmark.TypeSeparator,
+				Position: 1,
+			},
+		}
+	)
+
+	tests := []testData{
+		{
+			name:         "Round-Trip-With-Header",
+			bookmarks:    bookmarks,
+			enableHeader: true,
+		},
+		{
+			name:      "Round-Trip-Without-Header",
+			bookmarks: bookmarks,
+		},
+		{
+			name:         "No-Bookmarks",
+			bookmarks:    nil,
+			enableHeader: true,
+		},
+	}
+
+	for _, testCase = range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			buffer.Reset()
+
+			err = NewEncoder(&buffer, testCase.enableHeader).Encode(testCase.bookmarks)
+			assert.NoErrorf(t, err, "Failed to encode bookmarks: %v", err)
+
+			decoded, err = NewDecoder(&buffer).Decode()
+			assert.NoErrorf(t, err, "Failed to decode bookmarks: %v", err)
+
+			assert.Equalf(t, testCase.bookmarks, decoded, "Mismatch in decoded bookmarks")
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
 )
 
 const encoderNamesDelimiter = `, `
@@ -36,11 +39,39 @@ func (e encoderNames) String() string {
 	return strings.Join(encoders, encoderNamesDelimiter)
 }
 
+// Without returns the encoder names, except the excluded encoder names
+func (e encoderNames) Without(excluded encoderNames) encoderNames {
+	var result encoderNames
+
+	for _, encoderName := range e {
+		isExcluded := false
+		for _, excludedName := range excluded {
+			isExcluded = isExcluded || encoderName == excludedName
+		}
+
+		if !isExcluded {
+			result = append(result, encoderName)
+		}
+	}
+
+	return result
+}
+
 // AllEncoders holds list of encoder names.
 // All the encoder names in echo of the encoder packages should
 // be appended to AllEncoders during respective init()
 var AllEncoders encoderNames
 
+// FileEncoders holds list of encoder names of the formats written by the filename of output file,
+// which can't be written to stdout. Such encoder names should also be appended to FileEncoders
+// during respective init()
+var FileEncoders encoderNames
+
+// AllDecoders holds list of decoder names, i.e., the encoder names of the formats
+// that can be read back. All the decoder names in each of the encoder packages should
+// be appended to AllDecoders during respective init()
+var AllDecoders encoderNames
+
 // ToEncoder converts stringer to EncoderName type
 func ToEncoder(s fmt.Stringer) EncoderName {
 	return EncoderName(s.String())
@@ -58,3 +89,59 @@ type Encoder interface {
 	Filename() string
 	fmt.Stringer
 }
+
+// VisitEncoder is implemented by the encoders which are able to encode
+// browsing history visits, in addition to the bookmarks. This has the method:
+//  1. EncodeVisits - Encodes visits to target output format
+//     and writes to already mentioned output stream.
+type VisitEncoder interface {
+	EncodeVisits([]history.Visit) error
+}
+
+// LinkEncoder is implemented by the encoders which are able to encode
+// the health of bookmark links, in addition to the bookmarks. This has the method:
+//  1. EncodeLinks - Encodes links to target output format
+//     and writes to already mentioned output stream.
+type LinkEncoder interface {
+	EncodeLinks([]links.Link) error
+}
+
+// StreamEncoder is implemented by the encoders which are able to encode
+// the bookmarks one at a time, as they're fetched. This has the methods:
+//  1. EncodeBookmark - Encodes a bookmark to target output format,
+//     possibly buffered ahead of writing to already mentioned output stream.
+//  2. Flush - Writes the buffered bookmarks to already mentioned output stream.
+type StreamEncoder interface {
+	EncodeBookmark(bookmark.Bookmark) error
+	Flush() error
+}
+
+// FrontMatterEncoder is implemented by the encoders which are able to write
+// the front matter ahead of the bookmarks, such as of notes. This has the method:
+//  1. SetFrontMatter - Sets the front matter to write ahead of the bookmarks.
+type FrontMatterEncoder interface {
+	SetFrontMatter(FrontMatter)
+}
+
+// FrontMatter holds the metadata of export, written ahead of the bookmarks.
+// The fields with zero values are omitted.
+type FrontMatter struct {
+	Date    time.Time // Date of export
+	Profile string    // Name of the source profile
+}
+
+// IsZero reports whether the front matter has no fields to write
+func (f FrontMatter) IsZero() bool {
+	return f.Date.IsZero() && f.Profile == ""
+}
+
+// Decoder holds the signatures to custom decoder types, reading back
+// the output of respective encoders. This has the following methods:
+//  1. Decode - Reads already mentioned input stream and decodes
+//     bookmarks from the encoder's output format.
+//  2. String (in fmt.Stringer) - Returns the decoder name,
+//     same as the respective encoder name.
+type Decoder interface {
+	Decode() ([]bookmark.Bookmark, error)
+	fmt.Stringer
+}
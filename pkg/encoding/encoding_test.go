@@ -78,3 +78,42 @@ func Test_encoderNames_String(t *testing.T) {
 		})
 	}
 }
+
+func Test_encoderNames_Without(t *testing.T) {
+	var (
+		csv    = ToEncoder(constants.CSVFormat)
+		json   = ToEncoder(constants.JSONFormat)
+		sqlite = ToEncoder(constants.SQLiteExportFormat)
+	)
+
+	tests := []struct {
+		name     string
+		encoders encoderNames
+		excluded encoderNames
+		expected encoderNames
+	}{
+		{
+			name:     "Excluded-Encoder",
+			encoders: encoderNames{csv, sqlite, json},
+			excluded: encoderNames{sqlite},
+			expected: encoderNames{csv, json},
+		},
+		{
+			name:     "No-Excluded-Encoders",
+			encoders: encoderNames{csv, json},
+			excluded: nil,
+			expected: encoderNames{csv, json},
+		},
+		{
+			name:     "All-Excluded-Encoders",
+			encoders: encoderNames{sqlite},
+			excluded: encoderNames{sqlite},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.encoders.Without(tt.excluded), "Mismatch of remaining encoders")
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+)
+
+const (
+	// indentation is the indentation string per level of folder depth
+	indentation = "    "
+
+	// header is the preamble of the Netscape Bookmark File format.
+	// Browsers identify the file format by the doctype.
+	header = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<meta http-equiv="Content-Security-Policy"
+      content="default-src 'self'; script-src 'none'; img-src data: *; object-src 'none'"></meta>
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks Menu</H1>
+
+`
+)
+
+// rootFolder holds the heading and attribute of a Firefox built-in root folder
+type rootFolder struct {
+	title     string
+	attribute string
+}
+
+// rootFolders maps the Firefox built-in root folders to their headings in the HTML file.
+// The menu root is absent as its contents are written at the top-level, same as Firefox does.
+// The tags root is absent as the tag folders aren't bookmarks to be imported.
+var rootFolders = map[string]rootFolder{
+	bookmark.ToolbarRoot: {title: "Bookmarks Toolbar", attribute: ` PERSONAL_TOOLBAR_FOLDER="true"`},
+	bookmark.UnfiledRoot: {title: "Other Bookmarks", attribute: ` UNFILED_BOOKMARKS_FOLDER="true"`},
+	bookmark.MobileRoot:  {title: "Mobile Bookmarks", attribute: ""},
+}
+
+// EncoderName is name of the encoder in current package, i.e., HTML.
+// HTMLFormat constant is parsed as EncoderName type here.
+var EncoderName = encoding.ToEncoder(constants.HTMLFormat)
+
+func init() {
+	// Register the encoder name in pkg/encoding.AllEncoders
+	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+}
+
+// Encoder is the manager for HTML encoder.
+// The output is in Netscape Bookmark File format, importable in Firefox, Chrome, Edge, etc.
+type Encoder struct {
+	out      io.Writer
+	filename string
+}
+
+// NewEncoder initializes new Encoder
+func NewEncoder(out io.Writer) *Encoder {
+	var filename string
+
+	// If the output stream is a file, specifically pkg/files.File,
+	// the filename can be extracted here. Just an optional requirement.
+	if file, ok := any(out).(files.File); ok {
+		filename = file.Name()
+	}
+
+	return &Encoder{
+		out:      out,
+		filename: filename,
+	}
+}
+
+// Encode encodes the input bookmarks in HTML format to already set output stream.
+// The folder hierarchy is rebuilt from ID and Parent fields of bookmarks.
+func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
+	var buffer bytes.Buffer
+
+	buffer.WriteString(header)
+	buffer.WriteString("<DL><p>\n")
+	writeNodes(&buffer, bookmark.Tree(bookmarks), 1, false)
+	buffer.WriteString("</DL>\n")
+
+	// Write the document at once, so the output stream isn't left partially written
+	if _, err := e.out.Write(buffer.Bytes()); err != nil {
+		return fmt.Errorf("failed to write HTML: %v", err)
+	}
+
+	return nil
+}
+
+// writeNodes writes the nodes and their children recursively at the specified depth.
+// isRootChildren is set when the nodes are direct children of places root.
+func writeNodes(buffer *bytes.Buffer, nodes []*bookmark.Node, depth int, isRootChildren bool) {
+	var (
+		node   *bookmark.Node
+		root   rootFolder
+		ok     bool
+		indent = strings.Repeat(indentation, depth)
+	)
+
+	for _, node = range nodes {
+		switch {
+		case node.IsPlacesRoot():
+			// Places root itself isn't written, only its children
+			writeNodes(buffer, node.Children, depth, true)
+		case isRootChildren && node.Title == bookmark.MenuRoot:
+			// Contents of menu are written at the current level
+			writeNodes(buffer, node.Children, depth, false)
+		case node.IsTagsRoot(isRootChildren):
+			// Tag folders are skipped
+		case node.IsSeparator():
+			fmt.Fprintf(buffer, "%s<HR>\n", indent)
+		case node.IsFolder():
+			if root, ok = rootFolders[node.Title]; !ok || !isRootChildren {
+				// When the folder is a user-created folder
+				root = rootFolder{title: node.Title}
+			}
+
+			fmt.Fprintf(buffer, "%s<DT><H3%s%s>%s</H3>\n", indent, timestamps(node), root.attribute, escape(root.title))
+			fmt.Fprintf(buffer, "%s<DL><p>\n", indent)
+			writeNodes(buffer, node.Children, depth+1, false)
+			fmt.Fprintf(buffer, "%s</DL><p>\n", indent)
+		default:
+			fmt.Fprintf(buffer, "%s<DT><A HREF=\"%s\"%s%s%s>%s</A>\n", indent, escape(url(node)),
+				timestamps(node), keyword(node), tags(node), escape(node.Title))
+		}
+	}
+}
+
+// url returns the URL of the bookmark node, or blank if missing
+func url(node *bookmark.Node) string {
+	if node.URL == nil {
+		return ""
+	}
+
+	return *node.URL
+}
+
+// timestamps formats the ADD_DATE and LAST_MODIFIED attributes in seconds since epoch.
+// The attributes are omitted for missing timestamps.
+func timestamps(node *bookmark.Node) string {
+	var attributes string
+
+	if !node.DateAdded.IsZero() {
+		attributes += fmt.Sprintf(` ADD_DATE="%d"`, node.DateAdded.Unix())
+	}
+
+	if !node.LastModified.IsZero() {
+		attributes += fmt.Sprintf(` LAST_MODIFIED="%d"`, node.LastModified.Unix())
+	}
+
+	return attributes
+}
+
+// keyword formats the SHORTCUTURL attribute, omitted if the bookmark has no keyword
+func keyword(node *bookmark.Node) string {
+	if node.Keyword == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(` SHORTCUTURL="%s"`, escape(node.Keyword))
+}
+
+// tags formats the comma-separated TAGS attribute, omitted if the bookmark is untagged
+func tags(node *bookmark.Node) string {
+	if len(node.Tags) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(` TAGS="%s"`, escape(strings.Join(node.Tags, ",")))
+}
+
+// escape escapes the special characters in HTML text and attribute values
+func escape(s string) string {
+	return html.EscapeString(strings.TrimSpace(s))
+}
+
+// String returns the encoder name derived in EncoderName.
+// This returns the same value as EncoderName, but using the receiver.
+func (e *Encoder) String() string {
+	return EncoderName.String()
+}
+
+// Filename returns the file name string derived from output stream,
+// iff the output stream is of pkg/files.File type.
+func (e *Encoder) Filename() string {
+	return e.filename
+}
@@ -0,0 +1,244 @@
+package html
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/mocks"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+var (
+	ptrStr                 = util.PtrStr
+	stringSliceToFlatBytes = util.StringSliceToFlatBytes
+)
+
+func TestEncoder_Encode(t *testing.T) {
+	type fields struct {
+		filename     string
+		outputWriter io.Writer
+	}
+
+	type args struct {
+		bookmarks []bookmark.Bookmark
+	}
+
+	type toggles struct {
+		isExpectedError    bool
+		isFileWriter       bool
+		isErrorAtHTMLWrite bool
+	}
+
+	type testData struct {
+		name     string
+		fields   fields
+		args     args
+		toggles  toggles
+		expected []string
+	}
+
+	var (
+		err            error
+		tests          []testData
+		testCase       testData
+		encoder        *Encoder
+		filename       string
+		expectedOutput []byte
+
+		fileWriter    = new(mocks.File)
+		nonFileWriter = new(mocks.NonFileWriter)
+
+		preamble = []string{
+			`<!DOCTYPE NETSCAPE-Bookmark-file-1>`,
+			`<!-- This is an automatically generated file.`,
+			`     It will be read and overwritten.`,
+			`     DO NOT EDIT! -->`,
+			`<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">`,
+			`<meta http-equiv="Content-Security-Policy"`,
+			`      content="default-src 'self'; script-src 'none'; img-src data: *; object-src 'none'"></meta>`,
+			`<TITLE>Bookmarks</TITLE>`,
+			`<H1>Bookmarks Menu</H1>`,
+			``,
+		}
+
+		placesBookmarks = []bookmark.Bookmark{
+			{URL: nil, Title: "", ID: 1, Parent: 0},
+			{URL: nil, Title: "menu", ID: 2, Parent: 1},
+			{URL: nil, Title: "toolbar", ID: 3, Parent: 1},
+			{URL: nil, Title: "tags", ID: 4, Parent: 1},
+			{URL: nil, Title: "unfiled", ID: 5, Parent: 1},
+			{URL: nil, Title: "Projects", ID: 6, Parent: 3},
+			{URL: ptrStr("https://github.com/vaguecoder/firefox-backups"), Title: "Firefox <Backups>", ID: 7, Parent: 6},
+			{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 8, Parent: 2},
+			{URL: nil, Title: "golang", ID: 9, Parent: 4},
+			{URL: ptrStr("https://go.dev/?a=1&b=2"), Title: "Go", ID: 10, Parent: 5},
+		}
+	)
+
+	tests = []testData{
+		{
+			name: "Valid-Case-Places-Roots",
+			fields: fields{
+				filename:     "firefox-bookmarks.html",
+				outputWriter: fileWriter,
+			},
+			args: args{
+				bookmarks: placesBookmarks,
+			},
+			toggles: toggles{
+				isExpectedError:    false,
+				isFileWriter:       true,
+				isErrorAtHTMLWrite: false,
+			},
+			expected: util.AppendAll(preamble,
+				`<DL><p>`,
+				`    <DT><A HREF="https://github.com/vaguecoder">Vague Coder</A>`,
+				`    <DT><H3 PERSONAL_TOOLBAR_FOLDER="true">Bookmarks Toolbar</H3>`,
+				`    <DL><p>`,
+				`        <DT><H3>Projects</H3>`,
+				`        <DL><p>`,
+				`            <DT><A HREF="https://github.com/vaguecoder/firefox-backups">Firefox &lt;Backups&gt;</A>`,
+				`        </DL><p>`,
+				`    </DL><p>`,
+				`    <DT><H3 UNFILED_BOOKMARKS_FOLDER="true">Other Bookmarks</H3>`,
+				`    <DL><p>`,
+				`        <DT><A HREF="https://go.dev/?a=1&amp;b=2">Go</A>`,
+				`    </DL><p>`,
+				`</DL>`,
+			),
+		},
+		{
+			name: "Valid-Case-With-Non-File-Writer-Without-Roots",
+			fields: fields{
+				filename:     "firefox-bookmarks.html",
+				outputWriter: nonFileWriter,
+			},
+			args: args{
+				bookmarks: []bookmark.Bookmark{
+					{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 1, Parent: 0},
+					{URL: ptrStr("https://github.com/random"), Title: "Random", ID: 2, Parent: 0},
+				},
+			},
+			toggles: toggles{
+				isExpectedError:    false,
+				isFileWriter:       false,
+				isErrorAtHTMLWrite: false,
+			},
+			expected: util.AppendAll(preamble,
+				`<DL><p>`,
+				`    <DT><A HREF="https://github.com/vaguecoder">Vague Coder</A>`,
+				`    <DT><A HREF="https://github.com/random">Random</A>`,
+				`</DL>`,
+			),
+		},
+		{
+			name: "Valid-Case-With-Timestamps-Keyword-And-Separator",
+			fields: fields{
+				filename:     "firefox-bookmarks.html",
+				outputWriter: fileWriter,
+			},
+			args: args{
+				bookmarks: []bookmark.Bookmark{
+					{
+						URL: nil, Title: "GitHub", ID: 6, Parent: 0, Type: bookmark.TypeFolder,
+						DateAdded:    time.Date(2023, 3, 4, 11, 37, 6, 0, time.UTC),
+						LastModified: time.Date(2023, 3, 4, 11, 37, 7, 0, time.UTC),
+					},
+					{
+						URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 7, Parent: 6,
+						Type: bookmark.TypeBookmark, Position: 1, Keyword: "vc", Tags: []string{"go", "vcs"},
+						DateAdded: time.Date(2023, 3, 4, 11, 37, 6, 0, time.UTC),
+					},
+					{URL: nil, Title: "", ID: 8, Parent: 6, Type: bookmark.TypeSeparator, Position: 0},
+				},
+			},
+			toggles: toggles{
+				isExpectedError:    false,
+				isFileWriter:       true,
+				isErrorAtHTMLWrite: false,
+			},
+			expected: util.AppendAll(preamble,
+				`<DL><p>`,
+				`    <DT><H3 ADD_DATE="1677929826" LAST_MODIFIED="1677929827">GitHub</H3>`,
+				`    <DL><p>`,
+				`        <HR>`,
+				`        <DT><A HREF="https://github.com/vaguecoder" ADD_DATE="1677929826" SHORTCUTURL="vc" TAGS="go,vcs">Vague Coder</A>`,
+				`    </DL><p>`,
+				`</DL>`,
+			),
+		},
+		{
+			name: "No-Bookmarks",
+			fields: fields{
+				filename:     "firefox-bookmarks.html",
+				outputWriter: fileWriter,
+			},
+			args: args{
+				bookmarks: []bookmark.Bookmark{},
+			},
+			toggles: toggles{
+				isExpectedError:    false,
+				isFileWriter:       true,
+				isErrorAtHTMLWrite: false,
+			},
+			expected: util.AppendAll(preamble, `<DL><p>`, `</DL>`),
+		},
+		{
+			name: "Failure-At-HTML-Write",
+			fields: fields{
+				filename:     "firefox-bookmarks.html",
+				outputWriter: nonFileWriter,
+			},
+			args: args{
+				bookmarks: []bookmark.Bookmark{},
+			},
+			toggles: toggles{
+				isExpectedError:    true,
+				isFileWriter:       false,
+				isErrorAtHTMLWrite: true,
+			},
+			expected: util.AppendAll(preamble, `<DL><p>`, `</DL>`),
+		},
+	}
+	for _, testCase = range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			expectedOutput = stringSliceToFlatBytes(testCase.expected)
+
+			var (
+				lengthOfExpectedOutput       = len(expectedOutput)
+				htmlWriteErr           error = nil
+			)
+
+			if testCase.toggles.isErrorAtHTMLWrite {
+				htmlWriteErr = fmt.Errorf("some error")
+			}
+
+			filename = ""
+			if testCase.toggles.isFileWriter {
+				filename = testCase.fields.filename
+				fileWriter.On("Name").Return(testCase.fields.filename).Once()
+				fileWriter.On("Write", expectedOutput).Return(lengthOfExpectedOutput, htmlWriteErr).Once()
+			} else {
+				nonFileWriter.On("Write", expectedOutput).Return(lengthOfExpectedOutput, htmlWriteErr).Once()
+			}
+
+			encoder = NewEncoder(testCase.fields.outputWriter)
+
+			err = encoder.Encode(testCase.args.bookmarks)
+
+			if testCase.toggles.isExpectedError {
+				assert.Error(t, err, "Expected error from encode")
+			} else {
+				assert.NoError(t, err, "Unexpected error from encode")
+			}
+
+			assert.Equal(t, filename, encoder.Filename(), "Mismatch of filename in writer")
+			assert.Equal(t, constants.HTMLFormat.String(), encoder.String(), "Mismatch of encoder name string")
+		})
+	}
+}
@@ -2,6 +2,7 @@ package json
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 
@@ -9,6 +10,8 @@ import (
 	"github.com/vaguecoder/firefox-backups/pkg/constants"
 	"github.com/vaguecoder/firefox-backups/pkg/encoding"
 	"github.com/vaguecoder/firefox-backups/pkg/files"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
 )
 
 // indentation is the JSON indentation string
@@ -21,6 +24,9 @@ var EncoderName = encoding.ToEncoder(constants.JSONFormat)
 func init() {
 	// Register the encoder name in pkg/encoding.AllEncoders
 	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+
+	// Register the decoder name in pkg/encoding.AllDecoders
+	encoding.AllDecoders = append(encoding.AllDecoders, EncoderName)
 }
 
 // Encoder is the manager for JSON encoder
@@ -59,6 +65,26 @@ func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
 	return nil
 }
 
+// EncodeVisits encodes the input history visits in JSON format to already set output stream
+func (e *Encoder) EncodeVisits(visits []history.Visit) error {
+	err := e.jsonEncoder.Encode(visits)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+
+	return nil
+}
+
+// EncodeLinks encodes the input links of bookmarks in JSON format to already set output stream
+func (e *Encoder) EncodeLinks(checkedLinks []links.Link) error {
+	err := e.jsonEncoder.Encode(checkedLinks)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+
+	return nil
+}
+
 // String returns the encoder name derived in EncoderName.
 // This returns the same value as EncoderName, but using the receiver.
 func (e *Encoder) String() string {
@@ -70,3 +96,33 @@ func (e *Encoder) String() string {
 func (e *Encoder) Filename() string {
 	return e.filename
 }
+
+// Decoder is the manager for JSON decoder
+type Decoder struct {
+	jsonDecoder *json.Decoder
+}
+
+// NewDecoder initializes new Decoder
+func NewDecoder(in io.Reader) *Decoder {
+	return &Decoder{
+		jsonDecoder: json.NewDecoder(in),
+	}
+}
+
+// Decode decodes the bookmarks in JSON format from already set input stream
+func (d *Decoder) Decode() ([]bookmark.Bookmark, error) {
+	var bookmarks []bookmark.Bookmark
+
+	err := d.jsonDecoder.Decode(&bookmarks)
+	if err != nil && !errors.Is(err, io.EOF) {
+		// Empty input stream has no bookmarks
+		return nil, fmt.Errorf("failed to unmarshal JSON: %v", err)
+	}
+
+	return bookmarks, nil
+}
+
+// String returns the decoder name, same as EncoderName
+func (d *Decoder) String() string {
+	return EncoderName.String()
+}
@@ -1,13 +1,17 @@
 package json
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
 	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
 	"github.com/vaguecoder/firefox-backups/pkg/mocks"
 	"github.com/vaguecoder/firefox-backups/pkg/util"
 )
@@ -90,14 +94,28 @@ func TestEncoder_Encode(t *testing.T) {
 				`		"title": "Vague Coder",`,
 				`		"folder": "Profiles/GitHub",`,
 				`		"id": 1,`,
-				`		"parent": 0`,
+				`		"parent": 0,`,
+				`		"guid": "",`,
+				`		"place-guid": "",`,
+				`		"type": 0,`,
+				`		"position": 0,`,
+				`		"keyword": "",`,
+				`		"date-added": "0001-01-01T00:00:00Z",`,
+				`		"last-modified": "0001-01-01T00:00:00Z"`,
 				`	},`,
 				`	{`,
 				`		"url": "https://github.com/random",`,
 				`		"title": "Random",`,
 				`		"folder": "Profiles/GitHub",`,
 				`		"id": 2,`,
-				`		"parent": 0`,
+				`		"parent": 0,`,
+				`		"guid": "",`,
+				`		"place-guid": "",`,
+				`		"type": 0,`,
+				`		"position": 0,`,
+				`		"keyword": "",`,
+				`		"date-added": "0001-01-01T00:00:00Z",`,
+				`		"last-modified": "0001-01-01T00:00:00Z"`,
 				`	}`,
 				`]`,
 			},
@@ -133,14 +151,28 @@ func TestEncoder_Encode(t *testing.T) {
 				`		"title": "Vague Coder",`,
 				`		"folder": "Profiles/GitHub",`,
 				`		"id": 1,`,
-				`		"parent": 0`,
+				`		"parent": 0,`,
+				`		"guid": "",`,
+				`		"place-guid": "",`,
+				`		"type": 0,`,
+				`		"position": 0,`,
+				`		"keyword": "",`,
+				`		"date-added": "0001-01-01T00:00:00Z",`,
+				`		"last-modified": "0001-01-01T00:00:00Z"`,
 				`	},`,
 				`	{`,
 				`		"url": "https://github.com/random",`,
 				`		"title": "Random",`,
 				`		"folder": "Profiles/GitHub",`,
 				`		"id": 2,`,
-				`		"parent": 0`,
+				`		"parent": 0,`,
+				`		"guid": "",`,
+				`		"place-guid": "",`,
+				`		"type": 0,`,
+				`		"position": 0,`,
+				`		"keyword": "",`,
+				`		"date-added": "0001-01-01T00:00:00Z",`,
+				`		"last-modified": "0001-01-01T00:00:00Z"`,
 				`	}`,
 				`]`,
 			},
@@ -213,14 +245,28 @@ func TestEncoder_Encode(t *testing.T) {
 				`		"title": "Vague Coder",`,
 				`		"folder": "Profiles/GitHub",`,
 				`		"id": 1,`,
-				`		"parent": 0`,
+				`		"parent": 0,`,
+				`		"guid": "",`,
+				`		"place-guid": "",`,
+				`		"type": 0,`,
+				`		"position": 0,`,
+				`		"keyword": "",`,
+				`		"date-added": "0001-01-01T00:00:00Z",`,
+				`		"last-modified": "0001-01-01T00:00:00Z"`,
 				`	},`,
 				`	{`,
 				`		"url": "https://github.com/random",`,
 				`		"title": "Random",`,
 				`		"folder": "Profiles/GitHub",`,
 				`		"id": 2,`,
-				`		"parent": 0`,
+				`		"parent": 0,`,
+				`		"guid": "",`,
+				`		"place-guid": "",`,
+				`		"type": 0,`,
+				`		"position": 0,`,
+				`		"keyword": "",`,
+				`		"date-added": "0001-01-01T00:00:00Z",`,
+				`		"last-modified": "0001-01-01T00:00:00Z"`,
 				`	}`,
 				`]`,
 			},
@@ -261,14 +307,28 @@ func TestEncoder_Encode(t *testing.T) {
 				`		"title": "Vague Coder",`,
 				`		"folder": "Profiles/GitHub",`,
 				`		"id": 1,`,
-				`		"parent": 0`,
+				`		"parent": 0,`,
+				`		"guid": "",`,
+				`		"place-guid": "",`,
+				`		"type": 0,`,
+				`		"position": 0,`,
+				`		"keyword": "",`,
+				`		"date-added": "0001-01-01T00:00:00Z",`,
+				`		"last-modified": "0001-01-01T00:00:00Z"`,
 				`	},`,
 				`	{`,
 				`		"url": "https://github.com/random",`,
 				`		"title": "Random",`,
 				`		"folder": "Profiles/GitHub",`,
 				`		"id": 2,`,
-				`		"parent": 0`,
+				`		"parent": 0,`,
+				`		"guid": "",`,
+				`		"place-guid": "",`,
+				`		"type": 0,`,
+				`		"position": 0,`,
+				`		"keyword": "",`,
+				`		"date-added": "0001-01-01T00:00:00Z",`,
+				`		"last-modified": "0001-01-01T00:00:00Z"`,
 				`	}`,
 				`]`,
 			},
@@ -316,3 +376,215 @@ func TestEncoder_Encode(t *testing.T) {
 		})
 	}
 }
+
+func TestEncoder_EncodeVisits(t *testing.T) {
+	tests := []struct {
+		name     string
+		visits   []history.Visit
+		writeErr error
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name: "Valid-Case",
+			visits: []history.Visit{
+				{
+					ID:        2,
+					FromVisit: 1,
+					URL:       "https://github.com/vaguecoder",
+					Title:     "Vague Coder",
+					VisitDate: time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC),
+					VisitType: 1,
+				},
+			},
+			writeErr: nil,
+			expected: []string{
+				`[`,
+				`	{`,
+				`		"id": 2,`,
+				`		"from-visit": 1,`,
+				`		"url": "https://github.com/vaguecoder",`,
+				`		"title": "Vague Coder",`,
+				`		"visit-date": "2023-03-04T11:37:06Z",`,
+				`		"visit-type": 1`,
+				`	}`,
+				`]`,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Failure-At-JSON-Write",
+			visits:   []history.Visit{},
+			writeErr: fmt.Errorf("some error"),
+			expected: []string{
+				`[]`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				nonFileWriter  = new(mocks.NonFileWriter)
+				expectedOutput = stringSliceToFlatBytes(tt.expected)
+			)
+
+			nonFileWriter.On("Write", expectedOutput).Return(len(expectedOutput), tt.writeErr).Once()
+
+			err := NewEncoder(nonFileWriter).EncodeVisits(tt.visits)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from encode")
+			} else {
+				assert.NoError(t, err, "Unexpected error from encode")
+			}
+
+			nonFileWriter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEncoder_EncodeLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		links    []links.Link
+		writeErr error
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name: "Valid-Case",
+			links: []links.Link{
+				{
+					URL:         "http://golang.org/",
+					Title:       "Go",
+					Folder:      "toolbar",
+					GUID:        "bookmarkgd01",
+					Status:      200,
+					RedirectURL: "https://go.dev/",
+					Permanent:   true,
+				},
+			},
+			writeErr: nil,
+			expected: []string{
+				`[`,
+				`	{`,
+				`		"url": "http://golang.org/",`,
+				`		"title": "Go",`,
+				`		"folder": "toolbar",`,
+				`		"guid": "bookmarkgd01",`,
+				`		"status": 200,`,
+				`		"redirect-url": "https://go.dev/",`,
+				`		"permanent": true,`,
+				`		"error": ""`,
+				`	}`,
+				`]`,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Failure-At-JSON-Write",
+			links:    []links.Link{},
+			writeErr: fmt.Errorf("some error"),
+			expected: []string{
+				`[]`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				nonFileWriter  = new(mocks.NonFileWriter)
+				expectedOutput = stringSliceToFlatBytes(tt.expected)
+			)
+
+			nonFileWriter.On("Write", expectedOutput).Return(len(expectedOutput), tt.writeErr).Once()
+
+			err := NewEncoder(nonFileWriter).EncodeLinks(tt.links)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from encode")
+			} else {
+				assert.NoError(t, err, "Unexpected error from encode")
+			}
+
+			nonFileWriter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	type testData struct {
+		name      string
+		bookmarks []bookmark.Bookmark
+	}
+
+	var (
+		err       error
+		buffer    bytes.Buffer
+		decoded   []bookmark.Bookmark
+		testCase  testData
+		timestamp = time.Date(2023, time.March, 4, 5, 6, 7, 891011000, time.UTC)
+
+		bookmarks = []bookmark.Bookmark{
+			{
+				Title:        "Profiles",
+				Folder:       "Bookmarks Toolbar",
+				ID:           7,
+				Parent:       3,
+				GUID:         "folder-guid1",
+				Type:         bookmark.TypeFolder,
+				Position:     1,
+				DateAdded:    timestamp,
+				LastModified: timestamp.Add(time.Hour),
+			},
+			{
+				URL:          ptrStr("https://github.com/vaguecoder?tab=repositories"),
+				Title:        "Vague Coder · GitHub",
+				Folder:       "Bookmarks Toolbar/Profiles",
+				ID:           8,
+				Parent:       7,
+				GUID:         "bookmarkguid",
+				PlaceGUID:    "placeguid123",
+				Type:         bookmark.TypeBookmark,
+				Position:     0,
+				Keyword:      "gh",
+				DateAdded:    timestamp,
+				LastModified: timestamp,
+				Tags:         []string{"code", "profile"},
+			},
+			{
+				Folder:   "Bookmarks Toolbar/Profiles",
+				ID:       9,
+				Parent:   7,
+				GUID:     "separatorgd",
+				Type:     bookmark.TypeSeparator,
+				Position: 1,
+			},
+		}
+	)
+
+	tests := []testData{
+		{
+			name:      "Round-Trip",
+			bookmarks: bookmarks,
+		},
+		{
+			name:      "No-Bookmarks",
+			bookmarks: nil,
+		},
+	}
+
+	for _, testCase = range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			buffer.Reset()
+
+			err = NewEncoder(&buffer).Encode(testCase.bookmarks)
+			assert.NoErrorf(t, err, "Failed to encode bookmarks: %v", err)
+
+			decoded, err = NewDecoder(&buffer).Decode()
+			assert.NoErrorf(t, err, "Failed to decode bookmarks: %v", err)
+
+			assert.Equalf(t, testCase.bookmarks, decoded, "Mismatch in decoded bookmarks")
+		})
+	}
+}
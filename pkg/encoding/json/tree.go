@@ -0,0 +1,71 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+)
+
+// TreeEncoderName is name of the tree encoder in current package, i.e., JSON tree.
+// JSONTreeFormat constant is parsed as EncoderName type here.
+var TreeEncoderName = encoding.ToEncoder(constants.JSONTreeFormat)
+
+func init() {
+	// Register the encoder name in pkg/encoding.AllEncoders
+	encoding.AllEncoders = append(encoding.AllEncoders, TreeEncoderName)
+}
+
+// TreeEncoder is the manager for JSON encoder of the folder hierarchy,
+// with folders nesting their children
+type TreeEncoder struct {
+	jsonEncoder *json.Encoder
+	filename    string
+}
+
+// NewTreeEncoder initializes new TreeEncoder
+func NewTreeEncoder(out io.Writer) *TreeEncoder {
+	var filename string
+
+	// If the output stream is a file, specifically pkg/files.File,
+	// the filename can be extracted here. Just an optional requirement.
+	if file, ok := any(out).(files.File); ok {
+		filename = file.Name()
+	}
+
+	// Create new JSON encoder and set the indentation per constant.
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", indentation)
+
+	return &TreeEncoder{
+		jsonEncoder: encoder,
+		filename:    filename,
+	}
+}
+
+// Encode encodes the folder hierarchy of input bookmarks in JSON format to already set output stream.
+// The hierarchy is rebuilt from ID and Parent fields of bookmarks, or from the folder paths of denormalized ones.
+func (e *TreeEncoder) Encode(bookmarks []bookmark.Bookmark) error {
+	err := e.jsonEncoder.Encode(encoding.NewTree(bookmarks))
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON tree: %v", err)
+	}
+
+	return nil
+}
+
+// String returns the encoder name derived in TreeEncoderName.
+// This returns the same value as TreeEncoderName, but using the receiver.
+func (e *TreeEncoder) String() string {
+	return TreeEncoderName.String()
+}
+
+// Filename returns the file name string derived from output stream,
+// iff the output stream is of pkg/files.File type.
+func (e *TreeEncoder) Filename() string {
+	return e.filename
+}
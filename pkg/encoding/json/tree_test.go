@@ -0,0 +1,141 @@
+package json
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/mocks"
+)
+
+func TestTreeEncoder_Encode(t *testing.T) {
+	var (
+		root   = bookmark.Bookmark{ID: 1, GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder}
+		mobile = bookmark.Bookmark{ID: 6, Parent: 1, Title: "mobile", GUID: "mobile______", Type: bookmark.TypeFolder}
+		goDev  = bookmark.Bookmark{ID: 8, Parent: 6, Title: "Go", URL: ptrStr("https://go.dev/"), Type: bookmark.TypeBookmark,
+			Tags: []string{"golang"}}
+
+		// Denormalized record, i.e., with folder path, and without the folder it's in
+		denormalizedGoDev = bookmark.Bookmark{ID: 8, Parent: 6, Title: "Go", URL: ptrStr("https://go.dev/"),
+			Folder: "mobile", Type: bookmark.TypeBookmark}
+	)
+
+	tests := []struct {
+		name      string
+		bookmarks []bookmark.Bookmark
+		writeErr  error
+		expected  []string
+		wantErr   bool
+	}{
+		{
+			name:      "Valid-Case",
+			bookmarks: []bookmark.Bookmark{root, mobile, goDev},
+			expected: []string{
+				`[`,
+				`	{`,
+				`		"url": null,`,
+				`		"title": "mobile",`,
+				`		"folder": "",`,
+				`		"id": 6,`,
+				`		"parent": 1,`,
+				`		"guid": "mobile______",`,
+				`		"place-guid": "",`,
+				`		"type": 2,`,
+				`		"position": 0,`,
+				`		"keyword": "",`,
+				`		"date-added": "0001-01-01T00:00:00Z",`,
+				`		"last-modified": "0001-01-01T00:00:00Z",`,
+				`		"children": [`,
+				`			{`,
+				`				"url": "https://go.dev/",`,
+				`				"title": "Go",`,
+				`				"folder": "",`,
+				`				"id": 8,`,
+				`				"parent": 6,`,
+				`				"guid": "",`,
+				`				"place-guid": "",`,
+				`				"type": 1,`,
+				`				"position": 0,`,
+				`				"keyword": "",`,
+				`				"date-added": "0001-01-01T00:00:00Z",`,
+				`				"last-modified": "0001-01-01T00:00:00Z",`,
+				`				"tags": [`,
+				`					"golang"`,
+				`				]`,
+				`			}`,
+				`		]`,
+				`	}`,
+				`]`,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Denormalized-Bookmarks",
+			bookmarks: []bookmark.Bookmark{denormalizedGoDev},
+			expected: []string{
+				`[`,
+				`	{`,
+				`		"url": null,`,
+				`		"title": "mobile",`,
+				`		"folder": "",`,
+				`		"id": 9,`,
+				`		"parent": 0,`,
+				`		"guid": "",`,
+				`		"place-guid": "",`,
+				`		"type": 2,`,
+				`		"position": 0,`,
+				`		"keyword": "",`,
+				`		"date-added": "0001-01-01T00:00:00Z",`,
+				`		"last-modified": "0001-01-01T00:00:00Z",`,
+				`		"children": [`,
+				`			{`,
+				`				"url": "https://go.dev/",`,
+				`				"title": "Go",`,
+				`				"folder": "mobile",`,
+				`				"id": 8,`,
+				`				"parent": 6,`,
+				`				"guid": "",`,
+				`				"place-guid": "",`,
+				`				"type": 1,`,
+				`				"position": 0,`,
+				`				"keyword": "",`,
+				`				"date-added": "0001-01-01T00:00:00Z",`,
+				`				"last-modified": "0001-01-01T00:00:00Z"`,
+				`			}`,
+				`		]`,
+				`	}`,
+				`]`,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Failure-At-JSON-Write",
+			bookmarks: []bookmark.Bookmark{},
+			writeErr:  fmt.Errorf("some error"),
+			expected: []string{
+				`[]`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				nonFileWriter  = new(mocks.NonFileWriter)
+				expectedOutput = stringSliceToFlatBytes(tt.expected)
+			)
+
+			nonFileWriter.On("Write", expectedOutput).Return(len(expectedOutput), tt.writeErr).Once()
+
+			err := NewTreeEncoder(nonFileWriter).Encode(tt.bookmarks)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from encode")
+			} else {
+				assert.NoError(t, err, "Unexpected error from encode")
+			}
+
+			nonFileWriter.AssertExpectations(t)
+		})
+	}
+}
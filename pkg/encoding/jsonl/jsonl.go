@@ -0,0 +1,189 @@
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+)
+
+// FieldNames are the names of bookmark fields in JSON, in the order of the fields
+var FieldNames = fieldNames()
+
+// EncoderName is name of the encoder in current package, i.e., JSON Lines.
+// JSONLFormat constant is parsed as EncoderName type here.
+var EncoderName = encoding.ToEncoder(constants.JSONLFormat)
+
+func init() {
+	// Register the encoder name in pkg/encoding.AllEncoders
+	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+}
+
+// Encoder is the manager for JSON Lines encoder.
+// Each bookmark is written as a compact JSON object in a line, with all the fields,
+// or only the selected fields in their order of selection.
+type Encoder struct {
+	buffer      *bufio.Writer
+	jsonEncoder *json.Encoder
+	fields      []string
+	filename    string
+}
+
+// NewEncoder initializes new Encoder, writing the selected fields, or all the fields if none
+func NewEncoder(out io.Writer, fields []string) *Encoder {
+	var filename string
+
+	// If the output stream is a file, specifically pkg/files.File,
+	// the filename can be extracted here. Just an optional requirement.
+	if file, ok := any(out).(files.File); ok {
+		filename = file.Name()
+	}
+
+	// The lines are buffered, as the bookmarks are written one at a time when streamed
+	buffer := bufio.NewWriter(out)
+
+	return &Encoder{
+		buffer:      buffer,
+		jsonEncoder: json.NewEncoder(buffer),
+		fields:      fields,
+		filename:    filename,
+	}
+}
+
+// Encode encodes the input bookmarks in JSON Lines format to already set output stream
+func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
+	for _, b := range bookmarks {
+		if err := e.EncodeBookmark(b); err != nil {
+			return err
+		}
+	}
+
+	return e.Flush()
+}
+
+// EncodeBookmark encodes the bookmark as a line. The line is buffered until Flush.
+func (e *Encoder) EncodeBookmark(b bookmark.Bookmark) error {
+	var (
+		err   error
+		value any = b
+	)
+
+	if len(e.fields) > 0 {
+		// When the fields are selected
+		if value, err = selectFields(b, e.fields); err != nil {
+			return err
+		}
+	}
+
+	// The encoder writes compact JSON, terminated by a newline
+	if err = e.jsonEncoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to marshal JSON Lines: %v", err)
+	}
+
+	return nil
+}
+
+// Flush writes the buffered lines to already set output stream
+func (e *Encoder) Flush() error {
+	if err := e.buffer.Flush(); err != nil {
+		return fmt.Errorf("failed to write JSON Lines: %v", err)
+	}
+
+	return nil
+}
+
+// String returns the encoder name derived in EncoderName.
+// This returns the same value as EncoderName, but using the receiver.
+func (e *Encoder) String() string {
+	return EncoderName.String()
+}
+
+// Filename returns the file name string derived from output stream,
+// iff the output stream is of pkg/files.File type.
+func (e *Encoder) Filename() string {
+	return e.filename
+}
+
+// ValidateFields validates the field names against FieldNames, each to be selected once
+func ValidateFields(fields []string) error {
+	seen := make(map[string]bool, len(fields))
+
+	for _, field := range fields {
+		var known bool
+		for _, name := range FieldNames {
+			known = known || name == field
+		}
+
+		switch {
+		case !known:
+			return fmt.Errorf("unknown field %q (fields: %s)", field, strings.Join(FieldNames, ", "))
+		case seen[field]:
+			return fmt.Errorf("duplicate field %q", field)
+		}
+
+		seen[field] = true
+	}
+
+	return nil
+}
+
+// selectFields returns the JSON object of the bookmark with only the fields, in their order
+func selectFields(b bookmark.Bookmark, fields []string) (json.RawMessage, error) {
+	var (
+		buffer bytes.Buffer
+		values map[string]json.RawMessage
+	)
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON Lines: %v", err)
+	}
+
+	if err = json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to select fields: %v", err)
+	}
+
+	buffer.WriteByte('{')
+
+	for i, field := range fields {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		value, ok := values[field]
+		if !ok {
+			// When the field is omitted as empty, e.g., tags
+			value = json.RawMessage("null")
+		}
+
+		// Field names are validated, and don't need escaping
+		fmt.Fprintf(&buffer, "%q:%s", field, value)
+	}
+
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// fieldNames returns the names of bookmark fields in JSON, from the tags of fields
+func fieldNames() []string {
+	var (
+		names       []string
+		bookmarkTyp = reflect.TypeOf(bookmark.Bookmark{})
+	)
+
+	for i := 0; i < bookmarkTyp.NumField(); i++ {
+		name, _, _ := strings.Cut(bookmarkTyp.Field(i).Tag.Get("json"), ",")
+		names = append(names, name)
+	}
+
+	return names
+}
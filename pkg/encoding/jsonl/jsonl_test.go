@@ -0,0 +1,142 @@
+package jsonl
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/mocks"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+var (
+	ptrStr                 = util.PtrStr
+	stringSliceToFlatBytes = util.StringSliceToFlatBytes
+)
+
+func TestEncoder_Encode(t *testing.T) {
+	var (
+		coder = bookmark.Bookmark{
+			URL:          ptrStr("https://github.com/vaguecoder"),
+			Title:        "Vague <Coder>",
+			Folder:       "toolbar",
+			ID:           8,
+			Parent:       3,
+			GUID:         "8rLXK7Gl2ayV",
+			Type:         bookmark.TypeBookmark,
+			DateAdded:    time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC),
+			LastModified: time.Date(2023, time.March, 4, 11, 37, 7, 0, time.UTC),
+			Tags:         []string{"github"},
+		}
+		goDev = bookmark.Bookmark{URL: ptrStr("https://go.dev/"), Title: "Go", ID: 9, Parent: 3,
+			Type: bookmark.TypeBookmark, Position: 1}
+	)
+
+	tests := []struct {
+		name      string
+		bookmarks []bookmark.Bookmark
+		fields    []string
+		writeErr  error
+		expected  []string
+		wantErr   bool
+	}{
+		{
+			name:      "All-Fields",
+			bookmarks: []bookmark.Bookmark{coder, goDev},
+			fields:    nil,
+			expected: []string{
+				`{"url":"https://github.com/vaguecoder","title":"Vague \u003cCoder\u003e","folder":"toolbar","id":8,` +
+					`"parent":3,"guid":"8rLXK7Gl2ayV","place-guid":"","type":1,"position":0,"keyword":"",` +
+					`"date-added":"2023-03-04T11:37:06Z","last-modified":"2023-03-04T11:37:07Z","tags":["github"]}`,
+				`{"url":"https://go.dev/","title":"Go","folder":"","id":9,"parent":3,"guid":"","place-guid":"",` +
+					`"type":1,"position":1,"keyword":"","date-added":"0001-01-01T00:00:00Z",` +
+					`"last-modified":"0001-01-01T00:00:00Z"}`,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Selected-Fields",
+			bookmarks: []bookmark.Bookmark{coder, goDev},
+			fields:    []string{"title", "url", "tags"},
+			expected: []string{
+				`{"title":"Vague \u003cCoder\u003e","url":"https://github.com/vaguecoder","tags":["github"]}`,
+				`{"title":"Go","url":"https://go.dev/","tags":null}`,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Failure-At-JSONL-Write",
+			bookmarks: []bookmark.Bookmark{goDev},
+			fields:    []string{"id"},
+			writeErr:  fmt.Errorf("some error"),
+			expected: []string{
+				`{"id":9}`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				nonFileWriter  = new(mocks.NonFileWriter)
+				expectedOutput = stringSliceToFlatBytes(tt.expected)
+			)
+
+			// The lines are written at once on flush
+			nonFileWriter.On("Write", expectedOutput).Return(len(expectedOutput), tt.writeErr).Once()
+
+			err := NewEncoder(nonFileWriter, tt.fields).Encode(tt.bookmarks)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from encode")
+			} else {
+				assert.NoError(t, err, "Unexpected error from encode")
+			}
+
+			nonFileWriter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEncoder_EncodeBookmark(t *testing.T) {
+	var (
+		nonFileWriter  = new(mocks.NonFileWriter)
+		encoder        = NewEncoder(nonFileWriter, []string{"id"})
+		expectedOutput = stringSliceToFlatBytes([]string{`{"id":1}`, `{"id":2}`})
+	)
+
+	nonFileWriter.On("Write", expectedOutput).Return(len(expectedOutput), nil).Once()
+
+	assert.NoError(t, encoder.EncodeBookmark(bookmark.Bookmark{ID: 1}), "Unexpected error from encode")
+	assert.NoError(t, encoder.EncodeBookmark(bookmark.Bookmark{ID: 2}), "Unexpected error from encode")
+
+	// Nothing is written ahead of flush
+	nonFileWriter.AssertNotCalled(t, "Write", expectedOutput)
+
+	assert.NoError(t, encoder.Flush(), "Unexpected error from flush")
+	nonFileWriter.AssertExpectations(t)
+}
+
+func TestValidateFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []string
+		wantErr bool
+	}{
+		{name: "Valid-Fields", fields: []string{"url", "date-added", "tags"}, wantErr: false},
+		{name: "No-Fields", fields: nil, wantErr: false},
+		{name: "Failure-Unknown-Field", fields: []string{"url", "href"}, wantErr: true},
+		{name: "Failure-Duplicate-Field", fields: []string{"url", "url"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFields(tt.fields)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from validation")
+			} else {
+				assert.NoError(t, err, "Unexpected error from validation")
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package jsonlz4
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+	"github.com/vaguecoder/firefox-backups/pkg/mozlz4"
+)
+
+// EncoderName is name of the encoder in current package, i.e., JSONLZ4.
+// JSONLZ4Format constant is parsed as EncoderName type here.
+var EncoderName = encoding.ToEncoder(constants.JSONLZ4Format)
+
+func init() {
+	// Register the encoder name in pkg/encoding.AllEncoders
+	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+}
+
+// Encoder is the manager for jsonlz4 encoder.
+// The output is Firefox's bookmarks backup, restorable with
+// Library > Import and Backup > Restore > Choose File...
+type Encoder struct {
+	out      io.Writer
+	filename string
+}
+
+// NewEncoder initializes new Encoder
+func NewEncoder(out io.Writer) *Encoder {
+	var filename string
+
+	// If the output stream is a file, specifically pkg/files.File,
+	// the filename can be extracted here. Just an optional requirement.
+	if file, ok := any(out).(files.File); ok {
+		filename = file.Name()
+	}
+
+	return &Encoder{
+		out:      out,
+		filename: filename,
+	}
+}
+
+// Encode encodes the input bookmarks as Firefox's backup tree in JSON,
+// compresses it in mozlz4 format and writes to already set output stream
+func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
+	data, err := json.Marshal(bookmark.BackupTree(bookmarks))
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+
+	if _, err = e.out.Write(mozlz4.Compress(data)); err != nil {
+		return fmt.Errorf("failed to write jsonlz4: %v", err)
+	}
+
+	return nil
+}
+
+// String returns the encoder name derived in EncoderName.
+// This returns the same value as EncoderName, but using the receiver.
+func (e *Encoder) String() string {
+	return EncoderName.String()
+}
+
+// Filename returns the file name string derived from output stream,
+// iff the output stream is of pkg/files.File type.
+func (e *Encoder) Filename() string {
+	return e.filename
+}
@@ -0,0 +1,151 @@
+package jsonlz4
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/mocks"
+	"github.com/vaguecoder/firefox-backups/pkg/mozlz4"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+var ptrStr = util.PtrStr
+
+func TestEncoder_Encode(t *testing.T) {
+	type fields struct {
+		filename     string
+		outputWriter io.Writer
+	}
+
+	type args struct {
+		bookmarks []bookmark.Bookmark
+	}
+
+	type toggles struct {
+		isExpectedError       bool
+		isFileWriter          bool
+		isErrorAtJSONLZ4Write bool
+	}
+
+	type testData struct {
+		name     string
+		fields   fields
+		args     args
+		toggles  toggles
+		expected string
+	}
+
+	var (
+		err            error
+		tests          []testData
+		testCase       testData
+		encoder        *Encoder
+		filename       string
+		expectedOutput []byte
+
+		fileWriter    = new(mocks.File)
+		nonFileWriter = new(mocks.NonFileWriter)
+	)
+
+	tests = []testData{
+		{
+			name: "Valid-Case",
+			fields: fields{
+				filename:     "firefox-bookmarks.jsonlz4",
+				outputWriter: fileWriter,
+			},
+			args: args{
+				bookmarks: []bookmark.Bookmark{
+					{URL: nil, Title: "", ID: 1, Parent: 0},
+					{URL: nil, Title: "menu", ID: 2, Parent: 1},
+					{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 6, Parent: 2},
+				},
+			},
+			toggles: toggles{
+				isExpectedError:       false,
+				isFileWriter:          true,
+				isErrorAtJSONLZ4Write: false,
+			},
+			expected: `{"guid":"root________","title":"","index":0,"dateAdded":0,"lastModified":0,"id":1,` +
+				`"typeCode":2,"type":"text/x-moz-place-container","root":"placesRoot","children":[` +
+				`{"guid":"menu________","title":"menu","index":0,"dateAdded":0,"lastModified":0,"id":2,` +
+				`"typeCode":2,"type":"text/x-moz-place-container","root":"bookmarksMenuFolder","children":[` +
+				`{"guid":"AAAAAAAAAAAG","title":"Vague Coder","index":0,"dateAdded":0,"lastModified":0,"id":6,` +
+				`"typeCode":1,"type":"text/x-moz-place","uri":"https://github.com/vaguecoder"}]}]}`,
+		},
+		{
+			name: "No-Bookmarks-With-Non-File-Writer",
+			fields: fields{
+				filename:     "firefox-bookmarks.jsonlz4",
+				outputWriter: nonFileWriter,
+			},
+			args: args{
+				bookmarks: []bookmark.Bookmark{},
+			},
+			toggles: toggles{
+				isExpectedError:       false,
+				isFileWriter:          false,
+				isErrorAtJSONLZ4Write: false,
+			},
+			expected: `{"guid":"root________","title":"","index":0,"dateAdded":0,"lastModified":0,"id":0,` +
+				`"typeCode":2,"type":"text/x-moz-place-container","root":"placesRoot"}`,
+		},
+		{
+			name: "Failure-At-JSONLZ4-Write",
+			fields: fields{
+				filename:     "firefox-bookmarks.jsonlz4",
+				outputWriter: fileWriter,
+			},
+			args: args{
+				bookmarks: []bookmark.Bookmark{},
+			},
+			toggles: toggles{
+				isExpectedError:       true,
+				isFileWriter:          true,
+				isErrorAtJSONLZ4Write: true,
+			},
+			expected: `{"guid":"root________","title":"","index":0,"dateAdded":0,"lastModified":0,"id":0,` +
+				`"typeCode":2,"type":"text/x-moz-place-container","root":"placesRoot"}`,
+		},
+	}
+	for _, testCase = range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			expectedOutput = mozlz4.Compress([]byte(testCase.expected))
+
+			var (
+				lengthOfExpectedOutput       = len(expectedOutput)
+				jsonlz4WriteErr        error = nil
+			)
+
+			if testCase.toggles.isErrorAtJSONLZ4Write {
+				jsonlz4WriteErr = fmt.Errorf("some error")
+			}
+
+			filename = ""
+			if testCase.toggles.isFileWriter {
+				filename = testCase.fields.filename
+				fileWriter.On("Name").Return(testCase.fields.filename).Once()
+				fileWriter.On("Write", expectedOutput).Return(lengthOfExpectedOutput, jsonlz4WriteErr).Once()
+			} else {
+				nonFileWriter.On("Write", expectedOutput).Return(lengthOfExpectedOutput, jsonlz4WriteErr).Once()
+			}
+
+			encoder = NewEncoder(testCase.fields.outputWriter)
+
+			err = encoder.Encode(testCase.args.bookmarks)
+
+			if testCase.toggles.isExpectedError {
+				assert.Error(t, err, "Expected error from encode")
+			} else {
+				assert.NoError(t, err, "Unexpected error from encode")
+			}
+
+			assert.Equal(t, filename, encoder.Filename(), "Mismatch of filename in writer")
+			assert.Equal(t, constants.JSONLZ4Format.String(), encoder.String(), "Mismatch of encoder name string")
+		})
+	}
+}
@@ -6,15 +6,25 @@ import (
 	"reflect"
 
 	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
 	"github.com/vaguecoder/firefox-backups/pkg/logs"
 )
 
-// EncodingManager holds bookmarks and
-// target encoders to parse bookmarks to
+// EncodingManager holds bookmarks, history visits or links, and
+// target encoders to parse them to
 type EncodingManager struct {
 	encoders  []Encoder
 	bookmarks []bookmark.Bookmark
+	visits    []history.Visit
+	links     []links.Link
 	logger    logs.Logger
+
+	// isVisits is set when visits are added, as nil visits are valid with no history
+	isVisits bool
+
+	// isLinks is set when links are added, as nil links are valid with no http bookmarks
+	isLinks bool
 }
 
 // NewEncoderManager initiates new EncodingManager
@@ -22,6 +32,8 @@ func NewEncoderManager(ctx context.Context) *EncodingManager {
 	return &EncodingManager{
 		encoders:  []Encoder{},
 		bookmarks: nil,
+		visits:    nil,
+		links:     nil,
 		logger:    logs.FromContext(ctx),
 	}
 }
@@ -51,8 +63,38 @@ func (e *EncodingManager) Bookmarks(bookmarks []bookmark.Bookmark) *EncodingMana
 	return e
 }
 
-// Write encodes the bookmarks to all the encoders added to manager
+// Visits appends browsing history visits to manager.
+// When visits are added, they are encoded instead of bookmarks.
+// Both receiver and return value are of same type to implement builder's pattern.
+func (e *EncodingManager) Visits(visits []history.Visit) *EncodingManager {
+	e.visits = visits
+	e.isVisits = true
+
+	return e
+}
+
+// Links appends the checked links of bookmarks to manager.
+// When links are added, they are encoded instead of bookmarks.
+// Both receiver and return value are of same type to implement builder's pattern.
+func (e *EncodingManager) Links(links []links.Link) *EncodingManager {
+	e.links = links
+	e.isLinks = true
+
+	return e
+}
+
+// Write encodes the bookmarks, or the visits or links if added, to all the encoders added to manager
 func (e *EncodingManager) Write() error {
+	if e.isVisits {
+		// When visits are added, bookmarks aren't required
+		return e.writeVisits()
+	}
+
+	if e.isLinks {
+		// When links are added, bookmarks aren't required
+		return e.writeLinks()
+	}
+
 	if e.bookmarks == nil {
 		// When no bookmarks provided
 		return fmt.Errorf("bookmarks missing in chaining")
@@ -89,3 +131,162 @@ func (e *EncodingManager) Write() error {
 
 	return nil
 }
+
+// Stream encodes the bookmarks to all the encoders added to manager, one at a time as they're fetched,
+// so the bookmarks aren't held in memory. The fetch calls encode for each bookmark, and stops on its error.
+// All the encoders are validated to be stream encoders before fetching any.
+func (e *EncodingManager) Stream(fetch func(encode func(bookmark.Bookmark) error) error) error {
+	var (
+		err            error
+		ok             bool
+		count          int
+		encoder        Encoder
+		streamEncoder  StreamEncoder
+		streamEncoders = make([]StreamEncoder, 0, len(e.encoders))
+		subLogger      logs.Logger
+	)
+
+	for _, encoder = range e.encoders {
+		if streamEncoder, ok = encoder.(StreamEncoder); !ok {
+			// When the encoder's format needs all the bookmarks at once
+			return fmt.Errorf("encoder %q doesn't support streaming", encoder)
+		}
+
+		streamEncoders = append(streamEncoders, streamEncoder)
+	}
+
+	err = fetch(func(b bookmark.Bookmark) error {
+		for index, streamEncoder := range streamEncoders {
+			if err := streamEncoder.EncodeBookmark(b); err != nil {
+				// When encountered error while encoding
+				return fmt.Errorf("failed to encode to %q: %v", e.encoders[index], err)
+			}
+		}
+
+		count++
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream bookmarks: %v", err)
+	}
+
+	// Iterate over encoders in manager
+	for index, streamEncoder := range streamEncoders {
+		encoder = e.encoders[index]
+
+		// Sub-logger to hold current encoder's filename and encoder name
+		subLogger = logs.FromRawLogger(e.logger.With().Str("filename", encoder.Filename()).
+			Stringer("encoder", encoder).Logger())
+
+		if err = streamEncoder.Flush(); err != nil {
+			// When encountered error while writing the buffered bookmarks
+			subLogger.Error().Err(err).Msg("Failed to flush")
+
+			return fmt.Errorf("failed to encode to %q: %v", encoder, err)
+		}
+
+		// When encoding is successful
+		subLogger.Info().Int("count", count).Msg("Successfully streamed to output stream/file")
+	}
+
+	return nil
+}
+
+// writeVisits encodes the visits to all the encoders added to manager.
+// All the encoders are validated to be visit encoders before writing any.
+func (e *EncodingManager) writeVisits() error {
+	if len(e.encoders) == 0 || len(e.visits) == 0 {
+		// When no encoders provided or no visits in the list.
+		// Skipping.
+		return nil
+	}
+
+	var (
+		err           error
+		ok            bool
+		encoder       Encoder
+		visitEncoder  VisitEncoder
+		visitEncoders = make([]VisitEncoder, 0, len(e.encoders))
+		subLogger     logs.Logger
+	)
+
+	for _, encoder = range e.encoders {
+		if visitEncoder, ok = encoder.(VisitEncoder); !ok {
+			// When the encoder's format can only hold bookmarks
+			return fmt.Errorf("encoder %q doesn't support history visits", encoder)
+		}
+
+		visitEncoders = append(visitEncoders, visitEncoder)
+	}
+
+	// Iterate over encoders in manager
+	for index, visitEncoder := range visitEncoders {
+		encoder = e.encoders[index]
+
+		// Sub-logger to hold current encoder's filename and encoder name
+		subLogger = logs.FromRawLogger(e.logger.With().Str("filename", encoder.Filename()).
+			Stringer("encoder", encoder).Logger())
+
+		if err = visitEncoder.EncodeVisits(e.visits); err != nil {
+			// When encountered error while encoding
+			subLogger.Error().Err(err).Msg("Failed to encode visits")
+
+			return fmt.Errorf("failed to encode visits to %q: %v", encoder, err)
+		}
+
+		// When encoding is successful
+		subLogger.Info().Int("count", len(e.visits)).Msg("Successfully encoded visits to output stream/file")
+	}
+
+	return nil
+}
+
+// writeLinks encodes the links to all the encoders added to manager.
+// All the encoders are validated to be link encoders before writing any.
+func (e *EncodingManager) writeLinks() error {
+	if len(e.encoders) == 0 || len(e.links) == 0 {
+		// When no encoders provided or no links in the list.
+		// Skipping.
+		return nil
+	}
+
+	var (
+		err          error
+		ok           bool
+		encoder      Encoder
+		linkEncoder  LinkEncoder
+		linkEncoders = make([]LinkEncoder, 0, len(e.encoders))
+		subLogger    logs.Logger
+	)
+
+	for _, encoder = range e.encoders {
+		if linkEncoder, ok = encoder.(LinkEncoder); !ok {
+			// When the encoder's format can only hold bookmarks
+			return fmt.Errorf("encoder %q doesn't support links", encoder)
+		}
+
+		linkEncoders = append(linkEncoders, linkEncoder)
+	}
+
+	// Iterate over encoders in manager
+	for index, linkEncoder := range linkEncoders {
+		encoder = e.encoders[index]
+
+		// Sub-logger to hold current encoder's filename and encoder name
+		subLogger = logs.FromRawLogger(e.logger.With().Str("filename", encoder.Filename()).
+			Stringer("encoder", encoder).Logger())
+
+		if err = linkEncoder.EncodeLinks(e.links); err != nil {
+			// When encountered error while encoding
+			subLogger.Error().Err(err).Msg("Failed to encode links")
+
+			return fmt.Errorf("failed to encode links to %q: %v", encoder, err)
+		}
+
+		// When encoding is successful
+		subLogger.Info().Int("count", len(e.links)).Msg("Successfully encoded links to output stream/file")
+	}
+
+	return nil
+}
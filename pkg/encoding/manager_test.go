@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
 	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
 	"github.com/vaguecoder/firefox-backups/pkg/mocks"
 	"github.com/vaguecoder/firefox-backups/pkg/util"
 )
@@ -452,3 +456,290 @@ func TestEncodingManager_Write(t *testing.T) {
 		})
 	}
 }
+
+// visitEncoder is a mock of the encoder which encodes visits too
+type visitEncoder struct {
+	*mocks.Encoder
+	*mocks.VisitEncoder
+}
+
+func TestEncodingManager_Write_Visits(t *testing.T) {
+	var (
+		someErr error = fmt.Errorf("some error")
+		ctx           = context.Background()
+		visits        = []history.Visit{
+			{
+				ID:        1,
+				URL:       "https://github.com/vaguecoder",
+				Title:     "Vague Coder",
+				VisitDate: time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC),
+				VisitType: 1,
+			},
+		}
+	)
+
+	tests := []struct {
+		name           string
+		visits         []history.Visit
+		isBookmarkOnly bool
+		isEncodeErr    bool
+		wantErr        bool
+	}{
+		{
+			name:           "Valid-Case",
+			visits:         visits,
+			isBookmarkOnly: false,
+			isEncodeErr:    false,
+			wantErr:        false,
+		},
+		{
+			name:           "Empty-Visits",
+			visits:         []history.Visit{},
+			isBookmarkOnly: false,
+			isEncodeErr:    false,
+			wantErr:        false,
+		},
+		{
+			name:           "Failure-At-Encode",
+			visits:         visits,
+			isBookmarkOnly: false,
+			isEncodeErr:    true,
+			wantErr:        true,
+		},
+		{
+			name:           "Failure-Bookmark-Only-Encoder",
+			visits:         visits,
+			isBookmarkOnly: true,
+			isEncodeErr:    false,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				encoderManager = NewEncoderManager(ctx).Visits(tt.visits)
+				encoder        = &visitEncoder{Encoder: new(mocks.Encoder), VisitEncoder: new(mocks.VisitEncoder)}
+				encodeErr      error
+			)
+
+			if tt.isEncodeErr {
+				encodeErr = someErr
+			}
+
+			if tt.isBookmarkOnly {
+				// Bookmark-only encoder doesn't implement VisitEncoder
+				encoder.Encoder.On("String").Return(constants.HTMLFormat.String())
+				encoderManager = encoderManager.Encoder(encoder.Encoder)
+			} else {
+				encoder.Encoder.On("Filename").Return("firefox-history.json")
+				encoder.Encoder.On("String").Return(constants.JSONFormat.String())
+				encoder.VisitEncoder.On("EncodeVisits", tt.visits).Return(encodeErr)
+				encoderManager = encoderManager.Encoder(encoder)
+			}
+
+			err := encoderManager.Write()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from encoder write")
+			} else {
+				assert.NoError(t, err, "Unexpected error from encoder write")
+			}
+
+			encoder.Encoder.AssertNotCalled(t, "Encode", mock.Anything)
+		})
+	}
+}
+
+// linkEncoder is a mock of the encoder which encodes links too
+type linkEncoder struct {
+	*mocks.Encoder
+	*mocks.LinkEncoder
+}
+
+func TestEncodingManager_Write_Links(t *testing.T) {
+	var (
+		someErr error = fmt.Errorf("some error")
+		ctx           = context.Background()
+		checked       = []links.Link{
+			{
+				URL:         "http://golang.org/",
+				Title:       "Go",
+				Status:      200,
+				RedirectURL: "https://go.dev/",
+				Permanent:   true,
+			},
+		}
+	)
+
+	tests := []struct {
+		name           string
+		links          []links.Link
+		isBookmarkOnly bool
+		isEncodeErr    bool
+		wantErr        bool
+	}{
+		{
+			name:           "Valid-Case",
+			links:          checked,
+			isBookmarkOnly: false,
+			isEncodeErr:    false,
+			wantErr:        false,
+		},
+		{
+			name:           "Empty-Links",
+			links:          []links.Link{},
+			isBookmarkOnly: false,
+			isEncodeErr:    false,
+			wantErr:        false,
+		},
+		{
+			name:           "Failure-At-Encode",
+			links:          checked,
+			isBookmarkOnly: false,
+			isEncodeErr:    true,
+			wantErr:        true,
+		},
+		{
+			name:           "Failure-Bookmark-Only-Encoder",
+			links:          checked,
+			isBookmarkOnly: true,
+			isEncodeErr:    false,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				encoderManager = NewEncoderManager(ctx).Links(tt.links)
+				encoder        = &linkEncoder{Encoder: new(mocks.Encoder), LinkEncoder: new(mocks.LinkEncoder)}
+				encodeErr      error
+			)
+
+			if tt.isEncodeErr {
+				encodeErr = someErr
+			}
+
+			if tt.isBookmarkOnly {
+				// Bookmark-only encoder doesn't implement LinkEncoder
+				encoder.Encoder.On("String").Return(constants.HTMLFormat.String())
+				encoderManager = encoderManager.Encoder(encoder.Encoder)
+			} else {
+				encoder.Encoder.On("Filename").Return("firefox-links.json")
+				encoder.Encoder.On("String").Return(constants.JSONFormat.String())
+				encoder.LinkEncoder.On("EncodeLinks", tt.links).Return(encodeErr)
+				encoderManager = encoderManager.Encoder(encoder)
+			}
+
+			err := encoderManager.Write()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from encoder write")
+			} else {
+				assert.NoError(t, err, "Unexpected error from encoder write")
+			}
+
+			encoder.Encoder.AssertNotCalled(t, "Encode", mock.Anything)
+		})
+	}
+}
+
+// streamEncoder is a mock of the encoder which encodes bookmarks one at a time too
+type streamEncoder struct {
+	*mocks.Encoder
+	*mocks.StreamEncoder
+}
+
+func TestEncodingManager_Stream(t *testing.T) {
+	var (
+		someErr   error = fmt.Errorf("some error")
+		ctx             = context.Background()
+		bookmarks       = []bookmark.Bookmark{
+			{ID: 1, Title: "Vague Coder", URL: ptrStr("https://github.com/vaguecoder"), Type: bookmark.TypeBookmark},
+			{ID: 2, Title: "Go", URL: ptrStr("https://go.dev/"), Type: bookmark.TypeBookmark},
+		}
+	)
+
+	tests := []struct {
+		name           string
+		isBookmarkOnly bool
+		fetchErr       error
+		encodeErr      error
+		flushErr       error
+		wantErr        bool
+	}{
+		{
+			name:    "Valid-Case",
+			wantErr: false,
+		},
+		{
+			name:     "Failure-At-Fetch",
+			fetchErr: someErr,
+			wantErr:  true,
+		},
+		{
+			name:      "Failure-At-Encode",
+			encodeErr: someErr,
+			wantErr:   true,
+		},
+		{
+			name:     "Failure-At-Flush",
+			flushErr: someErr,
+			wantErr:  true,
+		},
+		{
+			name:           "Failure-Non-Stream-Encoder",
+			isBookmarkOnly: true,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				encoderManager = NewEncoderManager(ctx)
+				encoder        = &streamEncoder{Encoder: new(mocks.Encoder), StreamEncoder: new(mocks.StreamEncoder)}
+				fetched        int
+			)
+
+			encoder.Encoder.On("Filename").Return("firefox-backups.jsonl")
+			encoder.Encoder.On("String").Return(constants.JSONLFormat.String())
+
+			if tt.isBookmarkOnly {
+				// Bookmark-only encoder doesn't implement StreamEncoder
+				encoderManager = encoderManager.Encoder(encoder.Encoder)
+			} else {
+				encoder.StreamEncoder.On("EncodeBookmark", mock.Anything).Return(tt.encodeErr)
+				encoder.StreamEncoder.On("Flush").Return(tt.flushErr)
+				encoderManager = encoderManager.Encoder(encoder)
+			}
+
+			err := encoderManager.Stream(func(encode func(bookmark.Bookmark) error) error {
+				for _, b := range bookmarks {
+					if tt.fetchErr != nil {
+						return tt.fetchErr
+					}
+
+					if err := encode(b); err != nil {
+						return err
+					}
+
+					fetched++
+				}
+
+				return nil
+			})
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from encoder stream")
+			} else {
+				assert.NoError(t, err, "Unexpected error from encoder stream")
+				assert.Equal(t, len(bookmarks), fetched, "Mismatch of count of streamed bookmarks")
+				encoder.StreamEncoder.AssertNumberOfCalls(t, "EncodeBookmark", len(bookmarks))
+				encoder.StreamEncoder.AssertNumberOfCalls(t, "Flush", 1)
+			}
+
+			if tt.isBookmarkOnly || tt.fetchErr != nil {
+				// Nothing is encoded when the encoders aren't validated, or the fetch failed ahead
+				encoder.StreamEncoder.AssertNotCalled(t, "EncodeBookmark", mock.Anything)
+			}
+
+			encoder.Encoder.AssertNotCalled(t, "Encode", mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+)
+
+const (
+	// maxHeadingLevel is the deepest heading in Markdown, and the deeper folders are written at it
+	maxHeadingLevel = 6
+
+	// frontMatterDelimiter delimits the YAML front matter
+	frontMatterDelimiter = `---`
+
+	// thematicBreak is written for separators
+	thematicBreak = `***`
+)
+
+var (
+	// titleEscaper escapes the characters of Markdown syntax in titles
+	titleEscaper = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`, `*`, `\*`, `_`, `\_`, "`", "\\`",
+		`<`, `\<`, `#`, `\#`, "\n", " ")
+
+	// urlEscaper escapes the characters ending the link destination in URLs
+	urlEscaper = strings.NewReplacer(` `, `%20`, `(`, `%28`, `)`, `%29`, `<`, `%3C`, `>`, `%3E`)
+)
+
+// EncoderName is name of the encoder in current package, i.e., Markdown.
+// MarkdownFormat constant is parsed as EncoderName type here.
+var EncoderName = encoding.ToEncoder(constants.MarkdownFormat)
+
+func init() {
+	// Register the encoder name in pkg/encoding.AllEncoders
+	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+}
+
+// Encoder is the manager for Markdown encoder.
+// Folders are written as headings, and the bookmarks in them as lists of links,
+// ahead of the headings of their subfolders.
+type Encoder struct {
+	out         io.Writer
+	filename    string
+	frontMatter encoding.FrontMatter
+}
+
+// NewEncoder initializes new Encoder
+func NewEncoder(out io.Writer) *Encoder {
+	var filename string
+
+	// If the output stream is a file, specifically pkg/files.File,
+	// the filename can be extracted here. Just an optional requirement.
+	if file, ok := any(out).(files.File); ok {
+		filename = file.Name()
+	}
+
+	return &Encoder{
+		out:      out,
+		filename: filename,
+	}
+}
+
+// SetFrontMatter sets the front matter written in YAML ahead of the bookmarks
+func (e *Encoder) SetFrontMatter(frontMatter encoding.FrontMatter) {
+	e.frontMatter = frontMatter
+}
+
+// Encode encodes the input bookmarks in Markdown format to already set output stream.
+// The folder hierarchy is rebuilt from ID and Parent fields of bookmarks, or from the folder paths
+// of denormalized ones.
+func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
+	var buffer bytes.Buffer
+
+	if !e.frontMatter.IsZero() {
+		writeBlock(&buffer, frontMatter(e.frontMatter))
+	}
+
+	writeNodes(&buffer, encoding.NewTree(bookmarks), 1)
+
+	// Write the document at once, so the output stream isn't left partially written
+	if _, err := e.out.Write(buffer.Bytes()); err != nil {
+		return fmt.Errorf("failed to write Markdown: %v", err)
+	}
+
+	return nil
+}
+
+// String returns the encoder name derived in EncoderName.
+// This returns the same value as EncoderName, but using the receiver.
+func (e *Encoder) String() string {
+	return EncoderName.String()
+}
+
+// Filename returns the file name string derived from output stream,
+// iff the output stream is of pkg/files.File type.
+func (e *Encoder) Filename() string {
+	return e.filename
+}
+
+// frontMatter returns the YAML front matter, with the fields of zero values omitted
+func frontMatter(f encoding.FrontMatter) string {
+	lines := []string{frontMatterDelimiter}
+
+	if !f.Date.IsZero() {
+		lines = append(lines, "date: "+f.Date.Format(time.RFC3339))
+	}
+
+	if f.Profile != "" {
+		lines = append(lines, "profile: "+strconv.Quote(f.Profile))
+	}
+
+	return strings.Join(append(lines, frontMatterDelimiter), "\n")
+}
+
+// writeNodes writes the bookmarks and separators of the nodes, followed by the folders and their children
+// recursively, at the specified heading level
+func writeNodes(buffer *bytes.Buffer, nodes []encoding.TreeNode, level int) {
+	var list []string
+
+	for _, node := range nodes {
+		switch {
+		case node.Children != nil:
+			// Folders are written after the bookmarks
+		case node.Type == bookmark.TypeSeparator:
+			writeBlock(buffer, list...)
+			writeBlock(buffer, thematicBreak)
+			list = nil
+		default:
+			list = append(list, "- "+link(node.Bookmark))
+		}
+	}
+
+	writeBlock(buffer, list...)
+
+	for _, node := range nodes {
+		if node.Children == nil {
+			continue
+		}
+
+		writeBlock(buffer, strings.Repeat("#", min(level, maxHeadingLevel))+" "+titleEscaper.Replace(node.Title))
+		writeNodes(buffer, *node.Children, level+1)
+	}
+}
+
+// writeBlock writes the lines as a block, separated from the former block by a blank line
+func writeBlock(buffer *bytes.Buffer, lines ...string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	if buffer.Len() > 0 {
+		buffer.WriteString("\n")
+	}
+
+	for _, line := range lines {
+		buffer.WriteString(line + "\n")
+	}
+}
+
+// link returns the Markdown link of the bookmark, titled with the URL when the title is blank
+func link(b bookmark.Bookmark) string {
+	var url string
+	if b.URL != nil {
+		url = *b.URL
+	}
+
+	title := b.Title
+	if title == "" {
+		title = url
+	}
+
+	return fmt.Sprintf("[%s](%s)", titleEscaper.Replace(title), urlEscaper.Replace(url))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,133 @@
+package markdown
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+	"github.com/vaguecoder/firefox-backups/pkg/mocks"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+var (
+	ptrStr                 = util.PtrStr
+	stringSliceToFlatBytes = util.StringSliceToFlatBytes
+)
+
+func TestEncoder_Encode(t *testing.T) {
+	var (
+		root    = bookmark.Bookmark{ID: 1, GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder}
+		toolbar = bookmark.Bookmark{ID: 3, Parent: 1, Title: "toolbar", GUID: "toolbar_____", Type: bookmark.TypeFolder}
+		golang  = bookmark.Bookmark{ID: 7, Parent: 3, Title: "Go *Dev*", Type: bookmark.TypeFolder}
+		github  = bookmark.Bookmark{ID: 8, Parent: 3, Title: "GitHub", URL: ptrStr("https://github.com/"),
+			Type: bookmark.TypeBookmark}
+		separator = bookmark.Bookmark{ID: 9, Parent: 3, Type: bookmark.TypeSeparator}
+		untitled  = bookmark.Bookmark{ID: 10, Parent: 3, URL: ptrStr("https://example.com/a (b)"),
+			Type: bookmark.TypeBookmark}
+		goDev = bookmark.Bookmark{ID: 11, Parent: 7, Title: "[Go]", URL: ptrStr("https://go.dev/"),
+			Type: bookmark.TypeBookmark}
+
+		// Denormalized records, i.e., with folder paths, and without the folders they're in
+		denormalized = func() []bookmark.Bookmark {
+			github, goDev := github, goDev
+			github.Folder, goDev.Folder = "toolbar", "toolbar/Go *Dev*"
+
+			return []bookmark.Bookmark{github, goDev}
+		}
+	)
+
+	tests := []struct {
+		name        string
+		bookmarks   []bookmark.Bookmark
+		frontMatter encoding.FrontMatter
+		writeErr    error
+		expected    []string
+		wantErr     bool
+	}{
+		{
+			name:      "Valid-Case",
+			bookmarks: []bookmark.Bookmark{root, toolbar, golang, github, separator, untitled, goDev},
+			expected: []string{
+				`# toolbar`,
+				``,
+				`- [GitHub](https://github.com/)`,
+				``,
+				`***`,
+				``,
+				`- [https://example.com/a (b)](https://example.com/a%20%28b%29)`,
+				``,
+				`## Go \*Dev\*`,
+				``,
+				`- [\[Go\]](https://go.dev/)`,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Denormalized-Bookmarks",
+			bookmarks: denormalized(),
+			expected: []string{
+				`# toolbar`,
+				``,
+				`- [GitHub](https://github.com/)`,
+				``,
+				`## Go \*Dev\*`,
+				``,
+				`- [\[Go\]](https://go.dev/)`,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Front-Matter",
+			bookmarks: []bookmark.Bookmark{root, toolbar, github},
+			frontMatter: encoding.FrontMatter{
+				Date:    time.Date(2023, time.March, 4, 5, 6, 7, 0, time.UTC),
+				Profile: "default-release",
+			},
+			expected: []string{
+				`---`,
+				`date: 2023-03-04T05:06:07Z`,
+				`profile: "default-release"`,
+				`---`,
+				``,
+				`# toolbar`,
+				``,
+				`- [GitHub](https://github.com/)`,
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Failure-At-Markdown-Write",
+			bookmarks: []bookmark.Bookmark{root, toolbar},
+			writeErr:  fmt.Errorf("some error"),
+			expected: []string{
+				`# toolbar`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				nonFileWriter  = new(mocks.NonFileWriter)
+				expectedOutput = stringSliceToFlatBytes(tt.expected)
+			)
+
+			nonFileWriter.On("Write", expectedOutput).Return(len(expectedOutput), tt.writeErr).Once()
+
+			encoder := NewEncoder(nonFileWriter)
+			encoder.SetFrontMatter(tt.frontMatter)
+
+			err := encoder.Encode(tt.bookmarks)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from encode")
+			} else {
+				assert.NoError(t, err, "Unexpected error from encode")
+			}
+
+			nonFileWriter.AssertExpectations(t)
+		})
+	}
+}
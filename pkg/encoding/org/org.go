@@ -0,0 +1,142 @@
+package org
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+)
+
+// horizontalRule is written for separators
+const horizontalRule = `-----`
+
+var (
+	// urlEscaper escapes the brackets and backslashes in link, as in Org-mode 9.3 and later
+	urlEscaper = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
+
+	// descriptionEscaper replaces the brackets in link description, which can't be escaped
+	descriptionEscaper = strings.NewReplacer(`[`, `{`, `]`, `}`, "\n", " ")
+
+	// headingEscaper replaces the line breaks in headings
+	headingEscaper = strings.NewReplacer("\n", " ")
+)
+
+// EncoderName is name of the encoder in current package, i.e., Org.
+// OrgFormat constant is parsed as EncoderName type here.
+var EncoderName = encoding.ToEncoder(constants.OrgFormat)
+
+func init() {
+	// Register the encoder name in pkg/encoding.AllEncoders
+	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+}
+
+// Encoder is the manager for Org-mode encoder.
+// Folders are written as headings, and the bookmarks in them as lists of links,
+// ahead of the headings of their subfolders.
+type Encoder struct {
+	out         io.Writer
+	filename    string
+	frontMatter encoding.FrontMatter
+}
+
+// NewEncoder initializes new Encoder
+func NewEncoder(out io.Writer) *Encoder {
+	var filename string
+
+	// If the output stream is a file, specifically pkg/files.File,
+	// the filename can be extracted here. Just an optional requirement.
+	if file, ok := any(out).(files.File); ok {
+		filename = file.Name()
+	}
+
+	return &Encoder{
+		out:      out,
+		filename: filename,
+	}
+}
+
+// SetFrontMatter sets the front matter written as in-buffer settings, i.e., #+DATE and #+PROFILE,
+// ahead of the bookmarks
+func (e *Encoder) SetFrontMatter(frontMatter encoding.FrontMatter) {
+	e.frontMatter = frontMatter
+}
+
+// Encode encodes the input bookmarks in Org-mode format to already set output stream.
+// The folder hierarchy is rebuilt from ID and Parent fields of bookmarks, or from the folder paths
+// of denormalized ones.
+func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
+	var buffer bytes.Buffer
+
+	if !e.frontMatter.Date.IsZero() {
+		fmt.Fprintf(&buffer, "#+DATE: %s\n", e.frontMatter.Date.Format(time.RFC3339))
+	}
+
+	if e.frontMatter.Profile != "" {
+		fmt.Fprintf(&buffer, "#+PROFILE: %s\n", headingEscaper.Replace(e.frontMatter.Profile))
+	}
+
+	writeNodes(&buffer, encoding.NewTree(bookmarks), 1)
+
+	// Write the document at once, so the output stream isn't left partially written
+	if _, err := e.out.Write(buffer.Bytes()); err != nil {
+		return fmt.Errorf("failed to write Org: %v", err)
+	}
+
+	return nil
+}
+
+// String returns the encoder name derived in EncoderName.
+// This returns the same value as EncoderName, but using the receiver.
+func (e *Encoder) String() string {
+	return EncoderName.String()
+}
+
+// Filename returns the file name string derived from output stream,
+// iff the output stream is of pkg/files.File type.
+func (e *Encoder) Filename() string {
+	return e.filename
+}
+
+// writeNodes writes the bookmarks and separators of the nodes, followed by the folders and their children
+// recursively, at the specified heading level
+func writeNodes(buffer *bytes.Buffer, nodes []encoding.TreeNode, level int) {
+	for _, node := range nodes {
+		switch {
+		case node.Children != nil:
+			// Folders are written after the bookmarks
+		case node.Type == bookmark.TypeSeparator:
+			buffer.WriteString(horizontalRule + "\n")
+		default:
+			buffer.WriteString("- " + link(node.Bookmark) + "\n")
+		}
+	}
+
+	for _, node := range nodes {
+		if node.Children == nil {
+			continue
+		}
+
+		buffer.WriteString(strings.Repeat("*", level) + " " + headingEscaper.Replace(node.Title) + "\n")
+		writeNodes(buffer, *node.Children, level+1)
+	}
+}
+
+// link returns the Org-mode link of the bookmark, without description when the title is blank
+func link(b bookmark.Bookmark) string {
+	var url string
+	if b.URL != nil {
+		url = *b.URL
+	}
+
+	if b.Title == "" {
+		return fmt.Sprintf("[[%s]]", urlEscaper.Replace(url))
+	}
+
+	return fmt.Sprintf("[[%s][%s]]", urlEscaper.Replace(url), descriptionEscaper.Replace(b.Title))
+}
@@ -0,0 +1,211 @@
+package site
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+)
+
+// placeholderColors are the background colors of favicon placeholders, picked by the host name
+var placeholderColors = []string{"#0060df", "#058b00", "#a4000f", "#8000d7", "#d76e00", "#00828c", "#b5007f", "#4a4a4f"}
+
+// rootTitles maps the Firefox built-in root folders to their headings in the page, same as in Firefox's library
+var rootTitles = map[string]string{
+	bookmark.MenuRoot:    "Bookmarks Menu",
+	bookmark.ToolbarRoot: "Bookmarks Toolbar",
+	bookmark.UnfiledRoot: "Other Bookmarks",
+	bookmark.MobileRoot:  "Mobile Bookmarks",
+}
+
+// pageTemplate is the template of the page, with the styles and the script inlined,
+// so the page is served as a single file without any external resources
+//
+//go:embed site.html
+var pageTemplate string
+
+// page is parsed once, as the template is the same for all the encoders
+var page = template.Must(template.New("site").Parse(pageTemplate))
+
+// EncoderName is name of the encoder in current package, i.e., Site.
+// SiteFormat constant is parsed as EncoderName type here.
+var EncoderName = encoding.ToEncoder(constants.SiteFormat)
+
+func init() {
+	// Register the encoder name in pkg/encoding.AllEncoders
+	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+}
+
+// Encoder is the manager for Site encoder.
+// The output is a self-contained HTML page with a collapsible folder tree and a filter box,
+// to be published on a static host.
+type Encoder struct {
+	out      io.Writer
+	filename string
+}
+
+// NewEncoder initializes new Encoder
+func NewEncoder(out io.Writer) *Encoder {
+	var filename string
+
+	// If the output stream is a file, specifically pkg/files.File,
+	// the filename can be extracted here. Just an optional requirement.
+	if file, ok := any(out).(files.File); ok {
+		filename = file.Name()
+	}
+
+	return &Encoder{
+		out:      out,
+		filename: filename,
+	}
+}
+
+// node is a bookmark, separator or folder as rendered in the page template
+type node struct {
+	Title     string
+	URL       string
+	Host      string
+	Initial   string // Initial of the host, in the favicon placeholder
+	Color     string // Background color of the favicon placeholder
+	Search    string // Lower-cased text matched against the filter box
+	Tags      []string
+	Folder    bool
+	Open      bool // Folder is expanded on load
+	Separator bool
+	Count     int // Count of bookmarks in the folder, including its subfolders
+	Children  []node
+}
+
+// pageData is the data of the page template
+type pageData struct {
+	Count int
+	Nodes []node
+}
+
+// Encode encodes the input bookmarks in Site format to already set output stream.
+// The folder hierarchy is rebuilt from ID and Parent fields of bookmarks, or from the folder paths
+// of denormalized ones.
+func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
+	var (
+		buffer bytes.Buffer
+		data   pageData
+	)
+
+	data.Nodes, data.Count = newNodes(encoding.NewTree(bookmarks), true)
+
+	// Top-level folders are expanded on load, while their subfolders are collapsed
+	for i := range data.Nodes {
+		data.Nodes[i].Open = data.Nodes[i].Folder
+	}
+
+	if err := page.Execute(&buffer, data); err != nil {
+		return fmt.Errorf("failed to execute site template: %v", err)
+	}
+
+	// Write the document at once, so the output stream isn't left partially written
+	if _, err := e.out.Write(buffer.Bytes()); err != nil {
+		return fmt.Errorf("failed to write site: %v", err)
+	}
+
+	return nil
+}
+
+// String returns the encoder name derived in EncoderName.
+// This returns the same value as EncoderName, but using the receiver.
+func (e *Encoder) String() string {
+	return EncoderName.String()
+}
+
+// Filename returns the file name string derived from output stream,
+// iff the output stream is of pkg/files.File type.
+func (e *Encoder) Filename() string {
+	return e.filename
+}
+
+// newNodes converts the tree nodes and their children recursively, and returns them
+// along with the count of bookmarks in them. isTopLevel is set for the top-level nodes,
+// i.e., the root folders, which are titled as in Firefox's library.
+func newNodes(treeNodes []encoding.TreeNode, isTopLevel bool) ([]node, int) {
+	var (
+		result = make([]node, 0, len(treeNodes))
+		count  int
+	)
+
+	for _, treeNode := range treeNodes {
+		switch {
+		case treeNode.Type == bookmark.TypeSeparator:
+			result = append(result, node{Separator: true})
+		case treeNode.Children != nil:
+			title, ok := rootTitles[treeNode.Title]
+			if !ok || !isTopLevel {
+				// When the folder is a user-created folder
+				title = treeNode.Title
+			}
+
+			children, childCount := newNodes(*treeNode.Children, false)
+			result = append(result, node{Title: title, Folder: true, Count: childCount, Children: children})
+			count += childCount
+		default:
+			result = append(result, newBookmark(treeNode.Bookmark))
+			count++
+		}
+	}
+
+	return result, count
+}
+
+// newBookmark converts the bookmark, titled with the URL when the title is blank
+func newBookmark(b bookmark.Bookmark) node {
+	result := node{Title: strings.TrimSpace(b.Title), Tags: b.Tags}
+
+	if b.URL != nil {
+		result.URL = *b.URL
+	}
+
+	if result.Title == "" {
+		result.Title = result.URL
+	}
+
+	if parsed, err := url.Parse(result.URL); err == nil {
+		result.Host = strings.TrimPrefix(parsed.Hostname(), "www.")
+	}
+
+	result.Initial, result.Color = placeholder(result.Host, result.Title)
+	result.Search = strings.ToLower(strings.Join(append([]string{result.Title, result.URL}, b.Tags...), " "))
+
+	return result
+}
+
+// placeholder returns the initial and the color of favicon placeholder, derived from the host,
+// or from the title in absence of host. Colors are consistent across the bookmarks of a host.
+func placeholder(host, title string) (string, string) {
+	var (
+		source = host
+		hash   uint32
+	)
+
+	if source == "" {
+		source = title
+	}
+
+	for _, r := range source {
+		hash = hash*31 + uint32(r)
+	}
+
+	initial := "?"
+	if r, _ := utf8.DecodeRuneInString(source); r != utf8.RuneError && !unicode.IsSpace(r) {
+		initial = string(unicode.ToUpper(r))
+	}
+
+	return initial, placeholderColors[hash%uint32(len(placeholderColors))]
+}
@@ -0,0 +1,141 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/mocks"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+var ptrStr = util.PtrStr
+
+func TestEncoder_Encode(t *testing.T) {
+	var (
+		root    = bookmark.Bookmark{ID: 1, GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder}
+		toolbar = bookmark.Bookmark{ID: 3, Parent: 1, Title: "toolbar", GUID: "toolbar_____", Type: bookmark.TypeFolder}
+		tags    = bookmark.Bookmark{ID: 4, Parent: 1, Title: "tags", GUID: "tags________", Type: bookmark.TypeFolder}
+		golang  = bookmark.Bookmark{ID: 7, Parent: 3, Title: "Go & Tools", Type: bookmark.TypeFolder}
+		github  = bookmark.Bookmark{ID: 8, Parent: 3, Title: "GitHub", URL: ptrStr("https://www.github.com/vaguecoder"),
+			Type: bookmark.TypeBookmark}
+		separator = bookmark.Bookmark{ID: 9, Parent: 3, Type: bookmark.TypeSeparator}
+		goDev     = bookmark.Bookmark{ID: 11, Parent: 7, Title: "<Go>", URL: ptrStr("https://go.dev/"),
+			Type: bookmark.TypeBookmark, Tags: []string{"Golang"}}
+		tagFolder = bookmark.Bookmark{ID: 12, Parent: 4, Title: "golang", Type: bookmark.TypeFolder}
+		script    = bookmark.Bookmark{ID: 13, Parent: 3, Title: "Script", URL: ptrStr("javascript:alert(1)"),
+			Type: bookmark.TypeBookmark}
+
+		// Denormalized records, i.e., with folder paths, and without the folders they're in
+		denormalized = func() []bookmark.Bookmark {
+			github, goDev := github, goDev
+			github.Folder, goDev.Folder = "toolbar", "toolbar/Go & Tools"
+
+			return []bookmark.Bookmark{github, goDev}
+		}
+	)
+
+	tests := []struct {
+		name        string
+		bookmarks   []bookmark.Bookmark
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:      "Valid-Case",
+			bookmarks: []bookmark.Bookmark{root, toolbar, tags, golang, github, separator, goDev, tagFolder},
+			contains: []string{
+				`<span id="status" data-total="2">2 bookmarks</span>`,
+				`<li><details class="folder" open><summary>Bookmarks Toolbar<span class="count">2</span></summary>`,
+				`<li class="bookmark" data-search="github https://www.github.com/vaguecoder">` +
+					`<span class="favicon" style="background-color: #b5007f" aria-hidden="true">G</span>` +
+					`<a href="https://www.github.com/vaguecoder">GitHub</a> <span class="host">github.com</span></li>`,
+				`<li class="separator"><hr></li>`,
+				`<li><details class="folder"><summary>Go &amp; Tools<span class="count">1</span></summary>`,
+				`<a href="https://go.dev/">&lt;Go&gt;</a> <span class="host">go.dev</span> <span class="tag">Golang</span></li>`,
+				`data-search="&lt;go&gt; https://go.dev/ golang"`,
+			},
+			notContains: []string{
+				`<summary>tags`,
+				`<summary>golang`,
+				`src="http`,
+			},
+		},
+		{
+			name:      "Denormalized-Bookmarks",
+			bookmarks: denormalized(),
+			contains: []string{
+				`<span id="status" data-total="2">2 bookmarks</span>`,
+				`<li><details class="folder" open><summary>Bookmarks Toolbar<span class="count">2</span></summary>`,
+				`<a href="https://www.github.com/vaguecoder">GitHub</a> <span class="host">github.com</span></li>`,
+				`<li><details class="folder"><summary>Go &amp; Tools<span class="count">1</span></summary>`,
+				`<a href="https://go.dev/">&lt;Go&gt;</a> <span class="host">go.dev</span> <span class="tag">Golang</span></li>`,
+			},
+		},
+		{
+			name:      "Unsafe-URL",
+			bookmarks: []bookmark.Bookmark{root, toolbar, script},
+			contains: []string{
+				`<a href="#ZgotmplZ">Script</a>`,
+			},
+			notContains: []string{
+				`href="javascript:`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+
+			err := NewEncoder(&buffer).Encode(tt.bookmarks)
+			assert.NoError(t, err, "Unexpected error from encode")
+
+			for _, want := range tt.contains {
+				assert.Contains(t, buffer.String(), want, "Missing fragment in page")
+			}
+
+			for _, unwanted := range tt.notContains {
+				assert.NotContains(t, buffer.String(), unwanted, "Unexpected fragment in page")
+			}
+		})
+	}
+}
+
+func TestEncoder_Encode_WriteFailure(t *testing.T) {
+	nonFileWriter := new(mocks.NonFileWriter)
+	nonFileWriter.On("Write", mock.Anything).Return(0, fmt.Errorf("some error")).Once()
+
+	err := NewEncoder(nonFileWriter).Encode([]bookmark.Bookmark{})
+	assert.Error(t, err, "Expected error from encode")
+
+	nonFileWriter.AssertExpectations(t)
+}
+
+func TestPlaceholder(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        string
+		title       string
+		wantInitial string
+	}{
+		{name: "Host", host: "go.dev", title: "The Go Programming Language", wantInitial: "G"},
+		{name: "Title-Without-Host", host: "", title: "ünicode", wantInitial: "Ü"},
+		{name: "Blank", host: "", title: "", wantInitial: "?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initial, color := placeholder(tt.host, tt.title)
+
+			assert.Equal(t, tt.wantInitial, initial, "Mismatch of placeholder initial")
+			assert.Contains(t, placeholderColors, color, "Unexpected placeholder color")
+		})
+	}
+
+	// Colors are consistent across the bookmarks of a host
+	_, first := placeholder("go.dev", "Go")
+	_, second := placeholder("go.dev", "Packages")
+	assert.Equal(t, first, second, "Mismatch of placeholder colors of the same host")
+}
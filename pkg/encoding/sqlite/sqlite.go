@@ -0,0 +1,276 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	// SQLite driver, registered as sqlite3
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+)
+
+const (
+	sqliteDriverName = `sqlite3`
+
+	// schemaVersion is the version of the schema below, bumped on incompatible changes
+	schemaVersion = `1`
+
+	// timestampLayout is the layout of timestamps, in UTC, as parsed by SQLite's and DuckDB's date functions
+	timestampLayout = `2006-01-02 15:04:05`
+
+	// schemaStr creates the tables and indexes. Types are limited to INTEGER, TEXT and TIMESTAMP,
+	// so DuckDB's SQLite scanner maps them without casts.
+	schemaStr = `
+CREATE TABLE metadata (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE folders (
+	id            INTEGER PRIMARY KEY,
+	parent_id     INTEGER REFERENCES folders (id),
+	guid          TEXT,
+	title         TEXT NOT NULL,
+	path          TEXT NOT NULL,
+	position      INTEGER NOT NULL,
+	date_added    TIMESTAMP,
+	last_modified TIMESTAMP
+);
+CREATE TABLE bookmarks (
+	id            INTEGER PRIMARY KEY,
+	folder_id     INTEGER REFERENCES folders (id),
+	guid          TEXT,
+	title         TEXT NOT NULL,
+	url           TEXT NOT NULL,
+	keyword       TEXT,
+	position      INTEGER NOT NULL,
+	date_added    TIMESTAMP,
+	last_modified TIMESTAMP
+);
+CREATE TABLE tags (
+	bookmark_id INTEGER NOT NULL REFERENCES bookmarks (id),
+	tag         TEXT NOT NULL,
+	PRIMARY KEY (bookmark_id, tag)
+);
+CREATE INDEX folders_parent_id ON folders (parent_id);
+CREATE INDEX folders_path ON folders (path);
+CREATE INDEX bookmarks_folder_id ON bookmarks (folder_id);
+CREATE INDEX bookmarks_url ON bookmarks (url);
+CREATE INDEX tags_tag ON tags (tag);
+`
+
+	insertMetadataStr = `INSERT INTO metadata (key, value) VALUES (?, ?)`
+	insertFolderStr   = `INSERT INTO folders (id, parent_id, guid, title, path, position, date_added, last_modified)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	insertBookmarkStr = `INSERT INTO bookmarks (id, folder_id, guid, title, url, keyword, position, date_added, last_modified)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	insertTagStr = `INSERT OR IGNORE INTO tags (bookmark_id, tag) VALUES (?, ?)`
+)
+
+// EncoderName is name of the encoder in current package, i.e., SQLite export.
+// SQLiteExportFormat constant is parsed as EncoderName type here.
+var EncoderName = encoding.ToEncoder(constants.SQLiteExportFormat)
+
+func init() {
+	// Register the encoder name in pkg/encoding.AllEncoders, and as written only to files
+	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+	encoding.FileEncoders = append(encoding.FileEncoders, EncoderName)
+}
+
+// Encoder is the manager for SQLite export encoder.
+// The output is a new SQLite database with normalized folders, bookmarks and tags tables,
+// and a metadata table. Unlike the other encoders, the database is written by its filename
+// rather than to an output stream, so the file has to be empty or missing, and not held open by others.
+type Encoder struct {
+	filename   string
+	profile    string
+	exportedAt time.Time
+}
+
+// NewEncoder initializes new Encoder, writing the database at the filename
+func NewEncoder(filename string) *Encoder {
+	return &Encoder{
+		filename: filename,
+	}
+}
+
+// SetMetadata sets the source profile and the time of export, written in metadata table
+func (e *Encoder) SetMetadata(profile string, exportedAt time.Time) {
+	e.profile = profile
+	e.exportedAt = exportedAt
+}
+
+// Encode writes the input bookmarks into a new SQLite database at the filename of output stream.
+// The folder hierarchy is rebuilt from ID and Parent fields of bookmarks, or from the folder paths
+// when the bookmarks are denormalized.
+func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
+	if e.filename == "" {
+		return fmt.Errorf("SQLite export needs an output file")
+	}
+
+	conn, err := sql.Open(sqliteDriverName, e.filename)
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite DB %q: %v", e.filename, err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+
+	if err = e.write(tx, newExport(bookmarks)); err != nil {
+		// Nothing is written when writing failed midway, not even the schema
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// String returns the encoder name derived in EncoderName.
+// This returns the same value as EncoderName, but using the receiver.
+func (e *Encoder) String() string {
+	return EncoderName.String()
+}
+
+// Filename returns the filename of the database
+func (e *Encoder) Filename() string {
+	return e.filename
+}
+
+// write creates the schema, and inserts the metadata and the rows of export in the transaction
+func (e *Encoder) write(tx *sql.Tx, ex *export) error {
+	if _, err := tx.Exec(schemaStr); err != nil {
+		return fmt.Errorf("failed to create schema: %v", err)
+	}
+
+	metadata := [][2]string{
+		{"schema_version", schemaVersion},
+		{"source_profile", e.profile},
+		{"exported_at", e.exportedAt.UTC().Format(time.RFC3339)},
+		{"tool_version", toolVersion()},
+	}
+
+	for _, row := range metadata {
+		if _, err := tx.Exec(insertMetadataStr, row[0], row[1]); err != nil {
+			return fmt.Errorf("failed to insert metadata %q: %v", row[0], err)
+		}
+	}
+
+	for _, f := range ex.folders {
+		_, err := tx.Exec(insertFolderStr, f.ID, nullInt(f.parentID), nullStr(f.GUID), f.Title, f.path,
+			f.Position, timestamp(f.DateAdded), timestamp(f.LastModified))
+		if err != nil {
+			return fmt.Errorf("failed to insert folder %d: %v", f.ID, err)
+		}
+	}
+
+	for _, b := range ex.bookmarks {
+		_, err := tx.Exec(insertBookmarkStr, b.ID, nullInt(b.folderID), nullStr(b.GUID), b.Title, url(b.Bookmark),
+			nullStr(b.Keyword), b.Position, timestamp(b.DateAdded), timestamp(b.LastModified))
+		if err != nil {
+			return fmt.Errorf("failed to insert bookmark %d: %v", b.ID, err)
+		}
+
+		for _, tag := range b.Tags {
+			if _, err = tx.Exec(insertTagStr, b.ID, tag); err != nil {
+				return fmt.Errorf("failed to insert tag %q of bookmark %d: %v", tag, b.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// folderRow is a folder along with its parent folder and path, e.g., toolbar/GitHub
+type folderRow struct {
+	bookmark.Bookmark
+	parentID int // Zero for the top-level folders
+	path     string
+}
+
+// bookmarkRow is a bookmark along with its folder
+type bookmarkRow struct {
+	bookmark.Bookmark
+	folderID int // Zero in absence of folder
+}
+
+// export holds the rows of the tables, rebuilt from the bookmarks
+type export struct {
+	folders   []folderRow
+	bookmarks []bookmarkRow
+}
+
+// newExport splits the bookmarks into folders and bookmarks, parents ahead of their children.
+// The folders are of the tree as in the tree formats, i.e., the places root and the tags root
+// aren't exported, as the tags are in tags table, and the folders of denormalized bookmarks are
+// synthesised from their paths. Separators aren't exported either.
+func newExport(bookmarks []bookmark.Bookmark) *export {
+	ex := &export{}
+	ex.walk(encoding.NewTree(bookmarks), 0, "")
+
+	return ex
+}
+
+// walk adds the nodes in the folder and their children recursively
+func (ex *export) walk(nodes []encoding.TreeNode, parentID int, parentPath string) {
+	for _, node := range nodes {
+		switch {
+		case node.Type == bookmark.TypeSeparator:
+			// Separators aren't exported
+		case node.Children != nil:
+			path := bookmark.JoinPath(parentPath, node.Title)
+
+			ex.folders = append(ex.folders, folderRow{Bookmark: node.Bookmark, parentID: parentID, path: path})
+			ex.walk(*node.Children, node.ID, path)
+		default:
+			ex.bookmarks = append(ex.bookmarks, bookmarkRow{Bookmark: node.Bookmark, folderID: parentID})
+		}
+	}
+}
+
+// url returns the URL of the bookmark, or blank if missing
+func url(b bookmark.Bookmark) string {
+	if b.URL == nil {
+		return ""
+	}
+
+	return *b.URL
+}
+
+// timestamp returns the timestamp in UTC, or NULL if missing
+func timestamp(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: t.UTC().Format(timestampLayout), Valid: true}
+}
+
+// nullInt returns the ID, or NULL if zero
+func nullInt(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+// nullStr returns the string, or NULL if blank
+func nullStr(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// toolVersion returns the version of the main module as built by go install, or (devel) for local builds
+func toolVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	return "(devel)"
+}
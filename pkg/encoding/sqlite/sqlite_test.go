@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+// Queries of the exported rows, with the timestamps as stored, rather than parsed by the driver
+const (
+	foldersQueryStr = `SELECT id, parent_id, guid, title, path, position,
+	CAST(date_added AS TEXT), CAST(last_modified AS TEXT) FROM folders ORDER BY id`
+	bookmarksQueryStr = `SELECT id, folder_id, guid, title, url, keyword, position,
+	CAST(date_added AS TEXT), CAST(last_modified AS TEXT) FROM bookmarks ORDER BY id`
+)
+
+var ptrStr = util.PtrStr
+
+// queryRows reads the rows of the query, with the columns of each row joined by |
+func queryRows(t *testing.T, filename, query string) []string {
+	conn, err := sql.Open(sqliteDriverName, filename)
+	require.NoError(t, err, "Failed to open export")
+	defer conn.Close()
+
+	rows, err := conn.Query(query)
+	require.NoError(t, err, "Failed to query export with %q", query)
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	require.NoError(t, err, "Failed to read columns")
+
+	var result []string
+	for rows.Next() {
+		var (
+			values = make([]sql.NullString, len(columns))
+			ptrs   = make([]any, len(columns))
+			fields = make([]string, len(columns))
+		)
+
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+
+		require.NoError(t, rows.Scan(ptrs...), "Failed to scan export")
+
+		for i, value := range values {
+			fields[i] = value.String
+			if !value.Valid {
+				fields[i] = "NULL"
+			}
+		}
+
+		result = append(result, strings.Join(fields, "|"))
+	}
+
+	return result
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	var (
+		added = time.Date(2023, time.March, 4, 5, 6, 7, 0, time.UTC)
+
+		root    = bookmark.Bookmark{ID: 1, GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder}
+		toolbar = bookmark.Bookmark{ID: 3, Parent: 1, Title: "toolbar", GUID: "toolbar_____", Type: bookmark.TypeFolder}
+		tags    = bookmark.Bookmark{ID: 4, Parent: 1, Title: "tags", GUID: "tags________", Type: bookmark.TypeFolder}
+		github  = bookmark.Bookmark{ID: 7, Parent: 3, Title: "GitHub", Type: bookmark.TypeFolder, Position: 1}
+		goDev   = bookmark.Bookmark{ID: 8, Parent: 3, Title: "Go", URL: ptrStr("https://go.dev/"), Keyword: "go",
+			Type: bookmark.TypeBookmark, Tags: []string{"golang", "docs"}, DateAdded: added}
+		separator = bookmark.Bookmark{ID: 9, Parent: 3, Type: bookmark.TypeSeparator, Position: 2}
+		coder     = bookmark.Bookmark{ID: 10, Parent: 7, Title: "Vague Coder", URL: ptrStr("https://github.com/vaguecoder"),
+			Type: bookmark.TypeBookmark}
+		tagFolder = bookmark.Bookmark{ID: 11, Parent: 4, Title: "golang", Type: bookmark.TypeFolder}
+	)
+
+	tests := []struct {
+		name          string
+		bookmarks     []bookmark.Bookmark
+		wantFolders   []string
+		wantBookmarks []string
+		wantTags      []string
+	}{
+		{
+			name:      "Valid-Case",
+			bookmarks: []bookmark.Bookmark{root, toolbar, tags, github, goDev, separator, coder, tagFolder},
+			wantFolders: []string{
+				"3|NULL|toolbar_____|toolbar|toolbar|0|NULL|NULL",
+				"7|3|NULL|GitHub|toolbar/GitHub|1|NULL|NULL",
+			},
+			wantBookmarks: []string{
+				"8|3|NULL|Go|https://go.dev/|go|0|2023-03-04 05:06:07|NULL",
+				"10|7|NULL|Vague Coder|https://github.com/vaguecoder|NULL|0|NULL|NULL",
+			},
+			wantTags: []string{"8|docs", "8|golang"},
+		},
+		{
+			name: "Denormalized-Bookmarks",
+			bookmarks: []bookmark.Bookmark{
+				{ID: 8, Parent: 3, Folder: "toolbar", Title: "Go", URL: ptrStr("https://go.dev/"), Type: bookmark.TypeBookmark},
+				{ID: 10, Parent: 7, Folder: "toolbar/GitHub", Title: "Vague Coder", URL: ptrStr("https://github.com/vaguecoder"),
+					Type: bookmark.TypeBookmark},
+			},
+			wantFolders: []string{
+				"11|NULL|NULL|toolbar|toolbar|0|NULL|NULL",
+				"12|11|NULL|GitHub|toolbar/GitHub|1|NULL|NULL", // After Go in toolbar
+			},
+			wantBookmarks: []string{
+				"8|11|NULL|Go|https://go.dev/|NULL|0|NULL|NULL",
+				"10|12|NULL|Vague Coder|https://github.com/vaguecoder|NULL|0|NULL|NULL",
+			},
+			wantTags: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "bookmarks.db")
+
+			// The output file is created empty ahead, and closed, as by the file operator
+			require.NoError(t, os.WriteFile(filename, nil, 0600), "Failed to create output file")
+
+			encoder := NewEncoder(filename)
+			encoder.SetMetadata("default-release", added)
+
+			err := encoder.Encode(tt.bookmarks)
+			require.NoError(t, err, "Unexpected error from encode")
+
+			assert.Equal(t, filename, encoder.Filename(), "Mismatch of filename")
+			assert.Equal(t, tt.wantFolders, queryRows(t, filename, foldersQueryStr), "Mismatch of folders")
+			assert.Equal(t, tt.wantBookmarks, queryRows(t, filename, bookmarksQueryStr), "Mismatch of bookmarks")
+			assert.Equal(t, tt.wantTags, queryRows(t, filename, "SELECT * FROM tags ORDER BY bookmark_id, tag"),
+				"Mismatch of tags")
+			assert.Subset(t, queryRows(t, filename, "SELECT * FROM metadata"),
+				[]string{"schema_version|1", "source_profile|default-release", "exported_at|2023-03-04T05:06:07Z"},
+				"Mismatch of metadata")
+		})
+	}
+}
+
+func TestEncoder_Encode_NoFilename(t *testing.T) {
+	err := NewEncoder("").Encode([]bookmark.Bookmark{})
+	assert.Error(t, err, "Expected error from encode without filename")
+}
@@ -1,6 +1,7 @@
 package tabular
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"strings"
@@ -10,10 +11,18 @@ import (
 	"github.com/vaguecoder/firefox-backups/pkg/constants"
 	"github.com/vaguecoder/firefox-backups/pkg/encoding"
 	"github.com/vaguecoder/firefox-backups/pkg/files"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
 )
 
-// fixedTabWidth is the tab width in table
-const fixedTabWidth = 8
+const (
+	// fixedTabWidth is the tab width in table
+	fixedTabWidth = 8
+
+	// maxLineSize is the maximum length of a line read from table,
+	// as the columns are padded to the longest cell
+	maxLineSize = 16 * 1024 * 1024
+)
 
 // EncoderName is name of the encoder in current package, i.e., Table.
 // CSVFormat constant is parsed as EncoderName type here.
@@ -22,6 +31,9 @@ var EncoderName = encoding.ToEncoder(constants.TabularFormat)
 func init() {
 	// Register the encoder name in pkg/encoding.AllEncoders
 	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+
+	// Register the decoder name in pkg/encoding.AllDecoders
+	encoding.AllDecoders = append(encoding.AllDecoders, EncoderName)
 }
 
 // Encoder is the manager for table encoder
@@ -51,12 +63,25 @@ func NewEncoder(out io.Writer, header bool) *Encoder {
 
 // Encode encodes the input bookmarks in tabular format to already set output stream
 func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
+	return e.writeTable(bookmark.BookmarksTable(bookmarks, e.enableHeader))
+}
+
+// EncodeVisits encodes the input history visits in tabular format to already set output stream
+func (e *Encoder) EncodeVisits(visits []history.Visit) error {
+	return e.writeTable(history.VisitsTable(visits, e.enableHeader))
+}
+
+// EncodeLinks encodes the input links of bookmarks in tabular format to already set output stream
+func (e *Encoder) EncodeLinks(checkedLinks []links.Link) error {
+	return e.writeTable(links.LinksTable(checkedLinks, e.enableHeader))
+}
+
+// writeTable writes the records as tab-aligned table to already set output stream
+func (e *Encoder) writeTable(records [][]string) error {
 	var (
 		err       error
 		recordStr string
 		record    []string
-
-		records = bookmark.BookmarksTable(bookmarks, e.enableHeader)
 	)
 
 	for _, record = range records {
@@ -86,3 +111,132 @@ func (e *Encoder) String() string {
 func (e *Encoder) Filename() string {
 	return e.filename
 }
+
+// Decoder is the manager for table decoder
+type Decoder struct {
+	in io.Reader
+}
+
+// NewDecoder initializes new Decoder
+func NewDecoder(in io.Reader) *Decoder {
+	return &Decoder{
+		in: in,
+	}
+}
+
+// Decode decodes the bookmarks in tabular format from already set input stream.
+// The table must have the header, as the column boundaries are taken from it.
+func (d *Decoder) Decode() ([]bookmark.Bookmark, error) {
+	var (
+		err     error
+		offsets []int
+		records [][]string
+		line    []rune
+
+		scanner = bufio.NewScanner(d.in)
+	)
+
+	scanner.Buffer(nil, maxLineSize)
+
+	for scanner.Scan() {
+		line = []rune(scanner.Text())
+		if strings.TrimSpace(string(line)) == "" {
+			// Skip the blank lines
+			continue
+		}
+
+		if offsets == nil {
+			// First line is the header
+			if offsets, err = headerOffsets(line); err != nil {
+				return nil, err
+			}
+		}
+
+		records = append(records, splitRow(expandIndent(line, offsets), offsets))
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read table: %v", err)
+	}
+
+	bookmarks, err := bookmark.FromTable(records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal table: %v", err)
+	}
+
+	return bookmarks, nil
+}
+
+// String returns the decoder name, same as EncoderName
+func (d *Decoder) String() string {
+	return EncoderName.String()
+}
+
+// headerOffsets returns the offsets (in runes) where the columns start, from the header line
+func headerOffsets(header []rune) ([]int, error) {
+	known := map[string]bool{}
+	for _, title := range bookmark.TableHeader {
+		known[title] = true
+	}
+
+	var offsets []int
+
+	for _, field := range strings.Fields(string(header)) {
+		if !known[field] {
+			// When the first line isn't the header
+			return nil, fmt.Errorf("failed to decode table: header is missing, %q isn't a column title", field)
+		}
+	}
+
+	for i, r := range header {
+		if r != ' ' && r != '\t' && (i == 0 || header[i-1] == ' ') {
+			// Column starts at non-blank character after padding
+			offsets = append(offsets, i)
+		}
+	}
+
+	return offsets, nil
+}
+
+// expandIndent replaces the leading tabs with spaces up to the column they indent to.
+// The tab writer pads the leading empty cells with tabs instead of spaces,
+// taking as many tabs as needed to cover the column width.
+func expandIndent(line []rune, offsets []int) []rune {
+	var tabs, column int
+
+	for tabs < len(line) && line[tabs] == '\t' {
+		tabs++
+	}
+
+	if tabs == 0 {
+		return line
+	}
+
+	for remaining := tabs; remaining > 0 && column < len(offsets)-1; column++ {
+		width := offsets[column+1] - offsets[column]
+		remaining -= (width + fixedTabWidth - 1) / fixedTabWidth
+	}
+
+	return append([]rune(strings.Repeat(" ", offsets[column])), line[tabs:]...)
+}
+
+// splitRow splits the line into cells at the column offsets
+func splitRow(line []rune, offsets []int) []string {
+	row := make([]string, 0, len(offsets))
+
+	for i, start := range offsets {
+		end := len(line)
+		if i+1 < len(offsets) && offsets[i+1] < end {
+			end = offsets[i+1]
+		}
+
+		if start > end {
+			// When the line ends before the column
+			start = end
+		}
+
+		row = append(row, strings.TrimSpace(string(line[start:end])))
+	}
+
+	return row
+}
@@ -1,9 +1,12 @@
 package tabular
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -99,6 +102,16 @@ func TestEncoder_Encode(t *testing.T) {
 					`1`,
 					tabWidthWhitespace,
 					`0`,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					tabWidthWhitespace,
+					`0`,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -115,6 +128,16 @@ func TestEncoder_Encode(t *testing.T) {
 					`2`,
 					tabWidthWhitespace,
 					`0`,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					tabWidthWhitespace,
+					`0`,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
 					tabWidthWhitespace, // Closing tab added.
 				},
 			},
@@ -155,6 +178,16 @@ func TestEncoder_Encode(t *testing.T) {
 					`1`,
 					tabWidthWhitespace,
 					`0`,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					tabWidthWhitespace,
+					`0`,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -171,6 +204,16 @@ func TestEncoder_Encode(t *testing.T) {
 					`2`,
 					tabWidthWhitespace,
 					`0`,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					tabWidthWhitespace,
+					`0`,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					tabWidthWhitespace,
 					tabWidthWhitespace, // Closing tab added.
 				},
 			},
@@ -267,6 +310,22 @@ func TestEncoder_Encode(t *testing.T) {
 					`ID`,
 					tabWidthWhitespace,
 					`PARENT`,
+					tabWidthWhitespace,
+					`GUID`,
+					tabWidthWhitespace,
+					`PLACE-GUID`,
+					tabWidthWhitespace,
+					`TYPE`,
+					tabWidthWhitespace,
+					`POSITION`,
+					tabWidthWhitespace,
+					`KEYWORD`,
+					tabWidthWhitespace,
+					`DATE-ADDED`,
+					tabWidthWhitespace,
+					`LAST-MODIFIED`,
+					tabWidthWhitespace,
+					`TAGS`,
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -291,6 +350,22 @@ func TestEncoder_Encode(t *testing.T) {
 					`--`,
 					tabWidthWhitespace,
 					`------`,
+					tabWidthWhitespace,
+					`----`,
+					tabWidthWhitespace,
+					`----------`,
+					tabWidthWhitespace,
+					`----`,
+					tabWidthWhitespace,
+					`--------`,
+					tabWidthWhitespace,
+					`-------`,
+					tabWidthWhitespace,
+					`----------`,
+					tabWidthWhitespace,
+					`-------------`,
+					tabWidthWhitespace,
+					`----`,
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -307,6 +382,38 @@ func TestEncoder_Encode(t *testing.T) {
 					`0`,
 					// Width difference of max tab width and previous cell (6 - 1 = 5) is padded to maintain the table format
 					whitespace(uint(len(`PARENT`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`GUID`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (4 - 1 = 3) is padded to maintain the table format
+					whitespace(uint(len(`TYPE`) - len(`0`))),
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (8 - 1 = 7) is padded to maintain the table format
+					whitespace(uint(len(`POSITION`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (7) to maintain the table format
+					whitespace(uint(len(`KEYWORD`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (13) to maintain the table format
+					// Since the padding is higher than the tab width (13 > 8), it is split into 5 + 8.
+					whitespace(5),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`TAGS`))),
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -327,6 +434,38 @@ func TestEncoder_Encode(t *testing.T) {
 					`0`,
 					// Width difference of max tab width and previous cell (6 - 1 = 5) is padded to maintain the table format
 					whitespace(uint(len(`PARENT`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`GUID`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (4 - 1 = 3) is padded to maintain the table format
+					whitespace(uint(len(`TYPE`) - len(`0`))),
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (8 - 1 = 7) is padded to maintain the table format
+					whitespace(uint(len(`POSITION`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (7) to maintain the table format
+					whitespace(uint(len(`KEYWORD`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (13) to maintain the table format
+					// Since the padding is higher than the tab width (13 > 8), it is split into 5 + 8.
+					whitespace(5),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`TAGS`))),
 					tabWidthWhitespace, // Closing tab added.
 				},
 			},
@@ -379,6 +518,22 @@ func TestEncoder_Encode(t *testing.T) {
 					`ID`,
 					tabWidthWhitespace,
 					`PARENT`,
+					tabWidthWhitespace,
+					`GUID`,
+					tabWidthWhitespace,
+					`PLACE-GUID`,
+					tabWidthWhitespace,
+					`TYPE`,
+					tabWidthWhitespace,
+					`POSITION`,
+					tabWidthWhitespace,
+					`KEYWORD`,
+					tabWidthWhitespace,
+					`DATE-ADDED`,
+					tabWidthWhitespace,
+					`LAST-MODIFIED`,
+					tabWidthWhitespace,
+					`TAGS`,
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -403,6 +558,22 @@ func TestEncoder_Encode(t *testing.T) {
 					`--`,
 					tabWidthWhitespace,
 					`------`,
+					tabWidthWhitespace,
+					`----`,
+					tabWidthWhitespace,
+					`----------`,
+					tabWidthWhitespace,
+					`----`,
+					tabWidthWhitespace,
+					`--------`,
+					tabWidthWhitespace,
+					`-------`,
+					tabWidthWhitespace,
+					`----------`,
+					tabWidthWhitespace,
+					`-------------`,
+					tabWidthWhitespace,
+					`----`,
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -419,6 +590,38 @@ func TestEncoder_Encode(t *testing.T) {
 					`0`,
 					// Width difference of max tab width and previous cell (6 - 1 = 5) is padded to maintain the table format
 					whitespace(uint(len(`PARENT`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`GUID`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (4 - 1 = 3) is padded to maintain the table format
+					whitespace(uint(len(`TYPE`) - len(`0`))),
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (8 - 1 = 7) is padded to maintain the table format
+					whitespace(uint(len(`POSITION`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (7) to maintain the table format
+					whitespace(uint(len(`KEYWORD`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (13) to maintain the table format
+					// Since the padding is higher than the tab width (13 > 8), it is split into 5 + 8.
+					whitespace(5),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`TAGS`))),
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -439,6 +642,38 @@ func TestEncoder_Encode(t *testing.T) {
 					`0`,
 					// Width difference of max tab width and previous cell (6 - 1 = 5) is padded to maintain the table format
 					whitespace(uint(len(`PARENT`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`GUID`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (4 - 1 = 3) is padded to maintain the table format
+					whitespace(uint(len(`TYPE`) - len(`0`))),
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (8 - 1 = 7) is padded to maintain the table format
+					whitespace(uint(len(`POSITION`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (7) to maintain the table format
+					whitespace(uint(len(`KEYWORD`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (13) to maintain the table format
+					// Since the padding is higher than the tab width (13 > 8), it is split into 5 + 8.
+					whitespace(5),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`TAGS`))),
 					tabWidthWhitespace, // Closing tab added.
 				},
 			},
@@ -530,6 +765,22 @@ func TestEncoder_Encode(t *testing.T) {
 					`ID`,
 					tabWidthWhitespace,
 					`PARENT`,
+					tabWidthWhitespace,
+					`GUID`,
+					tabWidthWhitespace,
+					`PLACE-GUID`,
+					tabWidthWhitespace,
+					`TYPE`,
+					tabWidthWhitespace,
+					`POSITION`,
+					tabWidthWhitespace,
+					`KEYWORD`,
+					tabWidthWhitespace,
+					`DATE-ADDED`,
+					tabWidthWhitespace,
+					`LAST-MODIFIED`,
+					tabWidthWhitespace,
+					`TAGS`,
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -554,6 +805,22 @@ func TestEncoder_Encode(t *testing.T) {
 					`--`,
 					tabWidthWhitespace,
 					`------`,
+					tabWidthWhitespace,
+					`----`,
+					tabWidthWhitespace,
+					`----------`,
+					tabWidthWhitespace,
+					`----`,
+					tabWidthWhitespace,
+					`--------`,
+					tabWidthWhitespace,
+					`-------`,
+					tabWidthWhitespace,
+					`----------`,
+					tabWidthWhitespace,
+					`-------------`,
+					tabWidthWhitespace,
+					`----`,
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -570,6 +837,38 @@ func TestEncoder_Encode(t *testing.T) {
 					`0`,
 					// Width difference of max tab width and previous cell (6 - 1 = 5) is padded to maintain the table format
 					whitespace(uint(len(`PARENT`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`GUID`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (4 - 1 = 3) is padded to maintain the table format
+					whitespace(uint(len(`TYPE`) - len(`0`))),
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (8 - 1 = 7) is padded to maintain the table format
+					whitespace(uint(len(`POSITION`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (7) to maintain the table format
+					whitespace(uint(len(`KEYWORD`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (13) to maintain the table format
+					// Since the padding is higher than the tab width (13 > 8), it is split into 5 + 8.
+					whitespace(5),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`TAGS`))),
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -590,6 +889,38 @@ func TestEncoder_Encode(t *testing.T) {
 					`0`,
 					// Width difference of max tab width and previous cell (6 - 1 = 5) is padded to maintain the table format
 					whitespace(uint(len(`PARENT`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`GUID`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (4 - 1 = 3) is padded to maintain the table format
+					whitespace(uint(len(`TYPE`) - len(`0`))),
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (8 - 1 = 7) is padded to maintain the table format
+					whitespace(uint(len(`POSITION`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (7) to maintain the table format
+					whitespace(uint(len(`KEYWORD`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (13) to maintain the table format
+					// Since the padding is higher than the tab width (13 > 8), it is split into 5 + 8.
+					whitespace(5),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`TAGS`))),
 					tabWidthWhitespace, // Closing tab added.
 				},
 			},
@@ -647,6 +978,22 @@ func TestEncoder_Encode(t *testing.T) {
 					`ID`,
 					tabWidthWhitespace,
 					`PARENT`,
+					tabWidthWhitespace,
+					`GUID`,
+					tabWidthWhitespace,
+					`PLACE-GUID`,
+					tabWidthWhitespace,
+					`TYPE`,
+					tabWidthWhitespace,
+					`POSITION`,
+					tabWidthWhitespace,
+					`KEYWORD`,
+					tabWidthWhitespace,
+					`DATE-ADDED`,
+					tabWidthWhitespace,
+					`LAST-MODIFIED`,
+					tabWidthWhitespace,
+					`TAGS`,
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -671,6 +1018,22 @@ func TestEncoder_Encode(t *testing.T) {
 					`--`,
 					tabWidthWhitespace,
 					`------`,
+					tabWidthWhitespace,
+					`----`,
+					tabWidthWhitespace,
+					`----------`,
+					tabWidthWhitespace,
+					`----`,
+					tabWidthWhitespace,
+					`--------`,
+					tabWidthWhitespace,
+					`-------`,
+					tabWidthWhitespace,
+					`----------`,
+					tabWidthWhitespace,
+					`-------------`,
+					tabWidthWhitespace,
+					`----`,
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -687,6 +1050,38 @@ func TestEncoder_Encode(t *testing.T) {
 					`0`,
 					// Width difference of max tab width and previous cell (6 - 1 = 5) is padded to maintain the table format
 					whitespace(uint(len(`PARENT`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`GUID`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (4 - 1 = 3) is padded to maintain the table format
+					whitespace(uint(len(`TYPE`) - len(`0`))),
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (8 - 1 = 7) is padded to maintain the table format
+					whitespace(uint(len(`POSITION`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (7) to maintain the table format
+					whitespace(uint(len(`KEYWORD`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (13) to maintain the table format
+					// Since the padding is higher than the tab width (13 > 8), it is split into 5 + 8.
+					whitespace(5),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`TAGS`))),
 					tabWidthWhitespace, // Closing tab added.
 				},
 				{
@@ -707,6 +1102,38 @@ func TestEncoder_Encode(t *testing.T) {
 					`0`,
 					// Width difference of max tab width and previous cell (6 - 1 = 5) is padded to maintain the table format
 					whitespace(uint(len(`PARENT`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`GUID`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (4 - 1 = 3) is padded to maintain the table format
+					whitespace(uint(len(`TYPE`) - len(`0`))),
+					tabWidthWhitespace,
+					`0`,
+					// Width difference of max tab width and previous cell (8 - 1 = 7) is padded to maintain the table format
+					whitespace(uint(len(`POSITION`) - len(`0`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (7) to maintain the table format
+					whitespace(uint(len(`KEYWORD`))),
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (10) to maintain the table format
+					// Since the padding is higher than the tab width (10 > 8), it is split into 2 + 8.
+					whitespace(2),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (13) to maintain the table format
+					// Since the padding is higher than the tab width (13 > 8), it is split into 5 + 8.
+					whitespace(5),
+					tabWidthWhitespace,
+					tabWidthWhitespace,
+					// Empty cell is padded with the width of column (4) to maintain the table format
+					whitespace(uint(len(`TAGS`))),
 					tabWidthWhitespace, // Closing tab added.
 				},
 			},
@@ -799,3 +1226,96 @@ func TestEncoder_Encode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecoder_Decode(t *testing.T) {
+	type testData struct {
+		name         string
+		bookmarks    []bookmark.Bookmark
+		enableHeader bool
+	}
+
+	var (
+		err       error
+		buffer    bytes.Buffer
+		decoded   []bookmark.Bookmark
+		testCase  testData
+		timestamp = time.Date(2023, time.March, 4, 5, 6, 7, 891011000, time.UTC)
+
+		bookmarks = []bookmark.Bookmark{
+			{
+				Title:        "Profiles",
+				Folder:       "Bookmarks Toolbar",
+				ID:           7,
+				Parent:       3,
+				GUID:         "folder-guid1",
+				Type:         bookmark.TypeFolder,
+				Position:     1,
+				DateAdded:    timestamp,
+				LastModified: timestamp.Add(time.Hour),
+			},
+			{
+				URL:          ptrStr("https://github.com/vaguecoder?tab=repositories"),
+				Title:        "Vague Coder · GitHub",
+				Folder:       "Bookmarks Toolbar/Profiles",
+				ID:           8,
+				Parent:       7,
+				GUID:         "bookmarkguid",
+				PlaceGUID:    "placeguid123",
+				Type:         bookmark.TypeBookmark,
+				Position:     0,
+				Keyword:      "gh",
+				DateAdded:    timestamp,
+				LastModified: timestamp,
+				Tags:         []string{"code", "profile"},
+			},
+			{
+				Folder:   "Bookmarks Toolbar/Profiles",
+				ID:       9,
+				Parent:   7,
+				GUID:     "separatorgd",
+				Type:     bookmark.TypeSeparator,
+				Position: 1,
+			},
+		}
+	)
+
+	tests := []testData{
+		{
+			name:         "Round-Trip",
+			bookmarks:    bookmarks,
+			enableHeader: true,
+		},
+		{
+			name:         "Round-Trip-Leading-Empty-Cells",
+			bookmarks:    append([]bookmark.Bookmark{{ID: 1, Type: bookmark.TypeFolder}}, bookmarks...),
+			enableHeader: true,
+		},
+		{
+			name:         "No-Bookmarks",
+			bookmarks:    nil,
+			enableHeader: true,
+		},
+	}
+
+	for _, testCase = range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			buffer.Reset()
+
+			err = NewEncoder(&buffer, testCase.enableHeader).Encode(testCase.bookmarks)
+			assert.NoErrorf(t, err, "Failed to encode bookmarks: %v", err)
+
+			decoded, err = NewDecoder(&buffer).Decode()
+			assert.NoErrorf(t, err, "Failed to decode bookmarks: %v", err)
+
+			assert.Equalf(t, testCase.bookmarks, decoded, "Mismatch in decoded bookmarks")
+		})
+	}
+}
+
+func TestDecoder_Decode_MissingHeader(t *testing.T) {
+	input := strings.NewReader("https://github.com/vaguecoder\tVague Coder\t\n")
+
+	bookmarks, err := NewDecoder(input).Decode()
+	assert.Errorf(t, err, "Expected error for table without header, found nil")
+	assert.Nilf(t, bookmarks, "Expected no bookmarks, found %v", bookmarks)
+}
@@ -0,0 +1,141 @@
+package encoding
+
+import (
+	"strings"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+)
+
+// TreeNode is a bookmark record nesting its child records, as in the tree formats.
+// Children are set for folders, and are empty for empty folders, while they're omitted for the rest.
+type TreeNode struct {
+	bookmark.Bookmark `yaml:",inline"`
+	Children          *[]TreeNode `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// NewTree rebuilds the folder hierarchy of bookmarks from ID and Parent fields, as nested nodes.
+// The tree starts from the Firefox's root folders, i.e., menu, toolbar, unfiled and mobile,
+// with the places root omitted and the tags root skipped. The records whose parent isn't
+// in the input are placed in the folders at their denormalized folder paths, and the folders
+// missing in the input are synthesised with new IDs. The records with neither parent nor folder path
+// are along with the root folders. Children are ordered by position, and then by ID.
+func NewTree(bookmarks []bookmark.Bookmark) []TreeNode {
+	return treeNodes(newNester(bookmarks).nest(bookmark.Tree(bookmarks)), true)
+}
+
+// treeNodes converts the nodes and their children recursively.
+// isTopLevel is set when the nodes are the top-level nodes, or direct children of places root.
+func treeNodes(nodes []*bookmark.Node, isTopLevel bool) []TreeNode {
+	result := make([]TreeNode, 0, len(nodes))
+
+	for _, node := range nodes {
+		switch {
+		case node.IsPlacesRoot():
+			// Places root itself isn't written, only its children
+			result = append(result, treeNodes(node.Children, true)...)
+		case node.IsTagsRoot(isTopLevel):
+			// Tag folders are skipped, as the tags are resolved onto bookmarks by tags filter
+		case node.IsFolder():
+			children := treeNodes(node.Children, false)
+			result = append(result, TreeNode{Bookmark: node.Bookmark, Children: &children})
+		default:
+			result = append(result, TreeNode{Bookmark: node.Bookmark})
+		}
+	}
+
+	return result
+}
+
+// nester places the denormalized records in the folders at their paths
+type nester struct {
+	folders map[string]*bookmark.Node // Folders against their paths
+	nextID  int                       // Next ID of the folders missing in the input
+	roots   []*bookmark.Node
+}
+
+// newNester initiates new nester, with IDs of the synthesised folders following the IDs of bookmarks
+func newNester(bookmarks []bookmark.Bookmark) *nester {
+	n := &nester{folders: make(map[string]*bookmark.Node)}
+
+	for _, b := range bookmarks {
+		if b.ID >= n.nextID {
+			n.nextID = b.ID + 1
+		}
+	}
+
+	return n
+}
+
+// nest places the top-level nodes with denormalized folder paths in the folders at the paths,
+// and returns the top-level nodes left, along with the top-level folders synthesised
+func (n *nester) nest(roots []*bookmark.Node) []*bookmark.Node {
+	for _, root := range roots {
+		n.index(root, strings.Trim(root.Folder, bookmark.FolderPathDelimiter))
+	}
+
+	for _, root := range roots {
+		folder := strings.Trim(root.Folder, bookmark.FolderPathDelimiter)
+		if path := bookmark.JoinPath(folder, root.Title); folder == "" || root.IsPlacesRoot() ||
+			folder == path || strings.HasPrefix(folder, path+bookmark.FolderPathDelimiter) {
+			// When the record isn't denormalized, or its folder would be in the record itself
+			n.roots = append(n.roots, root)
+			continue
+		}
+
+		parent := n.folder(folder)
+		parent.Children = append(parent.Children, root)
+	}
+
+	return n.roots
+}
+
+// index maps the folders in the hierarchy of node against their paths, with path being the path it's in
+func (n *nester) index(node *bookmark.Node, path string) {
+	switch {
+	case node.IsPlacesRoot():
+		// Places root has no title, and its children are the top-level folders
+		for _, child := range node.Children {
+			n.index(child, "")
+		}
+	case node.IsFolder():
+		path = bookmark.JoinPath(path, node.Title)
+		if _, ok := n.folders[path]; !ok {
+			n.folders[path] = node
+		}
+
+		for _, child := range node.Children {
+			n.index(child, path)
+		}
+	}
+}
+
+// folder returns the folder at the non-blank path, synthesising the folders missing in the path
+func (n *nester) folder(path string) *bookmark.Node {
+	var (
+		parent     *bookmark.Node
+		parentPath string
+	)
+
+	for _, title := range strings.Split(path, bookmark.FolderPathDelimiter) {
+		path = bookmark.JoinPath(parentPath, title)
+
+		folder, ok := n.folders[path]
+		if !ok {
+			folder = &bookmark.Node{Bookmark: bookmark.Bookmark{ID: n.nextID, Title: title, Folder: parentPath,
+				Type: bookmark.TypeFolder}}
+			n.nextID++
+			n.folders[path] = folder
+
+			if parent == nil {
+				n.roots = append(n.roots, folder)
+			} else {
+				folder.Parent, folder.Position = parent.ID, len(parent.Children)
+				parent.Children = append(parent.Children, folder)
+			}
+		}
+
+		parent, parentPath = folder, path
+	}
+
+	return parent
+}
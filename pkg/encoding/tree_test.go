@@ -0,0 +1,88 @@
+package encoding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+func TestNewTree(t *testing.T) {
+	var (
+		ptrStr = util.PtrStr
+
+		root    = bookmark.Bookmark{ID: 1, GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder}
+		menu    = bookmark.Bookmark{ID: 2, Parent: 1, Title: "menu", Type: bookmark.TypeFolder, Position: 0}
+		toolbar = bookmark.Bookmark{ID: 3, Parent: 1, Title: "toolbar", Type: bookmark.TypeFolder, Position: 1}
+		tags    = bookmark.Bookmark{ID: 4, Parent: 1, Title: "tags", Type: bookmark.TypeFolder, Position: 2}
+		golang  = bookmark.Bookmark{ID: 5, Parent: 4, Title: "golang", Type: bookmark.TypeFolder}
+		work    = bookmark.Bookmark{ID: 6, Parent: 3, Title: "Work", Type: bookmark.TypeFolder, Position: 1}
+		wiki    = bookmark.Bookmark{ID: 7, Parent: 6, Title: "Wiki", URL: ptrStr("https://wiki.example.com/")}
+		goDev   = bookmark.Bookmark{ID: 8, Parent: 3, Title: "Go", URL: ptrStr("https://go.dev/"), Position: 0}
+		orphan  = bookmark.Bookmark{ID: 9, Parent: 99, Title: "Orphan", URL: ptrStr("https://example.com/"),
+			Position: 5}
+
+		// Denormalized records, i.e., with folder paths, and without the folders they're in
+		denormalizedWiki = bookmark.Bookmark{ID: 7, Parent: 6, Title: "Wiki", URL: ptrStr("https://wiki.example.com/"),
+			Folder: "toolbar/Work"}
+		denormalizedGoDev = bookmark.Bookmark{ID: 8, Parent: 3, Title: "Go", URL: ptrStr("https://go.dev/"),
+			Folder: "/toolbar/", Position: 1}
+
+		children = func(nodes ...TreeNode) *[]TreeNode {
+			nodes = append([]TreeNode{}, nodes...)
+			return &nodes
+		}
+	)
+
+	tests := []struct {
+		name      string
+		bookmarks []bookmark.Bookmark
+		want      []TreeNode
+	}{
+		{
+			name:      "Empty-Bookmarks",
+			bookmarks: []bookmark.Bookmark{},
+			want:      []TreeNode{},
+		},
+		{
+			name:      "Roots-With-Ordered-Children",
+			bookmarks: []bookmark.Bookmark{wiki, orphan, goDev, work, golang, tags, toolbar, menu, root},
+			want: []TreeNode{
+				{Bookmark: menu, Children: children()},
+				{Bookmark: toolbar, Children: children(
+					TreeNode{Bookmark: goDev},
+					TreeNode{Bookmark: work, Children: children(TreeNode{Bookmark: wiki})},
+				)},
+				{Bookmark: orphan},
+			},
+		},
+		{
+			name:      "Denormalized-Bookmarks",
+			bookmarks: []bookmark.Bookmark{denormalizedGoDev, denormalizedWiki},
+			want: []TreeNode{
+				{Bookmark: bookmark.Bookmark{ID: 9, Title: "toolbar", Type: bookmark.TypeFolder}, Children: children(
+					TreeNode{Bookmark: bookmark.Bookmark{ID: 10, Parent: 9, Title: "Work", Folder: "toolbar",
+						Type: bookmark.TypeFolder}, Children: children(TreeNode{Bookmark: denormalizedWiki})},
+					TreeNode{Bookmark: denormalizedGoDev},
+				)},
+			},
+		},
+		{
+			name:      "Denormalized-Bookmarks-In-Input-Folders",
+			bookmarks: []bookmark.Bookmark{root, toolbar, denormalizedWiki},
+			want: []TreeNode{
+				{Bookmark: toolbar, Children: children(
+					TreeNode{Bookmark: bookmark.Bookmark{ID: 8, Parent: 3, Title: "Work", Folder: "toolbar",
+						Type: bookmark.TypeFolder}, Children: children(TreeNode{Bookmark: denormalizedWiki})},
+				)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewTree(tt.bookmarks), "Mismatch of tree")
+		})
+	}
+}
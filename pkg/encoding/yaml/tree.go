@@ -0,0 +1,58 @@
+package yaml
+
+import (
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+)
+
+var TreeEncoderName = encoding.ToEncoder(constants.YAMLTreeFormat)
+
+func init() {
+	encoding.AllEncoders = append(encoding.AllEncoders, TreeEncoderName)
+}
+
+// TreeEncoder encodes the folder hierarchy of bookmarks, with folders nesting their children
+type TreeEncoder struct {
+	yamlEncoder *yaml.Encoder
+	filename    string
+}
+
+func NewTreeEncoder(out io.Writer) *TreeEncoder {
+	var filename string
+	if file, ok := any(out).(files.File); ok {
+		filename = file.Name()
+	}
+
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(8)
+	return &TreeEncoder{
+		yamlEncoder: encoder,
+		filename:    filename,
+	}
+}
+
+// Encode rebuilds the hierarchy from ID and Parent fields of bookmarks, or from the folder paths
+// of denormalized ones, and encodes it
+func (e *TreeEncoder) Encode(bookmarks []bookmark.Bookmark) error {
+	err := e.yamlEncoder.Encode(encoding.NewTree(bookmarks))
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML tree: %v", err)
+	}
+
+	return nil
+}
+
+func (e *TreeEncoder) String() string {
+	return TreeEncoderName.String()
+}
+
+func (e *TreeEncoder) Filename() string {
+	return e.filename
+}
@@ -0,0 +1,62 @@
+package yaml
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+)
+
+func TestTreeEncoder_Encode(t *testing.T) {
+	var (
+		buffer bytes.Buffer
+		got    []encoding.TreeNode
+
+		root    = bookmark.Bookmark{ID: 1, GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder}
+		toolbar = bookmark.Bookmark{ID: 3, Parent: 1, Title: "toolbar", Type: bookmark.TypeFolder}
+		empty   = bookmark.Bookmark{ID: 7, Parent: 3, Title: "Empty", Type: bookmark.TypeFolder}
+		goDev   = bookmark.Bookmark{ID: 8, Parent: 3, Title: "Go", URL: ptrStr("https://go.dev/"),
+			Type: bookmark.TypeBookmark, Position: 1, Tags: []string{"golang"}}
+	)
+
+	err := NewTreeEncoder(&buffer).Encode([]bookmark.Bookmark{goDev, empty, toolbar, root})
+	assert.NoError(t, err, "Unexpected error from encode")
+
+	assert.Contains(t, buffer.String(), "\n  children:\n        - url: null\n          title: Empty\n",
+		"Expected children nested in folder")
+	assert.NoError(t, yaml.Unmarshal(buffer.Bytes(), &got), "Unexpected error from reading tree")
+	assert.Equal(t, []encoding.TreeNode{{Bookmark: toolbar, Children: &[]encoding.TreeNode{
+		{Bookmark: empty, Children: &[]encoding.TreeNode{}},
+		{Bookmark: goDev},
+	}}}, got, "Mismatch of tree read back")
+}
+
+func TestTreeEncoder_Encode_Denormalized(t *testing.T) {
+	var (
+		buffer bytes.Buffer
+		got    []encoding.TreeNode
+
+		// Denormalized records, i.e., with folder paths, and without the folders they're in
+		goDev = bookmark.Bookmark{ID: 8, Parent: 3, Title: "Go", URL: ptrStr("https://go.dev/"),
+			Folder: "toolbar", Type: bookmark.TypeBookmark}
+		wiki = bookmark.Bookmark{ID: 9, Parent: 7, Title: "Wiki", URL: ptrStr("https://wiki.example.com/"),
+			Folder: "toolbar/Work", Type: bookmark.TypeBookmark, Position: 1}
+	)
+
+	err := NewTreeEncoder(&buffer).Encode([]bookmark.Bookmark{goDev, wiki})
+	assert.NoError(t, err, "Unexpected error from encode")
+
+	assert.NoError(t, yaml.Unmarshal(buffer.Bytes(), &got), "Unexpected error from reading tree")
+	assert.Equal(t, []encoding.TreeNode{{
+		Bookmark: bookmark.Bookmark{ID: 10, Title: "toolbar", Type: bookmark.TypeFolder},
+		Children: &[]encoding.TreeNode{
+			{Bookmark: goDev},
+			{Bookmark: bookmark.Bookmark{ID: 11, Parent: 10, Title: "Work", Folder: "toolbar", Type: bookmark.TypeFolder,
+				Position: 1}, Children: &[]encoding.TreeNode{{Bookmark: wiki}}},
+		},
+	}}, got, "Mismatch of tree synthesised from folder paths")
+}
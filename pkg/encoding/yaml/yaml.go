@@ -1,6 +1,7 @@
 package yaml
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
@@ -10,12 +11,15 @@ import (
 	"github.com/vaguecoder/firefox-backups/pkg/constants"
 	"github.com/vaguecoder/firefox-backups/pkg/encoding"
 	"github.com/vaguecoder/firefox-backups/pkg/files"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
 )
 
 var EncoderName = encoding.ToEncoder(constants.YAMLFormat)
 
 func init() {
 	encoding.AllEncoders = append(encoding.AllEncoders, EncoderName)
+	encoding.AllDecoders = append(encoding.AllDecoders, EncoderName)
 }
 
 type Encoder struct {
@@ -46,6 +50,24 @@ func (e *Encoder) Encode(bookmarks []bookmark.Bookmark) error {
 	return nil
 }
 
+func (e *Encoder) EncodeVisits(visits []history.Visit) error {
+	err := e.yamlEncoder.Encode(visits)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %v", err)
+	}
+
+	return nil
+}
+
+func (e *Encoder) EncodeLinks(checkedLinks []links.Link) error {
+	err := e.yamlEncoder.Encode(checkedLinks)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %v", err)
+	}
+
+	return nil
+}
+
 func (e *Encoder) String() string {
 	return EncoderName.String()
 }
@@ -53,3 +75,40 @@ func (e *Encoder) String() string {
 func (e *Encoder) Filename() string {
 	return e.filename
 }
+
+type Decoder struct {
+	yamlDecoder *yaml.Decoder
+}
+
+func NewDecoder(in io.Reader) *Decoder {
+	return &Decoder{
+		yamlDecoder: yaml.NewDecoder(in),
+	}
+}
+
+func (d *Decoder) Decode() ([]bookmark.Bookmark, error) {
+	var bookmarks []bookmark.Bookmark
+
+	err := d.yamlDecoder.Decode(&bookmarks)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to unmarshal YAML: %v", err)
+	}
+
+	if len(bookmarks) == 0 {
+		// Encoder writes no bookmarks as empty sequence
+		return nil, nil
+	}
+
+	for i := range bookmarks {
+		if len(bookmarks[i].Tags) == 0 {
+			// Encoder writes untagged bookmarks with empty sequence of tags
+			bookmarks[i].Tags = nil
+		}
+	}
+
+	return bookmarks, nil
+}
+
+func (d *Decoder) String() string {
+	return EncoderName.String()
+}
@@ -1 +1,90 @@
 package yaml
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+var ptrStr = util.PtrStr
+
+func TestDecoder_Decode(t *testing.T) {
+	type testData struct {
+		name      string
+		bookmarks []bookmark.Bookmark
+	}
+
+	var (
+		err       error
+		buffer    bytes.Buffer
+		decoded   []bookmark.Bookmark
+		testCase  testData
+		timestamp = time.Date(2023, time.March, 4, 5, 6, 7, 891011000, time.UTC)
+
+		bookmarks = []bookmark.Bookmark{
+			{
+				Title:        "Profiles",
+				Folder:       "Bookmarks Toolbar",
+				ID:           7,
+				Parent:       3,
+				GUID:         "folder-guid1",
+				Type:         bookmark.TypeFolder,
+				Position:     1,
+				DateAdded:    timestamp,
+				LastModified: timestamp.Add(time.Hour),
+			},
+			{
+				URL:          ptrStr("https://github.com/vaguecoder?tab=repositories"),
+				Title:        "Vague Coder · GitHub",
+				Folder:       "Bookmarks Toolbar/Profiles",
+				ID:           8,
+				Parent:       7,
+				GUID:         "bookmarkguid",
+				PlaceGUID:    "placeguid123",
+				Type:         bookmark.TypeBookmark,
+				Position:     0,
+				Keyword:      "gh",
+				DateAdded:    timestamp,
+				LastModified: timestamp,
+				Tags:         []string{"code", "profile"},
+			},
+			{
+				Folder:   "Bookmarks Toolbar/Profiles",
+				ID:       9,
+				Parent:   7,
+				GUID:     "separatorgd",
+				Type:     bookmark.TypeSeparator,
+				Position: 1,
+			},
+		}
+	)
+
+	tests := []testData{
+		{
+			name:      "Round-Trip",
+			bookmarks: bookmarks,
+		},
+		{
+			name:      "No-Bookmarks",
+			bookmarks: nil,
+		},
+	}
+
+	for _, testCase = range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			buffer.Reset()
+
+			err = NewEncoder(&buffer).Encode(testCase.bookmarks)
+			assert.NoErrorf(t, err, "Failed to encode bookmarks: %v", err)
+
+			decoded, err = NewDecoder(&buffer).Decode()
+			assert.NoErrorf(t, err, "Failed to decode bookmarks: %v", err)
+
+			assert.Equalf(t, testCase.bookmarks, decoded, "Mismatch in decoded bookmarks")
+		})
+	}
+}
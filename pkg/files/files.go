@@ -2,17 +2,24 @@ package files
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/vaguecoder/firefox-backups/pkg/logs"
 )
 
 type Operator struct {
 	logger logs.Logger
+
+	// Files created by the operator, the only files allowed to be deleted
+	created map[string]struct{}
+	mu      sync.Mutex
 }
 
 type FileOperator interface {
@@ -31,7 +38,8 @@ type File interface {
 
 func NewOperator(ctx context.Context) FileOperator {
 	return &Operator{
-		logger: logs.FromContext(ctx),
+		logger:  logs.FromContext(ctx),
+		created: map[string]struct{}{},
 	}
 }
 
@@ -51,11 +59,14 @@ func (o *Operator) Copy(src, dest string) error {
 		return fmt.Errorf("failed to write the file %q: %v", dest, err)
 	}
 
+	o.track(dest)
 	logger.Info().Msg("Successfully copied file")
 
 	return nil
 }
 
+// Delete deletes the file and the files with its name as prefix, e.g., SQLite's journal files.
+// It refuses to delete anything not created by the operator, and nothing is deleted in that case.
 func (o *Operator) Delete(filename string) error {
 	filenamePattern := fmt.Sprintf("%s*", filename)
 	logger := o.logger.With().Str("filename", filename).
@@ -70,17 +81,28 @@ func (o *Operator) Delete(filename string) error {
 	logger = logger.With().Strs("matching-files", files).
 		Int("totalFiles", len(files)).Logger()
 
+	for _, f := range files {
+		if !o.isCreated(f) {
+			// When the matching file is not created by operator, e.g., user's own places.sqlite
+			logger.Error().Str("current-file", f).Msg("Refused to delete file not created by operator")
+			return fmt.Errorf("refused to delete %q: not created by this run", f)
+		}
+	}
+
 	for i, f := range files {
-		if err := os.RemoveAll(f); err != nil {
+		if err := os.Remove(f); err != nil {
 			logger.Error().Err(err).
 				Str("current-file", f).
-				Int("deleted-count", i-1).
-				Msg("Failed to revove file")
+				Int("deleted-count", i).
+				Msg("Failed to remove file")
 			return fmt.Errorf("failed to remove file %q: %v", f, err)
 		}
-		logger.Info()
 	}
 
+	o.mu.Lock()
+	delete(o.created, absPath(filename))
+	o.mu.Unlock()
+
 	logger.Info().Msg("File(s) deleted successfully")
 
 	return nil
@@ -89,13 +111,25 @@ func (o *Operator) Delete(filename string) error {
 func (o *Operator) Open(filename string) (File, error) {
 	logger := o.logger.With().Str("filename", filename).Logger()
 
-	file, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0666)
+	// Create the parent directories, as the templates in filenames may expand to new directories
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		logger.Error().Err(err).Msg("Failed to create parent directories")
+		return nil, fmt.Errorf("failed to create parent directories of file %q: %v", filename, err)
+	}
+
+	// Try creating the file first, so only the files that didn't exist before are tracked as created
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666)
+	if err == nil {
+		o.track(filename)
+	} else if errors.Is(err, fs.ErrExist) {
+		file, err = os.OpenFile(filename, os.O_TRUNC|os.O_WRONLY, 0666)
+	}
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to open/create file")
 		return nil, fmt.Errorf("failed to open/create file %q: %v", filename, err)
 	}
 
-	o.logger.Info().Msg("Successfully opened/created file")
+	logger.Info().Msg("Successfully opened/created file")
 
 	return file, nil
 }
@@ -133,3 +167,40 @@ func chmod(filename string, permission fs.FileMode) error {
 
 	return nil
 }
+
+// track records the file as created by operator
+func (o *Operator) track(filename string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.created[absPath(filename)] = struct{}{}
+}
+
+// isCreated reports whether the file, or the SQLite database it belongs to, is created by operator
+func (o *Operator) isCreated(filename string) bool {
+	filename = absPath(filename)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.created[filename]; ok {
+		return true
+	}
+
+	for _, suffix := range sqliteSidecarSuffixes {
+		if _, ok := o.created[strings.TrimSuffix(filename, suffix)]; ok && strings.HasSuffix(filename, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// absPath returns the absolute path, or the cleaned path when the working directory is unknown
+func absPath(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		return abs
+	}
+
+	return filepath.Clean(filename)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -202,3 +203,88 @@ func TestOperator_Copy(t *testing.T) {
 		})
 	}
 }
+
+func TestOperator_Delete(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    []string
+		created     []string
+		foreign     []string
+		wantErr     bool
+		wantDeleted []string
+	}{
+		{
+			name:        "Created-Files",
+			created:     []string{"places.sqlite"},
+			foreign:     []string{"places.sqlite-wal"},
+			wantErr:     false,
+			wantDeleted: []string{"places.sqlite", "places.sqlite-wal"},
+		},
+		{
+			name:        "Failure-Foreign-Files",
+			created:     []string{"places.sqlite"},
+			foreign:     []string{"places.sqlite.bak"},
+			wantErr:     true,
+			wantDeleted: nil,
+		},
+		{
+			name:        "Failure-Existing-Files",
+			existing:    []string{"places.sqlite"},
+			created:     []string{"places.sqlite"},
+			wantErr:     true,
+			wantDeleted: nil,
+		},
+		{
+			name:        "Failure-Not-Created",
+			created:     nil,
+			foreign:     []string{"places.sqlite"},
+			wantErr:     true,
+			wantDeleted: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				dir      = t.TempDir()
+				filename = filepath.Join(dir, "places.sqlite")
+			)
+
+			ctx, _ := logs.NewLogger(context.Background(), bytes.NewBuffer([]byte{}), logs.LevelDebug)
+			fileOps := files.NewOperator(ctx)
+
+			for _, name := range tt.existing {
+				// The files existing before are truncated by the operator, but not owned by it
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("bookmarks"), 0600),
+					"Failed to create test file")
+			}
+
+			for _, name := range tt.created {
+				file, err := fileOps.Open(filepath.Join(dir, name))
+				require.NoError(t, err, "Unexpected error from open")
+				require.NoError(t, file.Close(), "Unexpected error from closing test file")
+			}
+
+			for _, name := range tt.foreign {
+				// The SQLite's files (e.g., -wal) are created by SQLite, not the operator
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("bookmarks"), 0600),
+					"Failed to create test file")
+			}
+
+			err := fileOps.Delete(filename)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from delete")
+
+				for _, name := range append(tt.created, tt.foreign...) {
+					assert.FileExists(t, filepath.Join(dir, name), "File is deleted despite refusal")
+				}
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from delete")
+
+			for _, name := range tt.wantDeleted {
+				assert.NoFileExists(t, filepath.Join(dir, name), "File is not deleted")
+			}
+		})
+	}
+}
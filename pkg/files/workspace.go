@@ -0,0 +1,135 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+const workspacePattern = `firefox-backups-*`
+
+// Suffixes of the files SQLite creates alongside the database file
+var sqliteSidecarSuffixes = []string{`-journal`, `-wal`, `-shm`}
+
+// Workspace is a private temporary directory owned by a run, holding the working files,
+// e.g., snapshot of places.sqlite. Only the files reserved in the workspace are deleted,
+// and the workspace refuses to delete anything it didn't create.
+type Workspace struct {
+	dir      string
+	reserved map[string]struct{}
+	mu       sync.Mutex
+	logger   logs.Logger
+}
+
+// NewWorkspace creates the workspace directory under the default temp directory,
+// accessible only by the current user
+func NewWorkspace(ctx context.Context) (*Workspace, error) {
+	dir, err := os.MkdirTemp("", workspacePattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %v", err)
+	}
+
+	logger := logs.FromContext(ctx).With().Str("workspace", dir).Logger()
+	logger.Info().Msg("Successfully created workspace")
+
+	return &Workspace{
+		dir:      dir,
+		reserved: map[string]struct{}{},
+		logger:   logs.FromRawLogger(logger),
+	}, nil
+}
+
+// Dir returns the workspace directory
+func (w *Workspace) Dir() string {
+	return w.dir
+}
+
+// Path reserves the file in workspace and returns its path. The name must be a plain
+// filename, so the file can't escape the workspace. The file itself is not created.
+func (w *Workspace) Path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid workspace filename %q", name)
+	}
+
+	path := filepath.Join(w.dir, name)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.reserved[path] = struct{}{}
+
+	return path, nil
+}
+
+// Remove deletes the reserved file along with SQLite's journal, WAL and shared memory files.
+// The files not reserved in workspace are never deleted.
+func (w *Workspace) Remove(filename string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.remove(filename)
+}
+
+// Close deletes all the reserved files and the workspace directory.
+// When the directory has files not created by the workspace, it is left in place with an error.
+func (w *Workspace) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for filename := range w.reserved {
+		if err := w.remove(filename); err != nil {
+			return err
+		}
+	}
+
+	// The directory is not removed recursively, so the foreign files are never deleted
+	if err := os.Remove(w.dir); err != nil {
+		w.logger.Error().Err(err).Msg("Failed to delete workspace")
+		return fmt.Errorf("failed to delete workspace %q, it may have files not created by workspace: %v", w.dir, err)
+	}
+
+	w.logger.Info().Msg("Successfully deleted workspace")
+
+	return nil
+}
+
+// remove deletes the reserved file and its sidecar files. The caller holds the lock.
+func (w *Workspace) remove(filename string) error {
+	logger := w.logger.With().Str("filename", filename).Logger()
+
+	filename = filepath.Clean(filename)
+	if _, ok := w.reserved[filename]; !ok {
+		// When the file isn't created by workspace
+		logger.Error().Msg("Refused to delete file not created by workspace")
+		return fmt.Errorf("refused to delete %q: not created by workspace %q", filename, w.dir)
+	}
+
+	for _, path := range append([]string{filename}, sidecars(filename)...) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Error().Err(err).Str("current-file", path).Msg("Failed to delete file")
+			return fmt.Errorf("failed to delete file %q: %v", path, err)
+		}
+	}
+
+	delete(w.reserved, filename)
+	logger.Info().Msg("File(s) deleted successfully")
+
+	return nil
+}
+
+// sidecars returns the files SQLite may create alongside the database file
+func sidecars(filename string) []string {
+	paths := make([]string, 0, len(sqliteSidecarSuffixes))
+
+	for _, suffix := range sqliteSidecarSuffixes {
+		paths = append(paths, filename+suffix)
+	}
+
+	return paths
+}
@@ -0,0 +1,177 @@
+package files_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vaguecoder/firefox-backups/pkg/files"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+func newWorkspace(t *testing.T) *files.Workspace {
+	ctx, _ := logs.NewLogger(context.Background(), bytes.NewBuffer([]byte{}), logs.LevelDebug)
+
+	workspace, err := files.NewWorkspace(ctx)
+	require.NoError(t, err, "Unexpected error from creating workspace")
+
+	t.Cleanup(func() {
+		// Clean up the leftovers of failed test cases
+		_ = os.RemoveAll(workspace.Dir())
+	})
+
+	return workspace
+}
+
+func TestNewWorkspace(t *testing.T) {
+	workspace := newWorkspace(t)
+
+	info, err := os.Stat(workspace.Dir())
+	require.NoError(t, err, "Workspace directory doesn't exist")
+
+	assert.True(t, info.IsDir(), "Workspace is not a directory")
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm(), "Workspace is accessible by other users")
+}
+
+func TestWorkspace_Path(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "Filename",
+			input:   "places.sqlite",
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Blank",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Parent-Directory",
+			input:   "../places.sqlite",
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Sub-Directory",
+			input:   "profile/places.sqlite",
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Dot-Dot",
+			input:   "..",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := newWorkspace(t)
+
+			got, err := workspace.Path(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from path")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from path")
+			assert.Equal(t, filepath.Join(workspace.Dir(), tt.input), got, "Mismatch of path in workspace")
+		})
+	}
+}
+
+func TestWorkspace_Remove(t *testing.T) {
+	workspace := newWorkspace(t)
+
+	reserved, err := workspace.Path("places.sqlite")
+	require.NoError(t, err, "Unexpected error from path")
+
+	for _, filename := range []string{reserved, reserved + "-journal", reserved + "-wal"} {
+		require.NoError(t, os.WriteFile(filename, []byte("bookmarks"), 0600), "Failed to create test file")
+	}
+
+	// Files not created by workspace, both inside and outside the workspace
+	foreign := filepath.Join(workspace.Dir(), "favicons.sqlite")
+	outside := filepath.Join(t.TempDir(), "places.sqlite")
+
+	for _, filename := range []string{foreign, outside} {
+		require.NoError(t, os.WriteFile(filename, []byte("bookmarks"), 0600), "Failed to create test file")
+
+		assert.Error(t, workspace.Remove(filename), "Expected refusal to delete %q", filename)
+		assert.FileExists(t, filename, "File not created by workspace is deleted")
+	}
+
+	assert.NoError(t, workspace.Remove(reserved), "Unexpected error from remove")
+
+	for _, filename := range []string{reserved, reserved + "-journal", reserved + "-wal"} {
+		assert.NoFileExists(t, filename, "Reserved file is not deleted")
+	}
+
+	assert.Error(t, workspace.Remove(reserved), "Expected refusal to delete removed file again")
+}
+
+func TestWorkspace_Close(t *testing.T) {
+	tests := []struct {
+		name       string
+		reserved   []string
+		foreign    []string
+		wantErr    bool
+		wantDelete bool
+	}{
+		{
+			name:       "Empty",
+			wantErr:    false,
+			wantDelete: true,
+		},
+		{
+			name:       "Reserved-Files",
+			reserved:   []string{"places.sqlite", "places.sqlite-wal", "bookmarks.json"},
+			wantErr:    false,
+			wantDelete: true,
+		},
+		{
+			name:       "Failure-Foreign-Files",
+			reserved:   []string{"places.sqlite"},
+			foreign:    []string{"favicons.sqlite"},
+			wantErr:    true,
+			wantDelete: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := newWorkspace(t)
+
+			for _, name := range tt.reserved {
+				filename, err := workspace.Path(name)
+				require.NoError(t, err, "Unexpected error from path")
+				require.NoError(t, os.WriteFile(filename, []byte("bookmarks"), 0600), "Failed to create test file")
+			}
+
+			for _, name := range tt.foreign {
+				filename := filepath.Join(workspace.Dir(), name)
+				require.NoError(t, os.WriteFile(filename, []byte("bookmarks"), 0600), "Failed to create test file")
+			}
+
+			err := workspace.Close()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from close")
+			} else {
+				assert.NoError(t, err, "Unexpected error from close")
+			}
+
+			if tt.wantDelete {
+				assert.NoDirExists(t, workspace.Dir(), "Workspace is not deleted")
+				return
+			}
+
+			for _, name := range tt.foreign {
+				assert.FileExists(t, filepath.Join(workspace.Dir(), name), "File not created by workspace is deleted")
+			}
+		})
+	}
+}
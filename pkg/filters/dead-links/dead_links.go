@@ -0,0 +1,62 @@
+package deadlinks
+
+import (
+	"context"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/filters"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+var FilterName = filters.ToFilterName(constants.DeadLinksFilter)
+
+func init() {
+	filters.AllFilterNames = append(filters.AllFilterNames, FilterName)
+}
+
+// DeadLinkRemover drops the bookmarks whose URLs are dead, as checked by the link checker,
+// i.e., the requests failed or the pages are not found or gone. The records other than
+// http and https bookmarks, e.g., folders and place: queries, are kept as is.
+type DeadLinkRemover struct {
+	checker *links.Checker
+}
+
+// NewDeadLinkRemover initiates new DeadLinkRemover with the link checker
+func NewDeadLinkRemover(checker *links.Checker) *DeadLinkRemover {
+	return &DeadLinkRemover{checker: checker}
+}
+
+func (d *DeadLinkRemover) Apply(ctx context.Context, bookmarks []bookmark.Bookmark) ([]bookmark.Bookmark, error) {
+	var (
+		result []bookmark.Bookmark
+		dead   = make(map[string]bool)
+
+		logger = logs.FromContext(ctx).With().Int("initial-count", len(bookmarks)).
+			Stringer("filter", FilterName).Logger()
+	)
+
+	for _, link := range d.checker.Check(ctx, bookmarks) {
+		if link.IsDead() {
+			dead[link.URL] = true
+		}
+	}
+
+	for _, b := range bookmarks {
+		if b.URL != nil && dead[*b.URL] {
+			logger.Info().Str("url", *b.URL).Str("title", b.Title).Msg("Dropped dead link")
+			continue
+		}
+
+		result = append(result, b)
+	}
+
+	logger.Info().Int("final-count", len(result)).Msg("Dead links dropped")
+
+	return result, nil
+}
+
+func (d *DeadLinkRemover) String() string {
+	return FilterName.String()
+}
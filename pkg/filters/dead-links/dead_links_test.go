@@ -0,0 +1,41 @@
+package deadlinks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+func TestDeadLinkRemover_Apply(t *testing.T) {
+	var ptrStr = util.PtrStr
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer server.Close()
+
+	var (
+		folder = bookmark.Bookmark{ID: 2, Title: "toolbar", Type: bookmark.TypeFolder}
+		live   = bookmark.Bookmark{ID: 3, Parent: 2, Title: "Live", URL: ptrStr(server.URL + "/ok")}
+		gone   = bookmark.Bookmark{ID: 4, Parent: 2, Title: "Gone", URL: ptrStr(server.URL + "/gone")}
+		query  = bookmark.Bookmark{ID: 5, Parent: 2, Title: "Most Visited", URL: ptrStr("place:sort=8&maxResults=10")}
+	)
+
+	ctx, _ := logs.SilentLogger(context.Background())
+	remover := NewDeadLinkRemover(links.NewChecker(ctx, server.Client()))
+
+	got, err := remover.Apply(ctx, []bookmark.Bookmark{folder, live, gone, query})
+
+	assert.NoError(t, err, "Unexpected error from filter")
+	assert.Equal(t, []bookmark.Bookmark{folder, live, query}, got, "Mismatch of bookmarks without dead links")
+	assert.Equal(t, FilterName.String(), remover.String(), "Mismatch of filter name")
+}
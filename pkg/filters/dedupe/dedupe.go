@@ -0,0 +1,191 @@
+package dedupe
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/filters"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/urls"
+)
+
+var FilterName = filters.ToFilterName(constants.DedupeFilter)
+
+func init() {
+	filters.AllFilterNames = append(filters.AllFilterNames, FilterName)
+}
+
+// Deduplicator finds the bookmarks of the same page, i.e., of equal URLs after normalization.
+// Along with the rules of urls.Normalize, the tracking params and the trailing slash are dropped,
+// and so is the fragment, optionally. The policy decides what is done with every group of duplicates:
+//  1. first - The first bookmark of the group is kept.
+//  2. newest - The most recently modified bookmark of the group is kept.
+//  3. report - All the bookmarks are kept, and the groups are only logged.
+//
+// The tags of the dropped duplicates are added to the kept bookmark. The tag records,
+// i.e., the records in tag folders of raw input, aren't bookmarks and are never dropped.
+type Deduplicator struct {
+	policy  constants.Constant[constants.Policy]
+	options urls.Options
+}
+
+// NewDeduplicator initiates new Deduplicator with the policy, and whether the fragments of URLs are ignored
+func NewDeduplicator(policy constants.Constant[constants.Policy], ignoreFragment bool) *Deduplicator {
+	return &Deduplicator{
+		policy: policy,
+		options: urls.Options{
+			DropTrackingParams: true,
+			DropTrailingSlash:  true,
+			DropFragment:       ignoreFragment,
+		},
+	}
+}
+
+func (d *Deduplicator) Apply(ctx context.Context, bookmarks []bookmark.Bookmark) ([]bookmark.Bookmark, error) {
+	var (
+		result     []bookmark.Bookmark
+		duplicates int
+
+		groups     = map[string][]int{}
+		groupOrder []string
+		dropped    = make(map[int]bool)
+		tagRecords = tagRecordIndexes(bookmarks)
+
+		logger = logs.FromContext(ctx).With().Int("initial-count", len(bookmarks)).
+			Stringer("filter", FilterName).Stringer("policy", d.policy).Logger()
+	)
+
+	switch d.policy {
+	case constants.KeepFirstPolicy, constants.NewestPolicy, constants.ReportPolicy:
+		// Known policies
+	default:
+		return nil, fmt.Errorf("invalid dedupe policy %q (available policies: [%s, %s, %s])", d.policy,
+			constants.KeepFirstPolicy, constants.NewestPolicy, constants.ReportPolicy)
+	}
+
+	// Group the bookmarks by normalized URL, in order of their first appearance
+	for i, b := range bookmarks {
+		if b.URL == nil || b.Type == bookmark.TypeSeparator || tagRecords[i] {
+			// Folders, separators and tag records aren't duplicates
+			continue
+		}
+
+		key := d.options.Normalize(*b.URL)
+		if _, ok := groups[key]; !ok {
+			groupOrder = append(groupOrder, key)
+		}
+
+		groups[key] = append(groups[key], i)
+	}
+
+	for _, key := range groupOrder {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+
+		duplicates += len(group) - 1
+
+		if d.policy == constants.ReportPolicy {
+			logger.Warn().Str("normalized-url", key).Interface("duplicates", describe(bookmarks, group)).
+				Msg("Duplicate bookmarks found")
+			continue
+		}
+
+		kept := group[0]
+		for _, i := range group[1:] {
+			if d.policy == constants.NewestPolicy && bookmarks[i].LastModified.After(bookmarks[kept].LastModified) {
+				// When the later duplicate is more recently modified
+				kept = i
+			}
+		}
+
+		for _, i := range group {
+			if i != kept {
+				dropped[i] = true
+				bookmarks[kept].Tags = bookmark.UnionTags(bookmarks[kept].Tags, bookmarks[i].Tags)
+			}
+		}
+	}
+
+	for i, b := range bookmarks {
+		if !dropped[i] {
+			result = append(result, b)
+		}
+	}
+
+	logger.Info().Int("duplicates-count", duplicates).Int("final-count", len(result)).Msg("Duplicates resolved")
+
+	return result, nil
+}
+
+func (d *Deduplicator) String() string {
+	return FilterName.String()
+}
+
+// duplicate is a bookmark of a group of duplicates, as reported in logs
+type duplicate struct {
+	ID     int    `json:"id"`
+	GUID   string `json:"guid"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Folder string `json:"folder"`
+}
+
+// describe lists the bookmarks of the group of duplicates for logs
+func describe(bookmarks []bookmark.Bookmark, group []int) []duplicate {
+	list := make([]duplicate, 0, len(group))
+
+	for _, i := range group {
+		b := bookmarks[i]
+		list = append(list, duplicate{ID: b.ID, GUID: b.GUID, Title: b.Title, URL: *b.URL, Folder: b.Folder})
+	}
+
+	return list
+}
+
+// tagRecordIndexes returns the indexes of the records in tag folders, i.e., the folders in tags root.
+// The tags root is identified by its GUID, or by its title in the places root when GUIDs are missing.
+// The tag records are found only in raw input, as the tags filter drops them.
+func tagRecordIndexes(bookmarks []bookmark.Bookmark) map[int]bool {
+	var (
+		tagsRootID = -1
+		tagFolders = make(map[int]bool)
+		tagRecords = make(map[int]bool)
+		placesRoot = make(map[int]bool)
+	)
+
+	for _, b := range bookmarks {
+		if (&bookmark.Node{Bookmark: b}).IsPlacesRoot() {
+			placesRoot[b.ID] = true
+		}
+	}
+
+	for _, b := range bookmarks {
+		if (&bookmark.Node{Bookmark: b}).IsTagsRoot(placesRoot[b.Parent]) {
+			tagsRootID = b.ID
+			break
+		}
+	}
+
+	if tagsRootID < 0 {
+		// When the input has no tags root, such as denormalized records or jsonlz4 backups
+		return tagRecords
+	}
+
+	for _, b := range bookmarks {
+		if b.Parent == tagsRootID && b.URL == nil {
+			tagFolders[b.ID] = true
+		}
+	}
+
+	for i, b := range bookmarks {
+		if tagFolders[b.Parent] && b.URL != nil {
+			tagRecords[i] = true
+		}
+	}
+
+	return tagRecords
+}
@@ -0,0 +1,118 @@
+package dedupe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+func TestDeduplicator_Apply(t *testing.T) {
+	type args struct {
+		policy         constants.Constant[constants.Policy]
+		ignoreFragment bool
+	}
+
+	var (
+		ptrStr = util.PtrStr
+		older  = time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+		newer  = time.Date(2023, time.March, 4, 0, 0, 0, 0, time.UTC)
+
+		menu  = bookmark.Bookmark{ID: 2, Parent: 1, GUID: "menu________", Title: "menu", Type: bookmark.TypeFolder}
+		first = bookmark.Bookmark{ID: 6, Parent: 2, Title: "Go", URL: ptrStr("https://go.dev/doc/"),
+			LastModified: older, Tags: []string{"go"}}
+		tracked = bookmark.Bookmark{ID: 7, Parent: 2, Title: "Go Docs", URL: ptrStr("HTTPS://GO.DEV:443/doc?utm_source=x"),
+			LastModified: newer, Tags: []string{"docs"}}
+		anchored = bookmark.Bookmark{ID: 8, Parent: 2, Title: "Install Go", URL: ptrStr("https://go.dev/doc#install"),
+			LastModified: older}
+		other = bookmark.Bookmark{ID: 9, Parent: 2, Title: "Vague Coder", URL: ptrStr("https://github.com/vaguecoder")}
+
+		bookmarks = func() []bookmark.Bookmark {
+			return []bookmark.Bookmark{menu, first, tracked, anchored, other}
+		}
+
+		with = func(b bookmark.Bookmark, tags ...string) bookmark.Bookmark {
+			b.Tags = tags
+			return b
+		}
+	)
+
+	tests := []struct {
+		name      string
+		args      args
+		bookmarks []bookmark.Bookmark
+		want      []bookmark.Bookmark
+		wantErr   bool
+	}{
+		{
+			name:      "Keep-First",
+			args:      args{policy: constants.KeepFirstPolicy},
+			bookmarks: bookmarks(),
+			want:      []bookmark.Bookmark{menu, with(first, "go", "docs"), anchored, other},
+		},
+		{
+			name:      "Keep-Newest",
+			args:      args{policy: constants.NewestPolicy},
+			bookmarks: bookmarks(),
+			want:      []bookmark.Bookmark{menu, with(tracked, "docs", "go"), anchored, other},
+		},
+		{
+			name:      "Keep-First-Ignoring-Fragment",
+			args:      args{policy: constants.KeepFirstPolicy, ignoreFragment: true},
+			bookmarks: bookmarks(),
+			want:      []bookmark.Bookmark{menu, with(first, "go", "docs"), other},
+		},
+		{
+			name:      "Report-Only",
+			args:      args{policy: constants.ReportPolicy, ignoreFragment: true},
+			bookmarks: bookmarks(),
+			want:      bookmarks(),
+		},
+		{
+			name: "Tag-Records-Are-Not-Duplicates",
+			args: args{policy: constants.KeepFirstPolicy},
+			bookmarks: []bookmark.Bookmark{
+				{ID: 1, Parent: 0, GUID: "root________"},
+				menu,
+				{ID: 4, Parent: 1, GUID: "tags________", Title: "tags"},
+				{ID: 5, Parent: 4, Title: "go"},
+				first,
+				{ID: 10, Parent: 5, URL: ptrStr("https://go.dev/doc/")},
+			},
+			want: []bookmark.Bookmark{
+				{ID: 1, Parent: 0, GUID: "root________"},
+				menu,
+				{ID: 4, Parent: 1, GUID: "tags________", Title: "tags"},
+				{ID: 5, Parent: 4, Title: "go"},
+				first,
+				{ID: 10, Parent: 5, URL: ptrStr("https://go.dev/doc/")},
+			},
+		},
+		{
+			name:      "Invalid-Policy",
+			args:      args{policy: "oldest"},
+			bookmarks: bookmarks(),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := logs.SilentLogger(context.Background())
+
+			got, err := NewDeduplicator(tt.args.policy, tt.args.ignoreFragment).Apply(ctx, tt.bookmarks)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from filter")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from filter")
+			assert.Equal(t, tt.want, got, "Mismatch of deduplicated bookmarks")
+		})
+	}
+}
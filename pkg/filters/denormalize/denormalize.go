@@ -2,7 +2,6 @@ package denormalize
 
 import (
 	"context"
-	"fmt"
 	"sort"
 
 	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
@@ -17,76 +16,159 @@ func init() {
 	filters.AllFilterNames = append(filters.AllFilterNames, FilterName)
 }
 
+// Denormalizer sets the path of the folder every record is in, i.e., the titles of its ancestor folders
+// from the top-level folder, as the Folder field. The folders having child records are dropped, as their
+// paths are held by the children, while the empty folders are kept. The records are ordered on ID.
+//
+// The paths are resolved in a single pass over the records, with the path of every folder resolved once.
+//  1. Orphans, i.e., the records whose parent is not in the input, keep their Folder field as it is,
+//     which is the path for the records denormalized already, and blank for the raw records.
+//  2. Cycles of parents are cut at the first folder of the cycle found, which is treated as an orphan.
 type Denormalizer struct{}
 
 func (d *Denormalizer) Apply(ctx context.Context, bookmarks []bookmark.Bookmark) ([]bookmark.Bookmark, error) {
-	logger := logs.FromContext(ctx).With().Int("initial-count", len(bookmarks)).
-		Stringer("filter", FilterName).Logger()
+	var (
+		result []bookmark.Bookmark
+		r      = newResolver(bookmarks)
 
-	sort.Slice(bookmarks, func(i, j int) bool {
-		return (bookmarks)[i].ID < (bookmarks)[j].ID
+		logger = logs.FromContext(ctx).With().Int("initial-count", len(bookmarks)).
+			Stringer("filter", FilterName).Logger()
+	)
+
+	sort.SliceStable(bookmarks, func(i, j int) bool {
+		return bookmarks[i].ID < bookmarks[j].ID
 	})
 	logger.Info().Msg("Bookmarks sorted on ID")
 
-	var prevLen int
-	for len(bookmarks) != prevLen {
-		prevLen = len(bookmarks)
-		for _, b := range bookmarks {
-			result := updatePathInChildBookmarks(b, bookmarks)
-			if result != nil {
-				bookmarks = result
-				break
-			}
+	for _, b := range bookmarks {
+		folder := r.folderOf(b)
+
+		switch {
+		case b.Parent == b.ID:
+			// When the record is its own parent, it's a cycle of its own
+			r.cycles = append(r.cycles, b.ID)
+		case r.isOrphan(b):
+			r.orphans = append(r.orphans, b.ID)
+		}
+
+		if r.isParent[b.ID] {
+			// When the folder's path is held by its children
+			continue
 		}
+
+		b.Folder = folder
+		result = append(result, b)
+	}
+
+	if len(r.orphans) > 0 {
+		logger.Warn().Ints("ids", r.orphans).Msg("Parents of bookmarks not found, folder paths are left blank")
+	}
+
+	if len(r.cycles) > 0 {
+		logger.Warn().Ints("ids", r.cycles).Msg("Cycles of parents found, folders are treated as top-level")
 	}
 
-	return bookmarks, nil
+	logger.Info().Int("final-count", len(result)).Msg("Bookmarks denormalized")
+
+	return result, nil
 }
 
 func (d *Denormalizer) String() string {
 	return FilterName.String()
 }
 
-func updatePathInChildBookmarks(parentBookmark bookmark.Bookmark, inputBookmarks []bookmark.Bookmark) []bookmark.Bookmark {
-	var resultantBookmarks []bookmark.Bookmark
-	var isUpdated bool
+// resolution is the state of resolving the path of a folder
+type resolution int
 
-	for _, b := range inputBookmarks {
-		if parentBookmark.ID == b.ID {
-			continue
+const (
+	unresolved resolution = iota
+	resolving             // The path of an ancestor is being resolved
+	resolved
+)
+
+// resolver resolves the paths of folders, memoising them against the folder IDs
+type resolver struct {
+	folders  map[int]bookmark.Bookmark
+	isParent map[int]bool
+	state    map[int]resolution
+	paths    map[int]string
+	cut      map[int]bool // Folders at which the cycles of parents are cut
+
+	orphans, cycles []int
+}
+
+func newResolver(bookmarks []bookmark.Bookmark) *resolver {
+	r := &resolver{
+		folders:  make(map[int]bookmark.Bookmark),
+		isParent: make(map[int]bool),
+		state:    make(map[int]resolution),
+		paths:    make(map[int]string),
+		cut:      make(map[int]bool),
+	}
+
+	ids := make(map[int]bookmark.Bookmark, len(bookmarks))
+	for _, b := range bookmarks {
+		if _, ok := ids[b.ID]; !ok {
+			// The first of the records with same ID is the parent of their children
+			ids[b.ID] = b
 		}
+	}
 
-		if b.Parent == parentBookmark.ID {
-			isUpdated = true
-
-			current := bookmark.Bookmark{
-				ID:     b.ID,
-				Parent: b.Parent,
-				URL:    b.URL,
-				Title:  b.Title,
-				Folder: "",
-			}
-
-			parentTitle := parentBookmark.Title
-			if parentBookmark.Folder != "" {
-				parentTitle = fmt.Sprintf("%s/%s", parentBookmark.Folder, parentTitle)
-			}
-
-			if b.Folder != "" {
-				current.Folder = fmt.Sprintf("%s/%s", parentTitle, b.Folder)
-			} else {
-				current.Folder = parentTitle
-			}
-
-			resultantBookmarks = append(resultantBookmarks, current)
-			continue
+	for _, b := range bookmarks {
+		if parent, ok := ids[b.Parent]; ok && b.Parent != b.ID {
+			r.folders[b.Parent] = parent
+			r.isParent[b.Parent] = true
 		}
+	}
+
+	return r
+}
+
+// folderOf returns the path of the folder the record is in
+func (r *resolver) folderOf(b bookmark.Bookmark) string {
+	if _, ok := r.folders[b.Parent]; !ok || b.Parent == b.ID || r.cut[b.ID] {
+		// When the record is an orphan, its own parent, or the folder at which a cycle is cut
+		return b.Folder
+	}
+
+	return r.pathOf(b.Parent)
+}
 
-		resultantBookmarks = append(resultantBookmarks, b)
+// isOrphan reports whether the raw record isn't top-level, and its parent isn't in the input
+func (r *resolver) isOrphan(b bookmark.Bookmark) bool {
+	_, ok := r.folders[b.Parent]
+
+	return !ok && b.Parent != 0 && b.Parent != b.ID && b.Folder == ""
+}
+
+// pathOf returns the path of the folder, including its own title
+func (r *resolver) pathOf(id int) string {
+	folder := r.folders[id]
+
+	switch r.state[id] {
+	case resolved:
+		return r.paths[id]
+	case resolving:
+		// When the folder is its own ancestor, the cycle is cut at it
+		r.cut[id] = true
+		r.cycles = append(r.cycles, id)
+		r.resolve(id, folder.Folder)
+
+		return r.paths[id]
 	}
-	if isUpdated {
-		return resultantBookmarks
+
+	r.state[id] = resolving
+	parentPath := r.folderOf(folder)
+
+	if r.state[id] == resolving {
+		// When the cycle isn't cut at the folder while resolving its ancestors
+		r.resolve(id, parentPath)
 	}
 
-	return nil
+	return r.paths[id]
+}
+
+// resolve memoises the path of the folder, given the path of the folder it's in
+func (r *resolver) resolve(id int, parentPath string) {
+	r.paths[id], r.state[id] = bookmark.JoinPath(parentPath, r.folders[id].Title), resolved
 }
@@ -0,0 +1,66 @@
+package denormalize
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+// generateBookmarks generates the records of places root, built-in roots, and the folders nested in them,
+// along with the bookmarks spread across the folders, as in a large profile
+func generateBookmarks(count int) []bookmark.Bookmark {
+	const (
+		foldersPerFolder   = 4
+		bookmarksPerFolder = 20
+	)
+
+	var (
+		folders   []int
+		bookmarks = []bookmark.Bookmark{{ID: 1, GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder}}
+	)
+
+	for _, root := range []string{bookmark.MenuRoot, bookmark.ToolbarRoot, bookmark.UnfiledRoot} {
+		id := len(bookmarks) + 1
+		bookmarks = append(bookmarks, bookmark.Bookmark{ID: id, Parent: 1, Title: root, Type: bookmark.TypeFolder})
+		folders = append(folders, id)
+	}
+
+	// Every folder is a child of a former folder, so the hierarchy is a few levels deep
+	for i := 0; len(folders) < count/bookmarksPerFolder; i++ {
+		id := len(bookmarks) + 1
+		bookmarks = append(bookmarks, bookmark.Bookmark{ID: id, Parent: folders[i/foldersPerFolder],
+			Title: fmt.Sprintf("Folder %d", id), Type: bookmark.TypeFolder})
+		folders = append(folders, id)
+	}
+
+	for i := 0; len(bookmarks) < count; i++ {
+		id := len(bookmarks) + 1
+		bookmarks = append(bookmarks, bookmark.Bookmark{ID: id, Parent: folders[i%len(folders)],
+			Title: fmt.Sprintf("Bookmark %d", id), URL: util.PtrStr(fmt.Sprintf("https://example.com/%d", id)),
+			Type: bookmark.TypeBookmark})
+	}
+
+	return bookmarks
+}
+
+func BenchmarkDenormalizer_Apply(b *testing.B) {
+	ctx, _ := logs.SilentLogger(context.Background())
+
+	for _, count := range []int{1000, 10000, 50000} {
+		bookmarks := generateBookmarks(count)
+
+		b.Run(fmt.Sprintf("Bookmarks-%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				input := append([]bookmark.Bookmark{}, bookmarks...)
+
+				if _, err := (&Denormalizer{}).Apply(ctx, input); err != nil {
+					b.Fatalf("Unexpected error from filter: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -1 +1,107 @@
 package denormalize
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+func TestDenormalizer_Apply(t *testing.T) {
+	var (
+		ptrStr = util.PtrStr
+
+		root    = bookmark.Bookmark{ID: 1, GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder}
+		toolbar = bookmark.Bookmark{ID: 3, Parent: 1, Title: "toolbar", Type: bookmark.TypeFolder}
+		unfiled = bookmark.Bookmark{ID: 5, Parent: 1, Title: "unfiled", Type: bookmark.TypeFolder}
+		github  = bookmark.Bookmark{ID: 7, Parent: 3, Title: "GitHub", Type: bookmark.TypeFolder}
+		coder   = bookmark.Bookmark{ID: 8, Parent: 7, Title: "Vague Coder", URL: ptrStr("https://github.com/vaguecoder")}
+		golang  = bookmark.Bookmark{ID: 9, Parent: 3, Title: "Go", URL: ptrStr("https://go.dev/")}
+
+		in = func(b bookmark.Bookmark, folder string) bookmark.Bookmark {
+			b.Folder = folder
+			return b
+		}
+	)
+
+	tests := []struct {
+		name      string
+		bookmarks []bookmark.Bookmark
+		want      []bookmark.Bookmark
+	}{
+		{
+			name:      "Empty-Bookmarks",
+			bookmarks: []bookmark.Bookmark{},
+			want:      nil,
+		},
+		{
+			name:      "Nested-Folders-Unordered",
+			bookmarks: []bookmark.Bookmark{golang, coder, github, unfiled, toolbar, root},
+			want:      []bookmark.Bookmark{unfiled, in(coder, "toolbar/GitHub"), in(golang, "toolbar")},
+		},
+		{
+			name: "Folder-With-Lower-ID-Than-Parent",
+			bookmarks: []bookmark.Bookmark{root, toolbar,
+				{ID: 10, Parent: 3, Title: "Work", Type: bookmark.TypeFolder},
+				{ID: 4, Parent: 10, Title: "Team", Type: bookmark.TypeFolder},
+				{ID: 6, Parent: 4, Title: "Board", URL: ptrStr("https://board.example.com/")},
+			},
+			want: []bookmark.Bookmark{
+				{ID: 6, Parent: 4, Title: "Board", URL: ptrStr("https://board.example.com/"), Folder: "toolbar/Work/Team"},
+			},
+		},
+		{
+			name: "Orphans",
+			bookmarks: []bookmark.Bookmark{root, toolbar, golang,
+				{ID: 20, Parent: 99, Title: "Raw Orphan", URL: ptrStr("https://example.com/raw")},
+				{ID: 21, Parent: 98, Title: "Denormalized", URL: ptrStr("https://example.com/denormalized"),
+					Folder: "menu/Work"},
+			},
+			want: []bookmark.Bookmark{in(golang, "toolbar"),
+				{ID: 20, Parent: 99, Title: "Raw Orphan", URL: ptrStr("https://example.com/raw")},
+				{ID: 21, Parent: 98, Title: "Denormalized", URL: ptrStr("https://example.com/denormalized"),
+					Folder: "menu/Work"},
+			},
+		},
+		{
+			name: "Cycles-Of-Parents",
+			bookmarks: []bookmark.Bookmark{
+				{ID: 20, Parent: 21, Title: "A", Type: bookmark.TypeFolder},
+				{ID: 21, Parent: 20, Title: "B", Type: bookmark.TypeFolder},
+				{ID: 22, Parent: 20, Title: "Leaf", URL: ptrStr("https://example.com/leaf")},
+				{ID: 30, Parent: 30, Title: "Self", Type: bookmark.TypeFolder},
+			},
+			want: []bookmark.Bookmark{
+				{ID: 22, Parent: 20, Title: "Leaf", URL: ptrStr("https://example.com/leaf"), Folder: "B/A"},
+				{ID: 30, Parent: 30, Title: "Self", Type: bookmark.TypeFolder},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := logs.SilentLogger(context.Background())
+
+			got, err := (&Denormalizer{}).Apply(ctx, tt.bookmarks)
+			assert.NoError(t, err, "Unexpected error from filter")
+			assert.Equal(t, tt.want, got, "Mismatch of denormalized bookmarks")
+		})
+	}
+}
+
+func TestDenormalizer_Apply_Generated(t *testing.T) {
+	ctx, _ := logs.SilentLogger(context.Background())
+
+	got, err := (&Denormalizer{}).Apply(ctx, generateBookmarks(1000))
+	assert.NoError(t, err, "Unexpected error from filter")
+
+	for _, b := range got {
+		if b.URL != nil {
+			// Every bookmark is in a folder nested in a built-in root
+			assert.Regexp(t, `^(menu|toolbar|unfiled)(/Folder \d+)*$`, b.Folder, "Mismatch of folder path")
+		}
+	}
+}
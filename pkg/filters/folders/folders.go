@@ -0,0 +1,133 @@
+package folders
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/filters"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+var FilterName = filters.ToFilterName(constants.FoldersFilter)
+
+func init() {
+	filters.AllFilterNames = append(filters.AllFilterNames, FilterName)
+}
+
+// FolderFilter keeps the records in the folders matching include globs, and drops the records
+// in the folders matching exclude globs. The path of every record, i.e., the titles of its folders
+// from the top-level folder along with its own title, is rebuilt from the ID and Parent fields,
+// so the filter works alike on raw and denormalized records.
+//
+// A record matching a glob includes or excludes its descendants too, e.g., toolbar/Work keeps
+// the Work folder along with its subtree, and other/Archive/* drops everything in Archive folder.
+// Exclusion takes precedence over inclusion, and everything is included when there are no include globs.
+// The ancestor folders of kept records are kept, so that the hierarchy is intact in the outputs.
+type FolderFilter struct {
+	includes, excludes []*Glob
+}
+
+// NewFolderFilter validates the include and exclude patterns, and initiates new FolderFilter with them
+func NewFolderFilter(includes, excludes []string) (*FolderFilter, error) {
+	var (
+		err error
+		f   = &FolderFilter{
+			includes: make([]*Glob, len(includes)),
+			excludes: make([]*Glob, len(excludes)),
+		}
+	)
+
+	for i, pattern := range includes {
+		if f.includes[i], err = NewGlob(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, pattern := range excludes {
+		if f.excludes[i], err = NewGlob(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func (f *FolderFilter) Apply(ctx context.Context, bookmarks []bookmark.Bookmark) ([]bookmark.Bookmark, error) {
+	var (
+		result []bookmark.Bookmark
+		walk   func(node *bookmark.Node, path []string, included bool) bool
+
+		kept    = make(map[int]bool, len(bookmarks))
+		visited = make(map[int]bool, len(bookmarks))
+
+		logger = logs.FromContext(ctx).With().Int("initial-count", len(bookmarks)).
+			Stringer("filter", FilterName).Logger()
+	)
+
+	// walk decides on the node and its subtree, and reports whether any of them is kept
+	walk = func(node *bookmark.Node, path []string, included bool) bool {
+		visited[node.ID] = true
+
+		if !node.IsPlacesRoot() {
+			path = append(append([]string{}, path...), node.Title)
+
+			if matchAny(f.excludes, path) {
+				// When the record is excluded along with its subtree
+				return false
+			}
+
+			included = included || matchAny(f.includes, path)
+		}
+
+		var keep bool
+		for _, child := range node.Children {
+			// Children are walked regardless of the record, as they may be included on their own
+			keep = walk(child, path, included) || keep
+		}
+
+		if keep = keep || included; keep {
+			// When the record is included, or is an ancestor of the included records
+			kept[node.ID] = true
+		}
+
+		return keep
+	}
+
+	for _, root := range bookmark.Tree(bookmarks) {
+		var path []string
+		if root.Folder != "" {
+			// When the parent of the denormalized record is not in the input
+			path = strings.Split(root.Folder, bookmark.FolderPathDelimiter)
+		}
+
+		walk(root, path, len(f.includes) == 0)
+	}
+
+	for _, b := range bookmarks {
+		if kept[b.ID] || !visited[b.ID] && len(f.includes) == 0 {
+			// The records out of the hierarchy, i.e., in a cycle of parents, have no path to exclude with
+			result = append(result, b)
+		}
+	}
+
+	logger.Info().Int("final-count", len(result)).Msg("Folders filtered")
+
+	return result, nil
+}
+
+func (f *FolderFilter) String() string {
+	return FilterName.String()
+}
+
+// matchAny reports whether the path matches any of the globs
+func matchAny(globs []*Glob, path []string) bool {
+	for _, glob := range globs {
+		if glob.Match(path) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+package folders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+func TestFolderFilter_Apply(t *testing.T) {
+	type args struct {
+		includes []string
+		excludes []string
+	}
+
+	var (
+		ptrStr = util.PtrStr
+
+		root    = bookmark.Bookmark{ID: 1, Parent: 0, GUID: "root________", Type: bookmark.TypeFolder}
+		toolbar = bookmark.Bookmark{ID: 3, Parent: 1, GUID: "toolbar_____", Title: "toolbar", Type: bookmark.TypeFolder}
+		other   = bookmark.Bookmark{ID: 4, Parent: 1, GUID: "unfiled_____", Title: "other", Type: bookmark.TypeFolder}
+		work    = bookmark.Bookmark{ID: 7, Parent: 3, Title: "Work", Type: bookmark.TypeFolder}
+		wiki    = bookmark.Bookmark{ID: 8, Parent: 7, Title: "Wiki", URL: ptrStr("https://wiki.example.com/"),
+			Type: bookmark.TypeBookmark}
+		team   = bookmark.Bookmark{ID: 9, Parent: 7, Title: "Team", Type: bookmark.TypeFolder}
+		board  = bookmark.Bookmark{ID: 10, Parent: 9, Title: "Board", URL: ptrStr("https://board.example.com/")}
+		golang = bookmark.Bookmark{ID: 11, Parent: 3, Title: "Go", URL: ptrStr("https://go.dev/")}
+		attic  = bookmark.Bookmark{ID: 12, Parent: 4, Title: "Archive", Type: bookmark.TypeFolder}
+		old    = bookmark.Bookmark{ID: 13, Parent: 12, Title: "Old", URL: ptrStr("https://old.example.com/")}
+
+		bookmarks = func() []bookmark.Bookmark {
+			return []bookmark.Bookmark{root, toolbar, other, work, wiki, team, board, golang, attic, old}
+		}
+	)
+
+	tests := []struct {
+		name      string
+		args      args
+		bookmarks []bookmark.Bookmark
+		want      []bookmark.Bookmark
+	}{
+		{
+			name:      "No-Globs",
+			args:      args{},
+			bookmarks: bookmarks(),
+			want:      bookmarks(),
+		},
+		{
+			name:      "Include-Subtree",
+			args:      args{includes: []string{"toolbar/Work/**"}},
+			bookmarks: bookmarks(),
+			want:      []bookmark.Bookmark{root, toolbar, work, wiki, team, board},
+		},
+		{
+			name:      "Include-Folder-Keeps-Descendants",
+			args:      args{includes: []string{"toolbar/Work"}},
+			bookmarks: bookmarks(),
+			want:      []bookmark.Bookmark{root, toolbar, work, wiki, team, board},
+		},
+		{
+			name:      "Exclude-Contents",
+			args:      args{excludes: []string{"other/Archive/*"}},
+			bookmarks: bookmarks(),
+			want:      []bookmark.Bookmark{root, toolbar, other, work, wiki, team, board, golang, attic},
+		},
+		{
+			name:      "Exclusion-Takes-Precedence",
+			args:      args{includes: []string{"toolbar/**"}, excludes: []string{"**/Team"}},
+			bookmarks: bookmarks(),
+			want:      []bookmark.Bookmark{root, toolbar, work, wiki, golang},
+		},
+		{
+			name: "Denormalized-Without-Parents",
+			args: args{includes: []string{"toolbar/Work/**"}},
+			bookmarks: []bookmark.Bookmark{
+				{ID: 8, Parent: 7, Title: "Wiki", Folder: "toolbar/Work"},
+				{ID: 10, Parent: 9, Title: "Board", Folder: "toolbar/Work/Team"},
+				{ID: 11, Parent: 3, Title: "Go", Folder: "toolbar"},
+			},
+			want: []bookmark.Bookmark{
+				{ID: 8, Parent: 7, Title: "Wiki", Folder: "toolbar/Work"},
+				{ID: 10, Parent: 9, Title: "Board", Folder: "toolbar/Work/Team"},
+			},
+		},
+		{
+			name:      "Cycle-Of-Parents",
+			args:      args{excludes: []string{"other"}},
+			bookmarks: []bookmark.Bookmark{root, other, {ID: 20, Parent: 21}, {ID: 21, Parent: 20}},
+			want:      []bookmark.Bookmark{root, {ID: 20, Parent: 21}, {ID: 21, Parent: 20}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := logs.SilentLogger(context.Background())
+
+			filter, err := NewFolderFilter(tt.args.includes, tt.args.excludes)
+			assert.NoError(t, err, "Unexpected error from initiation")
+
+			got, err := filter.Apply(ctx, tt.bookmarks)
+			assert.NoError(t, err, "Unexpected error from filter")
+			assert.Equal(t, tt.want, got, "Mismatch of filtered bookmarks")
+		})
+	}
+}
+
+func TestNewFolderFilter(t *testing.T) {
+	_, err := NewFolderFilter([]string{"toolbar/**"}, []string{"[Archive"})
+	assert.ErrorContains(t, err, `invalid folder pattern "[Archive"`, "Mismatch of error")
+}
@@ -0,0 +1,83 @@
+package folders
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+)
+
+// globDelimiter is the delimiter of segments in globs, same as of folder titles in denormalized records
+const globDelimiter = bookmark.FolderPathDelimiter
+
+// anySegments is the segment of globs matching zero or more segments of paths
+const anySegments = `**`
+
+// Glob is a pattern of folder paths, e.g., toolbar/Work/** or other/Archive/*.
+// Every segment of the glob matches a title in the path, with the syntax of path.Match,
+// and ** matches any count of titles, including none.
+type Glob struct {
+	pattern  string
+	segments []string
+}
+
+// NewGlob validates the pattern, and initiates new Glob with it
+func NewGlob(pattern string) (*Glob, error) {
+	trimmed := strings.Trim(pattern, globDelimiter)
+	if trimmed == "" {
+		// When the pattern would match only the places root
+		return nil, fmt.Errorf("empty folder pattern %q", pattern)
+	}
+
+	segments := strings.Split(trimmed, globDelimiter)
+
+	for _, segment := range segments {
+		if segment != anySegments && strings.Contains(segment, anySegments) {
+			// When ** is part of a title, which is ambiguous
+			return nil, fmt.Errorf("invalid folder pattern %q: %s must be a whole segment", pattern, anySegments)
+		}
+
+		if _, err := path.Match(segment, ""); err != nil {
+			// When the segment is malformed, such as with unclosed brackets
+			return nil, fmt.Errorf("invalid folder pattern %q: %v", pattern, err)
+		}
+	}
+
+	return &Glob{pattern: pattern, segments: segments}, nil
+}
+
+// Match reports whether the path, i.e., the titles of folders from the top-level folder, matches the glob
+func (g *Glob) Match(titles []string) bool {
+	return matchSegments(g.segments, titles)
+}
+
+func (g *Glob) String() string {
+	return g.pattern
+}
+
+func matchSegments(segments, titles []string) bool {
+	if len(segments) == 0 {
+		return len(titles) == 0
+	}
+
+	if segments[0] == anySegments {
+		// Try every count of titles for **, from none to all of them
+		for i := 0; i <= len(titles); i++ {
+			if matchSegments(segments[1:], titles[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(titles) == 0 {
+		return false
+	}
+
+	// Error is ignored, as the segments are validated on initiation
+	matched, _ := path.Match(segments[0], titles[0])
+
+	return matched && matchSegments(segments[1:], titles[1:])
+}
@@ -0,0 +1,104 @@
+package folders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlob_Match(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		titles  []string
+		want    bool
+	}{
+		{
+			name:    "Exact-Path",
+			pattern: "toolbar/Work",
+			titles:  []string{"toolbar", "Work"},
+			want:    true,
+		},
+		{
+			name:    "Exact-Path-Of-Ancestor",
+			pattern: "toolbar/Work",
+			titles:  []string{"toolbar"},
+			want:    false,
+		},
+		{
+			name:    "Any-Segments-Matching-None",
+			pattern: "toolbar/Work/**",
+			titles:  []string{"toolbar", "Work"},
+			want:    true,
+		},
+		{
+			name:    "Any-Segments-Matching-Many",
+			pattern: "toolbar/Work/**",
+			titles:  []string{"toolbar", "Work", "Wiki", "Go"},
+			want:    true,
+		},
+		{
+			name:    "Any-Segments-In-Middle",
+			pattern: "**/Archive/*",
+			titles:  []string{"other", "Old", "Archive", "2019"},
+			want:    true,
+		},
+		{
+			name:    "Single-Segment",
+			pattern: "other/Archive/*",
+			titles:  []string{"other", "Archive", "2019", "Go"},
+			want:    false,
+		},
+		{
+			name:    "Character-Class-And-Leading-Delimiter",
+			pattern: "/menu/[A-Z]?",
+			titles:  []string{"menu", "Go"},
+			want:    true,
+		},
+		{
+			name:    "Title-With-Delimiter",
+			pattern: "toolbar/*",
+			titles:  []string{"toolbar", "CI/CD"},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			glob, err := NewGlob(tt.pattern)
+			assert.NoError(t, err, "Unexpected error from glob")
+			assert.Equal(t, tt.want, glob.Match(tt.titles), "Mismatch of glob match")
+		})
+	}
+}
+
+func TestNewGlob(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		wantErr string
+	}{
+		{
+			name:    "Empty",
+			pattern: "/",
+			wantErr: `empty folder pattern "/"`,
+		},
+		{
+			name:    "Any-Segments-In-Title",
+			pattern: "toolbar/Work**",
+			wantErr: "** must be a whole segment",
+		},
+		{
+			name:    "Unclosed-Bracket",
+			pattern: "toolbar/[Work",
+			wantErr: "syntax error in pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGlob(tt.pattern)
+			assert.ErrorContains(t, err, tt.wantErr, "Mismatch of glob error")
+		})
+	}
+}
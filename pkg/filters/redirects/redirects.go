@@ -0,0 +1,67 @@
+package redirects
+
+import (
+	"context"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/filters"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+var FilterName = filters.ToFilterName(constants.RedirectsFilter)
+
+func init() {
+	filters.AllFilterNames = append(filters.AllFilterNames, FilterName)
+}
+
+// RedirectRewriter replaces the URLs of the bookmarks with their redirect targets,
+// when the URLs redirect to live pages only by permanent redirects, i.e., 301 and 308.
+// The temporary redirects, e.g., to login pages, are kept as is.
+type RedirectRewriter struct {
+	checker *links.Checker
+}
+
+// NewRedirectRewriter initiates new RedirectRewriter with the link checker
+func NewRedirectRewriter(checker *links.Checker) *RedirectRewriter {
+	return &RedirectRewriter{checker: checker}
+}
+
+func (r *RedirectRewriter) Apply(ctx context.Context, bookmarks []bookmark.Bookmark) ([]bookmark.Bookmark, error) {
+	var (
+		rewritten int
+		targets   = make(map[string]string)
+
+		logger = logs.FromContext(ctx).With().Int("initial-count", len(bookmarks)).
+			Stringer("filter", FilterName).Logger()
+	)
+
+	for _, link := range r.checker.Check(ctx, bookmarks) {
+		if link.IsPermanentRedirect() {
+			targets[link.URL] = link.RedirectURL
+		}
+	}
+
+	result := make([]bookmark.Bookmark, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		if b.URL != nil {
+			if target, ok := targets[*b.URL]; ok {
+				logger.Info().Str("url", *b.URL).Str("redirect-url", target).Msg("Rewrote permanent redirect")
+
+				b.URL = &target
+				rewritten++
+			}
+		}
+
+		result = append(result, b)
+	}
+
+	logger.Info().Int("rewritten-count", rewritten).Msg("Permanent redirects rewritten")
+
+	return result, nil
+}
+
+func (r *RedirectRewriter) String() string {
+	return FilterName.String()
+}
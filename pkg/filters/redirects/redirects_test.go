@@ -0,0 +1,43 @@
+package redirects
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+func TestRedirectRewriter_Apply(t *testing.T) {
+	var ptrStr = util.PtrStr
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/new-home", http.StatusMovedPermanently)
+		case "/login-wall":
+			http.Redirect(w, r, "/login", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	var (
+		moved     = bookmark.Bookmark{ID: 3, Title: "Moved", URL: ptrStr(server.URL + "/moved")}
+		loginWall = bookmark.Bookmark{ID: 4, Title: "Login Wall", URL: ptrStr(server.URL + "/login-wall")}
+		rewritten = bookmark.Bookmark{ID: 3, Title: "Moved", URL: ptrStr(server.URL + "/new-home")}
+	)
+
+	ctx, _ := logs.SilentLogger(context.Background())
+	rewriter := NewRedirectRewriter(links.NewChecker(ctx, server.Client()))
+
+	got, err := rewriter.Apply(ctx, []bookmark.Bookmark{moved, loginWall})
+
+	assert.NoError(t, err, "Unexpected error from filter")
+	assert.Equal(t, []bookmark.Bookmark{rewritten, loginWall}, got, "Mismatch of rewritten bookmarks")
+	assert.Equal(t, FilterName.String(), rewriter.String(), "Mismatch of filter name")
+}
@@ -0,0 +1,141 @@
+package tags
+
+import (
+	"context"
+	"sort"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/filters"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+var FilterName = filters.ToFilterName(constants.TagsFilter)
+
+func init() {
+	filters.AllFilterNames = append(filters.AllFilterNames, FilterName)
+}
+
+// TagResolver folds the tag records onto the bookmarks they tag.
+// Firefox stores every tag as a folder under the tags root, holding one record
+// per tagged URL, pointing to the same place as the actual bookmark. The tag names
+// are collected in Tags field of the actual bookmarks, and the tags root, tag folders
+// and tag records are dropped from the result.
+type TagResolver struct{}
+
+func (t *TagResolver) Apply(ctx context.Context, bookmarks []bookmark.Bookmark) ([]bookmark.Bookmark, error) {
+	var (
+		tagsRootID int
+		ok         bool
+		tag        string
+		result     []bookmark.Bookmark
+
+		tagFolders  = make(map[int]string)
+		tagsOfPlace = make(map[string][]string)
+
+		logger = logs.FromContext(ctx).With().Int("initial-count", len(bookmarks)).
+			Stringer("filter", FilterName).Logger()
+	)
+
+	if tagsRootID, ok = findTagsRoot(bookmarks); !ok {
+		// When the input has no tags root, such as already filtered records or jsonlz4 backups
+		logger.Info().Msg("Tags root not found, no tags to resolve")
+		return bookmarks, nil
+	}
+
+	// Every folder in tags root is a tag, titled with the tag name
+	for _, b := range bookmarks {
+		if b.Parent == tagsRootID && b.URL == nil {
+			tagFolders[b.ID] = b.Title
+		}
+	}
+
+	// Every record in a tag folder tags the place of the record
+	for _, b := range bookmarks {
+		if tag, ok = tagFolders[b.Parent]; ok && b.URL != nil {
+			tagsOfPlace[placeKey(b)] = append(tagsOfPlace[placeKey(b)], tag)
+		}
+	}
+	logger.Info().Int("tags-count", len(tagFolders)).Int("tagged-places-count", len(tagsOfPlace)).
+		Msg("Tags collected")
+
+	for _, b := range bookmarks {
+		if _, ok = tagFolders[b.ID]; ok || b.ID == tagsRootID {
+			// Tags root and tag folders are dropped
+			continue
+		}
+
+		if _, ok = tagFolders[b.Parent]; ok {
+			// Tag records are dropped
+			continue
+		}
+
+		if b.URL != nil {
+			b.Tags = mergeTags(b.Tags, tagsOfPlace[placeKey(b)])
+		}
+
+		result = append(result, b)
+	}
+
+	logger.Info().Int("final-count", len(result)).Msg("Tags resolved")
+
+	return result, nil
+}
+
+func (t *TagResolver) String() string {
+	return FilterName.String()
+}
+
+// findTagsRoot returns the ID of tags root, identified by its GUID,
+// or by its title in the places root when GUIDs are missing, such as in older exports
+func findTagsRoot(bookmarks []bookmark.Bookmark) (int, bool) {
+	placesRoots := make(map[int]bool)
+
+	for _, b := range bookmarks {
+		if (&bookmark.Node{Bookmark: b}).IsPlacesRoot() {
+			placesRoots[b.ID] = true
+		}
+	}
+
+	for _, b := range bookmarks {
+		if (&bookmark.Node{Bookmark: b}).IsTagsRoot(placesRoots[b.Parent]) {
+			return b.ID, true
+		}
+	}
+
+	return 0, false
+}
+
+// placeKey identifies the place, i.e., the URL record, that a bookmark points to.
+// Place GUID is preferred, while URL is the fallback when place GUID is missing.
+func placeKey(b bookmark.Bookmark) string {
+	if b.PlaceGUID != "" {
+		return b.PlaceGUID
+	}
+
+	return "url:" + *b.URL
+}
+
+// mergeTags merges the tag lists into a sorted list without duplicates.
+// The result is nil when there are no tags.
+func mergeTags(lists ...[]string) []string {
+	var (
+		result []string
+		seen   = make(map[string]bool)
+	)
+
+	for _, list := range lists {
+		for _, tag := range list {
+			if tag == "" || seen[tag] {
+				continue
+			}
+
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,86 @@
+package tags
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+func TestTagResolver_Apply(t *testing.T) {
+	var (
+		ptrStr = util.PtrStr
+		ctx    = context.Background()
+	)
+
+	tests := []struct {
+		name      string
+		bookmarks []bookmark.Bookmark
+		want      []bookmark.Bookmark
+	}{
+		{
+			name: "Tags-By-Place-GUID",
+			bookmarks: []bookmark.Bookmark{
+				{URL: nil, Title: "", ID: 1, Parent: 0, GUID: "root________"},
+				{URL: nil, Title: "menu", ID: 2, Parent: 1, GUID: "menu________"},
+				{URL: nil, Title: "tags", ID: 4, Parent: 1, GUID: "tags________"},
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 6, Parent: 2, PlaceGUID: "p1"},
+				{URL: ptrStr("https://go.dev"), Title: "Go", ID: 7, Parent: 2, PlaceGUID: "p2"},
+				{URL: nil, Title: "vcs", ID: 8, Parent: 4},
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "", ID: 9, Parent: 8, PlaceGUID: "p1"},
+				{URL: nil, Title: "go", ID: 10, Parent: 4},
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "", ID: 11, Parent: 10, PlaceGUID: "p1"},
+				{URL: ptrStr("https://go.dev"), Title: "", ID: 12, Parent: 10, PlaceGUID: "p2"},
+			},
+			want: []bookmark.Bookmark{
+				{URL: nil, Title: "", ID: 1, Parent: 0, GUID: "root________"},
+				{URL: nil, Title: "menu", ID: 2, Parent: 1, GUID: "menu________"},
+				{
+					URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 6, Parent: 2,
+					PlaceGUID: "p1", Tags: []string{"go", "vcs"},
+				},
+				{URL: ptrStr("https://go.dev"), Title: "Go", ID: 7, Parent: 2, PlaceGUID: "p2", Tags: []string{"go"}},
+			},
+		},
+		{
+			name: "Tags-By-URL-Without-GUIDs",
+			bookmarks: []bookmark.Bookmark{
+				{URL: nil, Title: "", ID: 1, Parent: 0},
+				{URL: nil, Title: "tags", ID: 4, Parent: 1},
+				{URL: nil, Title: "GitHub", ID: 5, Parent: 1},
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 6, Parent: 5},
+				{URL: nil, Title: "vcs", ID: 8, Parent: 4},
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "", ID: 9, Parent: 8},
+			},
+			want: []bookmark.Bookmark{
+				{URL: nil, Title: "", ID: 1, Parent: 0},
+				{URL: nil, Title: "GitHub", ID: 5, Parent: 1},
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 6, Parent: 5, Tags: []string{"vcs"}},
+			},
+		},
+		{
+			name: "Without-Tags-Root",
+			bookmarks: []bookmark.Bookmark{
+				{URL: nil, Title: "GitHub", ID: 5, Parent: 1},
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 6, Parent: 5, Tags: []string{"vcs"}},
+			},
+			want: []bookmark.Bookmark{
+				{URL: nil, Title: "GitHub", ID: 5, Parent: 1},
+				{URL: ptrStr("https://github.com/vaguecoder"), Title: "Vague Coder", ID: 6, Parent: 5, Tags: []string{"vcs"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &TagResolver{}
+
+			got, err := resolver.Apply(ctx, tt.bookmarks)
+			assert.NoError(t, err, "Unexpected error from apply")
+			assert.Equal(t, tt.want, got, "Mismatch of resolved bookmarks")
+			assert.Equal(t, constants.TagsFilter.String(), resolver.String(), "Mismatch of filter name string")
+		})
+	}
+}
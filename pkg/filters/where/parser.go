@@ -0,0 +1,424 @@
+package where
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// kind is the type of field values and literals in expressions
+type kind int
+
+const (
+	stringKind kind = iota + 1
+	intKind
+	timeKind
+	tagsKind
+)
+
+func (k kind) String() string {
+	switch k {
+	case stringKind:
+		return "string"
+	case intKind:
+		return "integer"
+	case timeKind:
+		return "date"
+	case tagsKind:
+		return "tags"
+	default:
+		return "unknown"
+	}
+}
+
+// fieldKinds are the kinds of fields of bookmarks, by their names in expressions.
+// The names are same as in JSON exports, along with the short names of dates.
+var fieldKinds = map[string]kind{
+	"url":           stringKind,
+	"title":         stringKind,
+	"folder":        stringKind,
+	"guid":          stringKind,
+	"place-guid":    stringKind,
+	"keyword":       stringKind,
+	"id":            intKind,
+	"parent":        intKind,
+	"type":          intKind,
+	"position":      intKind,
+	"date-added":    timeKind,
+	"added":         timeKind,
+	"last-modified": timeKind,
+	"modified":      timeKind,
+	"tags":          tagsKind,
+}
+
+// operatorKinds are the kinds of fields every comparison operator is valid for
+var operatorKinds = map[string][]kind{
+	"==": {stringKind, intKind, timeKind, tagsKind},
+	"!=": {stringKind, intKind, timeKind, tagsKind},
+	"<":  {stringKind, intKind, timeKind},
+	"<=": {stringKind, intKind, timeKind},
+	">":  {stringKind, intKind, timeKind},
+	">=": {stringKind, intKind, timeKind},
+	"~":  {stringKind, tagsKind},
+	"!~": {stringKind, tagsKind},
+}
+
+// dateFormat is the format of date literals, which are the midnight of the date in UTC
+const dateFormat = `2006-01-02`
+
+// SyntaxError is the error in expression, along with its position, i.e., the column starting at 1
+type SyntaxError struct {
+	Column  int
+	Message string
+}
+
+func (s *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", s.Column, s.Message)
+}
+
+// tokenType is the type of lexical tokens of expressions
+type tokenType int
+
+const (
+	endToken tokenType = iota
+	identToken
+	stringToken
+	intToken
+	timeToken
+	operatorToken // Comparison operators
+	andToken
+	orToken
+	notToken
+	openToken
+	closeToken
+)
+
+// token is a lexical token of expression, along with its offset in expression
+type token struct {
+	typ    tokenType
+	text   string
+	offset int
+}
+
+// lex splits the expression into tokens
+func lex(expr string) ([]token, error) {
+	var (
+		tokens []token
+		runes  = []rune(expr)
+	)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, token{typ: openToken, text: "(", offset: start})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{typ: closeToken, text: ")", offset: start})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, &SyntaxError{Column: start + 1, Message: fmt.Sprintf("expected %c%c", r, r)}
+			}
+
+			typ := andToken
+			if r == '|' {
+				typ = orToken
+			}
+
+			tokens = append(tokens, token{typ: typ, text: string([]rune{r, r}), offset: start})
+			i += 2
+		case r == '=' || r == '!' || r == '<' || r == '>' || r == '~':
+			i++
+			if i < len(runes) && (runes[i] == '=' || r == '!' && runes[i] == '~') {
+				i++
+			}
+
+			text := string(runes[start:i])
+			switch text {
+			case "!":
+				tokens = append(tokens, token{typ: notToken, text: text, offset: start})
+			case "=":
+				return nil, &SyntaxError{Column: start + 1, Message: `unknown operator "=", use "==" for equality`}
+			case "==", "!=", "<", "<=", ">", ">=", "~", "!~":
+				tokens = append(tokens, token{typ: operatorToken, text: text, offset: start})
+			default:
+				return nil, &SyntaxError{Column: start + 1, Message: fmt.Sprintf("unknown operator %q", text)}
+			}
+		case r == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' {
+					// Escaped character, e.g., \" or \\
+					i++
+				}
+			}
+
+			if i >= len(runes) {
+				return nil, &SyntaxError{Column: start + 1, Message: "unterminated string"}
+			}
+
+			i++
+			value, err := strconv.Unquote(string(runes[start:i]))
+			if err != nil {
+				return nil, &SyntaxError{Column: start + 1, Message: fmt.Sprintf("invalid string: %v", err)}
+			}
+
+			tokens = append(tokens, token{typ: stringToken, text: value, offset: start})
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune("-:.TZ+", runes[i])) {
+				i++
+			}
+
+			text := string(runes[start:i])
+			typ := intToken
+			if strings.ContainsAny(text, "-:") {
+				// Dates and timestamps, e.g., 2024-01-01 and 2024-01-01T10:00:00Z
+				typ = timeToken
+			}
+
+			tokens = append(tokens, token{typ: typ, text: text, offset: start})
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '-' ||
+				runes[i] == '_') {
+				i++
+			}
+
+			tokens = append(tokens, token{typ: identToken, text: string(runes[start:i]), offset: start})
+		default:
+			return nil, &SyntaxError{Column: start + 1, Message: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	return append(tokens, token{typ: endToken, offset: len(runes)}), nil
+}
+
+// parser is a recursive descent parser of expressions, with the grammar:
+//
+//	expression := and ( "||" and )*
+//	and        := unary ( "&&" unary )*
+//	unary      := "!" unary | "(" expression ")" | comparison
+//	comparison := field operator literal
+type parser struct {
+	tokens []token
+	next   int
+}
+
+// Parse parses and type checks the expression over bookmark fields
+func Parse(expr string) (Expression, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 1 {
+		return nil, &SyntaxError{Column: 1, Message: "empty expression"}
+	}
+
+	p := &parser{tokens: tokens}
+
+	expression, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if current := p.peek(); current.typ != endToken {
+		return nil, p.errorAt(current, fmt.Sprintf("unexpected %q, expected && or ||", current.text))
+	}
+
+	return expression, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	current := p.tokens[p.next]
+	if current.typ != endToken {
+		p.next++
+	}
+
+	return current
+}
+
+func (p *parser) errorAt(t token, message string) error {
+	return &SyntaxError{Column: t.offset + 1, Message: message}
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().typ == orToken {
+		p.advance()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &orExpression{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().typ == andToken {
+		p.advance()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &andExpression{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	switch current := p.advance(); current.typ {
+	case notToken:
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notExpression{operand: operand}, nil
+	case openToken:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.advance(); closing.typ != closeToken {
+			return nil, p.errorAt(closing, fmt.Sprintf("expected ) to close ( at column %d", current.offset+1))
+		}
+
+		return inner, nil
+	case identToken:
+		return p.parseComparison(current)
+	case endToken:
+		return nil, p.errorAt(current, "unexpected end of expression, expected a comparison")
+	default:
+		return nil, p.errorAt(current, fmt.Sprintf("unexpected %q, expected a field name", current.text))
+	}
+}
+
+func (p *parser) parseComparison(field token) (Expression, error) {
+	fieldKind, ok := fieldKinds[field.text]
+	if !ok {
+		return nil, p.errorAt(field, fmt.Sprintf("unknown field %q (available fields: %s)", field.text,
+			fieldNames()))
+	}
+
+	operator := p.advance()
+	if operator.typ != operatorToken {
+		return nil, p.errorAt(operator, fmt.Sprintf("expected comparison operator after %q", field.text))
+	}
+
+	if !validFor(operator.text, fieldKind) {
+		return nil, p.errorAt(operator, fmt.Sprintf("operator %s isn't valid for %s field %q", operator.text,
+			fieldKind, field.text))
+	}
+
+	literal := p.advance()
+	c := &comparison{field: field.text, operator: operator.text}
+
+	switch {
+	case operator.text == "~" || operator.text == "!~":
+		if literal.typ != stringToken {
+			return nil, p.errorAt(literal, fmt.Sprintf("operator %s requires a regular expression string",
+				operator.text))
+		}
+
+		pattern, err := regexp.Compile(literal.text)
+		if err != nil {
+			return nil, p.errorAt(literal, fmt.Sprintf("invalid regular expression: %v", err))
+		}
+
+		c.pattern = pattern
+	case (fieldKind == stringKind || fieldKind == tagsKind) && literal.typ == stringToken:
+		c.text = literal.text
+	case fieldKind == intKind && literal.typ == intToken:
+		number, err := strconv.Atoi(literal.text)
+		if err != nil {
+			return nil, p.errorAt(literal, fmt.Sprintf("invalid integer %q: %v", literal.text, err))
+		}
+
+		c.number = number
+	case fieldKind == timeKind && literal.typ == timeToken:
+		date, err := parseTime(literal.text)
+		if err != nil {
+			return nil, p.errorAt(literal, err.Error())
+		}
+
+		c.date = date
+	case literal.typ == endToken:
+		return nil, p.errorAt(literal, fmt.Sprintf("unexpected end of expression, expected %s value", fieldKind))
+	default:
+		return nil, p.errorAt(literal, fmt.Sprintf("mismatched types: %s field %q compared with %s", fieldKind,
+			field.text, literalKind(literal)))
+	}
+
+	return c, nil
+}
+
+// validFor reports whether the comparison operator is valid for the kind of field
+func validFor(operator string, fieldKind kind) bool {
+	for _, k := range operatorKinds[operator] {
+		if k == fieldKind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// literalKind returns the kind of literal token for errors
+func literalKind(t token) string {
+	switch t.typ {
+	case stringToken:
+		return stringKind.String()
+	case intToken:
+		return intKind.String()
+	case timeToken:
+		return timeKind.String()
+	case identToken:
+		return fmt.Sprintf("field %q (fields can only be compared with values)", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// parseTime parses the date or RFC3339 timestamp literal
+func parseTime(text string) (time.Time, error) {
+	if date, err := time.Parse(dateFormat, text); err == nil {
+		return date, nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected %s or RFC3339 timestamp", text, dateFormat)
+	}
+
+	return timestamp, nil
+}
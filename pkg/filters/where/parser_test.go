@@ -0,0 +1,101 @@
+package where
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		expr       string
+		wantColumn int
+		wantErr    string
+	}{
+		{
+			name: "Comparisons-Joined",
+			expr: `folder ~ "^toolbar/Work" && url !~ "localhost" && added > 2024-01-01`,
+		},
+		{
+			name: "Grouped-And-Negated",
+			expr: `!(id == 3 || position >= 2) && modified <= 2024-01-01T10:00:00Z && tags == "go"`,
+		},
+		{
+			name:       "Empty",
+			expr:       `  `,
+			wantColumn: 1,
+			wantErr:    "empty expression",
+		},
+		{
+			name:       "Single-Equals",
+			expr:       `title = "Go"`,
+			wantColumn: 7,
+			wantErr:    `use "=="`,
+		},
+		{
+			name:       "Unknown-Field",
+			expr:       `url == "a" && name == "Go"`,
+			wantColumn: 15,
+			wantErr:    `unknown field "name"`,
+		},
+		{
+			name:       "Mismatched-Types",
+			expr:       `added > "yesterday"`,
+			wantColumn: 9,
+			wantErr:    "mismatched types",
+		},
+		{
+			name:       "Operator-Invalid-For-Kind",
+			expr:       `id ~ "1"`,
+			wantColumn: 4,
+			wantErr:    "isn't valid for integer",
+		},
+		{
+			name:       "Invalid-Regular-Expression",
+			expr:       `title ~ "(go"`,
+			wantColumn: 9,
+			wantErr:    "regular expression",
+		},
+		{
+			name:       "Invalid-Date",
+			expr:       `added > 2024-13-01`,
+			wantColumn: 9,
+			wantErr:    "invalid date",
+		},
+		{
+			name:       "Unclosed-Parenthesis",
+			expr:       `(id == 1`,
+			wantColumn: 9,
+			wantErr:    "expected ) to close (",
+		},
+		{
+			name:       "Unterminated-String",
+			expr:       `title == "Go`,
+			wantColumn: 10,
+			wantErr:    "unterminated string",
+		},
+		{
+			name:       "Dangling-Operator",
+			expr:       `id == 1 &&`,
+			wantColumn: 11,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.expr)
+			if tt.wantColumn == 0 {
+				assert.NoError(t, err, "Unexpected error from parsing")
+				assert.NotNil(t, got, "Expected expression from parsing")
+				return
+			}
+
+			var syntaxErr *SyntaxError
+			if assert.ErrorAs(t, err, &syntaxErr, "Expected syntax error from parsing") {
+				assert.Equal(t, tt.wantColumn, syntaxErr.Column, "Mismatch of error column")
+				assert.Contains(t, syntaxErr.Message, tt.wantErr, "Mismatch of error message")
+			}
+		})
+	}
+}
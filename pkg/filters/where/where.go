@@ -0,0 +1,254 @@
+package where
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/filters"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+var FilterName = filters.ToFilterName(constants.WhereFilter)
+
+func init() {
+	filters.AllFilterNames = append(filters.AllFilterNames, FilterName)
+}
+
+// Expression is a parsed and type checked expression over bookmark fields
+type Expression interface {
+	// Match reports whether the record matches the expression.
+	// The folder is the path of the folder the record is in.
+	Match(b bookmark.Bookmark, folder string) bool
+}
+
+// Filter keeps the records matching the expression, and drops the rest.
+// The expressions compare the fields of records with values, e.g.,
+//
+//	folder ~ "^toolbar/Work" && url !~ "localhost" && added > 2024-01-01
+//
+// The comparisons are joined with && and ||, negated with !, and grouped with parentheses.
+//  1. String fields (url, title, folder, guid, place-guid, keyword) are compared with quoted strings,
+//     by ==, !=, <, <=, >, >= or by regular expression with ~ and !~.
+//  2. Integer fields (id, parent, type, position) are compared with integers.
+//  3. Date fields (date-added or added, last-modified or modified) are compared with dates,
+//     i.e., 2006-01-02 as midnight in UTC, or RFC3339 timestamps.
+//  4. Tags are matched by any of the tags, i.e., tags == "go" matches the records tagged go,
+//     and tags !~ "^work" matches the records with no tag starting with work.
+//
+// The folder of raw records is rebuilt from ID and Parent fields, as it's set only after denormalization.
+// The places root, and the tags root along with the tags, are in no folder, as in merge.
+type Filter struct {
+	expr       string
+	expression Expression
+}
+
+// NewFilter parses the expression, and initiates new Filter with it
+func NewFilter(expr string) (*Filter, error) {
+	expression, err := Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %v", expr, err)
+	}
+
+	return &Filter{expr: expr, expression: expression}, nil
+}
+
+func (f *Filter) Apply(ctx context.Context, bookmarks []bookmark.Bookmark) ([]bookmark.Bookmark, error) {
+	var (
+		result []bookmark.Bookmark
+		paths  = make(map[int]string, len(bookmarks))
+
+		logger = logs.FromContext(ctx).With().Int("initial-count", len(bookmarks)).
+			Stringer("filter", FilterName).Str("expression", f.expr).Logger()
+	)
+
+	// The paths are located the same way as by merge and diff, so that they agree on paths
+	for _, located := range bookmark.Locate(bookmarks) {
+		paths[located.ID] = located.Path
+	}
+
+	for _, b := range bookmarks {
+		folder := b.Folder
+		if folder == "" {
+			// When the record isn't denormalized
+			folder = paths[b.ID]
+		}
+
+		if f.expression.Match(b, folder) {
+			result = append(result, b)
+		}
+	}
+
+	logger.Info().Int("final-count", len(result)).Msg("Expression applied")
+
+	return result, nil
+}
+
+func (f *Filter) String() string {
+	return FilterName.String()
+}
+
+// fieldNames returns the names of fields, sorted
+func fieldNames() string {
+	names := make([]string, 0, len(fieldKinds))
+	for name := range fieldKinds {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// andExpression matches when both the operands match
+type andExpression struct {
+	left, right Expression
+}
+
+func (a *andExpression) Match(b bookmark.Bookmark, folder string) bool {
+	return a.left.Match(b, folder) && a.right.Match(b, folder)
+}
+
+// orExpression matches when either of the operands matches
+type orExpression struct {
+	left, right Expression
+}
+
+func (o *orExpression) Match(b bookmark.Bookmark, folder string) bool {
+	return o.left.Match(b, folder) || o.right.Match(b, folder)
+}
+
+// notExpression matches when the operand doesn't match
+type notExpression struct {
+	operand Expression
+}
+
+func (n *notExpression) Match(b bookmark.Bookmark, folder string) bool {
+	return !n.operand.Match(b, folder)
+}
+
+// comparison compares a field with a value of the field's kind, or matches it with a pattern
+type comparison struct {
+	field    string
+	operator string
+	text     string
+	number   int
+	date     time.Time
+	pattern  *regexp.Regexp
+}
+
+func (c *comparison) Match(b bookmark.Bookmark, folder string) bool {
+	switch c.field {
+	case "url":
+		url := ""
+		if b.URL != nil {
+			url = *b.URL
+		}
+
+		return c.matchString(url)
+	case "title":
+		return c.matchString(b.Title)
+	case "folder":
+		return c.matchString(folder)
+	case "guid":
+		return c.matchString(b.GUID)
+	case "place-guid":
+		return c.matchString(b.PlaceGUID)
+	case "keyword":
+		return c.matchString(b.Keyword)
+	case "id":
+		return c.matchInt(b.ID)
+	case "parent":
+		return c.matchInt(b.Parent)
+	case "type":
+		return c.matchInt(b.Type)
+	case "position":
+		return c.matchInt(b.Position)
+	case "date-added", "added":
+		return c.matchTime(b.DateAdded)
+	case "last-modified", "modified":
+		return c.matchTime(b.LastModified)
+	case "tags":
+		return c.matchTags(b.Tags)
+	default:
+		// Fields are validated at parsing
+		return false
+	}
+}
+
+func (c *comparison) matchString(value string) bool {
+	switch c.operator {
+	case "~":
+		return c.pattern.MatchString(value)
+	case "!~":
+		return !c.pattern.MatchString(value)
+	default:
+		return compare(strings.Compare(value, c.text), c.operator)
+	}
+}
+
+func (c *comparison) matchInt(value int) bool {
+	switch {
+	case value < c.number:
+		return compare(-1, c.operator)
+	case value > c.number:
+		return compare(1, c.operator)
+	default:
+		return compare(0, c.operator)
+	}
+}
+
+func (c *comparison) matchTime(value time.Time) bool {
+	switch {
+	case value.Before(c.date):
+		return compare(-1, c.operator)
+	case value.After(c.date):
+		return compare(1, c.operator)
+	default:
+		return compare(0, c.operator)
+	}
+}
+
+// matchTags matches when any of the tags equals or matches, and the negated operators
+// match when none of the tags does
+func (c *comparison) matchTags(tags []string) bool {
+	var matched bool
+
+	for _, tag := range tags {
+		if c.pattern != nil && c.pattern.MatchString(tag) || c.pattern == nil && tag == c.text {
+			matched = true
+			break
+		}
+	}
+
+	if c.operator == "!=" || c.operator == "!~" {
+		return !matched
+	}
+
+	return matched
+}
+
+// compare reports whether the result of comparison, i.e., -1, 0 or 1, satisfies the operator
+func compare(result int, operator string) bool {
+	switch operator {
+	case "==":
+		return result == 0
+	case "!=":
+		return result != 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,121 @@
+package where
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+func TestFilter_Apply(t *testing.T) {
+	var (
+		ptrStr = util.PtrStr
+		older  = time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+		newer  = time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)
+
+		root    = bookmark.Bookmark{ID: 1, Parent: 0, GUID: "root________", Type: bookmark.TypeFolder}
+		toolbar = bookmark.Bookmark{ID: 3, Parent: 1, GUID: "toolbar_____", Title: "toolbar", Type: bookmark.TypeFolder}
+		work    = bookmark.Bookmark{ID: 7, Parent: 3, Title: "Work", Type: bookmark.TypeFolder}
+		wiki    = bookmark.Bookmark{ID: 8, Parent: 7, Title: "Wiki", URL: ptrStr("https://wiki.example.com/"),
+			Type: bookmark.TypeBookmark, DateAdded: newer, Tags: []string{"work", "docs"}}
+		local = bookmark.Bookmark{ID: 9, Parent: 7, Title: "Dev Server", URL: ptrStr("http://localhost:8080/"),
+			Type: bookmark.TypeBookmark, DateAdded: newer}
+		old = bookmark.Bookmark{ID: 10, Parent: 7, Title: "Old Wiki", URL: ptrStr("https://old.example.com/"),
+			Type: bookmark.TypeBookmark, DateAdded: older, Tags: []string{"archive"}}
+		golang = bookmark.Bookmark{ID: 11, Parent: 3, Title: "Go", URL: ptrStr("https://go.dev/"),
+			Type: bookmark.TypeBookmark, DateAdded: newer, Tags: []string{"go"}}
+
+		tagsRoot  = bookmark.Bookmark{ID: 4, Parent: 1, GUID: "tags________", Title: "tags", Type: bookmark.TypeFolder}
+		tagFolder = bookmark.Bookmark{ID: 12, Parent: 4, Title: "go", Type: bookmark.TypeFolder}
+		tagRecord = bookmark.Bookmark{ID: 13, Parent: 12, URL: ptrStr("https://go.dev/"), Type: bookmark.TypeBookmark}
+
+		raw = func() []bookmark.Bookmark {
+			return []bookmark.Bookmark{root, toolbar, work, wiki, local, old, golang}
+		}
+
+		denormalized = func() []bookmark.Bookmark {
+			var bookmarks []bookmark.Bookmark
+			for _, b := range []bookmark.Bookmark{wiki, local, old} {
+				b.Folder = "toolbar/Work"
+				bookmarks = append(bookmarks, b)
+			}
+
+			golang := golang
+			golang.Folder = "toolbar"
+
+			return append(bookmarks, golang)
+		}
+	)
+
+	tests := []struct {
+		name      string
+		expr      string
+		bookmarks []bookmark.Bookmark
+		want      []bookmark.Bookmark
+	}{
+		{
+			name:      "Folder-URL-And-Date",
+			expr:      `folder ~ "^toolbar/Work" && url !~ "localhost" && added > 2024-01-01`,
+			bookmarks: denormalized(),
+			want:      denormalized()[:1],
+		},
+		{
+			name:      "Folder-Of-Raw-Records",
+			expr:      `folder == "toolbar/Work" && type == 1`,
+			bookmarks: raw(),
+			want:      []bookmark.Bookmark{wiki, local, old},
+		},
+		{
+			name:      "Tags-Root-In-No-Folder",
+			expr:      `folder ~ "^tags"`,
+			bookmarks: append(raw(), tagsRoot, tagFolder, tagRecord),
+			want:      nil,
+		},
+		{
+			name:      "Tags-Equal",
+			expr:      `tags == "docs" || tags == "go"`,
+			bookmarks: raw(),
+			want:      []bookmark.Bookmark{wiki, golang},
+		},
+		{
+			name:      "Tags-Not-Matching",
+			expr:      `type == 1 && tags !~ "^(work|go)$"`,
+			bookmarks: raw(),
+			want:      []bookmark.Bookmark{local, old},
+		},
+		{
+			name:      "Negated-Group",
+			expr:      `!(title < "H" || id >= 10) && parent != 0`,
+			bookmarks: raw(),
+			want:      []bookmark.Bookmark{toolbar, work, wiki},
+		},
+		{
+			name:      "No-Match",
+			expr:      `modified > 2024-01-01T00:00:00Z`,
+			bookmarks: raw(),
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := logs.SilentLogger(context.Background())
+
+			filter, err := NewFilter(tt.expr)
+			assert.NoError(t, err, "Unexpected error from parsing")
+
+			got, err := filter.Apply(ctx, tt.bookmarks)
+			assert.NoError(t, err, "Unexpected error from filter")
+			assert.Equal(t, tt.want, got, "Mismatch of filtered bookmarks")
+		})
+	}
+}
+
+func TestNewFilter(t *testing.T) {
+	_, err := NewFilter(`url == 1`)
+	assert.ErrorContains(t, err, `invalid expression "url == 1": column 8: mismatched types`, "Mismatch of error")
+}
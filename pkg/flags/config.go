@@ -0,0 +1,429 @@
+package flags
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvPrefix is the prefix of environment variables of flags, e.g., FIREFOX_BACKUPS_STDOUT_FORMAT
+	EnvPrefix = `FIREFOX_BACKUPS_`
+
+	// configDirName is the directory of config file in the config home, i.e., $XDG_CONFIG_HOME or ~/.config
+	configDirName = `firefox-backups`
+
+	// Keys of the list of output files in config file, and of every output file in it
+	configOutputsKey = `outputs`
+	configFormatKey  = `format`
+	configFileKey    = `file`
+)
+
+// configFilenames are the names of config files discovered in the config directory, in order of preference
+var configFilenames = []string{`config.yaml`, `config.yml`, `config.json`, `config.toml`}
+
+// configSections are the sections of config file, along with the flags configurable in them.
+// The flags in blank section are at the top level of the file.
+var configSections = map[string][]constants.Constant[constants.Flag]{
+	"": {constants.StdOutFormatFlag, constants.SilentFlag, constants.FrontMatterFlag,
+		constants.JSONLFieldsFlag},
+	"input": {constants.ModeFlag, constants.HistoryRangeFlag, constants.InputSQLiteFileFlag,
+		constants.InputJSONLZ4FileFlag, constants.ProfileFlag, constants.ProfilesRootFlag, constants.AllProfilesFlag,
+		constants.RawFlag},
+	"filters": {constants.IgnoreDefaultsFlag, constants.DenormalizeFlag, constants.TagsFlag, constants.DedupeFlag,
+		constants.DedupeKeepFlag, constants.DedupeFragmentFlag, constants.DropDeadLinksFlag,
+		constants.RewriteRedirectsFlag, constants.WhereFlag, constants.IncludeFolderFlag, constants.ExcludeFolderFlag},
+	"checks": {constants.CheckWorkersFlag, constants.CheckIntervalFlag, constants.CheckTimeoutFlag},
+}
+
+// resetter is the flag value holding a list, with the flag repeated for each item of the list.
+// The list is reset when the flag is overridden by environment variable.
+type resetter interface {
+	reset()
+}
+
+// configValue is a value of flag in config file, along with the line it's at
+type configValue struct {
+	flag  string
+	value string
+	line  int
+}
+
+// config is the flag values and output files read from config file
+type config struct {
+	filename string
+	values   []configValue
+	outputs  outputs
+}
+
+// EnvName returns the name of environment variable of the flag, e.g., FIREFOX_BACKUPS_STDOUT_FORMAT
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// layer applies the environment variables and the config file onto the flags parsed from args.
+// Flags override the config file, and environment variables override both.
+// The config file is discovered in the config home, when it's not provided as flag or as environment variable.
+func (o *Operator) layer(configFilename *string, outputFiles *outputs) error {
+	var (
+		err error
+		cfg *config
+
+		set = make(map[string]bool)
+	)
+
+	o.flagSet.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	o.flagSet.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(EnvName(f.Name))
+		if !ok || err != nil {
+			return
+		}
+
+		if list, ok := f.Value.(resetter); ok {
+			// When the list be replaced, instead of being appended to
+			list.reset()
+		}
+
+		if setErr := o.flagSet.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q of environment variable %s: %v", value, EnvName(f.Name), setErr)
+		}
+
+		set[f.Name] = true
+	})
+	if err != nil {
+		return err
+	}
+
+	if *configFilename == "" {
+		// When the config file isn't provided, it's optional
+		if *configFilename = discoverConfig(); *configFilename == "" {
+			return nil
+		}
+	}
+
+	if cfg, err = loadConfig(*configFilename, o.flagSet); err != nil {
+		return err
+	}
+
+	for _, v := range cfg.values {
+		if set[v.flag] {
+			// When the flag is overridden by args or by environment variable
+			continue
+		}
+
+		if err = o.flagSet.Set(v.flag, v.value); err != nil {
+			return fmt.Errorf("%s: invalid value %q for %s: %v", cfg.at(v.line), v.value, v.flag, err)
+		}
+	}
+
+	if !set[constants.OutputFiles.String()] {
+		*outputFiles = append(*outputFiles, cfg.outputs...)
+	}
+
+	return nil
+}
+
+// discoverConfig returns the first config file found in the config directory, or blank when there's none
+func discoverConfig() string {
+	home := os.Getenv("XDG_CONFIG_HOME")
+	if home == "" {
+		// Default config home of XDG base directory specification
+		userHome, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+
+		home = filepath.Join(userHome, ".config")
+	}
+
+	for _, name := range configFilenames {
+		filename := filepath.Join(home, configDirName, name)
+		if info, err := os.Stat(filename); err == nil && !info.IsDir() {
+			return filename
+		}
+	}
+
+	return ""
+}
+
+// loadConfig reads the config file, and validates its sections and options against the flags.
+// JSON is read as YAML, which is its superset, and TOML is converted to YAML nodes before validation.
+func loadConfig(filename string, flagSet *flag.FlagSet) (*config, error) {
+	var (
+		document yaml.Node
+		cfg      = &config{filename: filename}
+		ext      = strings.ToLower(filepath.Ext(filename))
+	)
+
+	switch ext {
+	case ".yaml", ".yml", ".json", ".toml":
+		// Supported formats
+	default:
+		return nil, fmt.Errorf("unsupported format %q of config file %s (supported formats: yaml, yml, json, toml)",
+			strings.TrimPrefix(ext, "."), filename)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	if ext == ".toml" {
+		err = decodeTOML(data, &document)
+	} else {
+		err = yaml.Unmarshal(data, &document)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %v", filename, err)
+	}
+
+	if len(document.Content) == 0 {
+		// When the file is empty
+		return cfg, nil
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: config must be a mapping of sections and options", cfg.at(root.Line))
+	}
+
+	for i := 0; i < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		switch _, isSection := configSections[key.Value]; {
+		case key.Value == configOutputsKey:
+			err = cfg.readOutputs(value)
+		case isSection && key.Value != "":
+			if value.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("%s: section %q must be a mapping of options", cfg.at(value.Line), key.Value)
+			}
+
+			for j := 0; j < len(value.Content) && err == nil; j += 2 {
+				err = cfg.readValue(key.Value, value.Content[j], value.Content[j+1], flagSet)
+			}
+		default:
+			err = cfg.readValue("", key, value, flagSet)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// at returns the position of the line in config file, as filename:line.
+// The line is zero when it's unknown, such as in TOML, and the position is the filename alone.
+func (c *config) at(line int) string {
+	if line == 0 {
+		return c.filename
+	}
+
+	return c.filename + ":" + strconv.Itoa(line)
+}
+
+// readValue reads the value of the option in the section, with the lists being read as repeated flags
+func (c *config) readValue(section string, key, value *yaml.Node, flagSet *flag.FlagSet) error {
+	if !inSection(section, key.Value) {
+		if section == "" {
+			return fmt.Errorf("%s: unknown option %q (sections: input, filters, checks, %s)", c.at(key.Line),
+				key.Value, configOutputsKey)
+		}
+
+		return fmt.Errorf("%s: unknown option %q in section %q (options: %s)", c.at(key.Line),
+			key.Value, section, optionNames(section))
+	}
+
+	switch value.Kind {
+	case yaml.ScalarNode:
+		c.values = append(c.values, configValue{flag: key.Value, value: value.Value, line: value.Line})
+	case yaml.SequenceNode:
+		if _, ok := flagSet.Lookup(key.Value).Value.(resetter); !ok {
+			// When the flag takes a single value
+			return fmt.Errorf("%s: option %q takes a single value, not a list", c.at(value.Line), key.Value)
+		}
+
+		for _, item := range value.Content {
+			if item.Kind != yaml.ScalarNode {
+				return fmt.Errorf("%s: items of option %q must be strings", c.at(item.Line), key.Value)
+			}
+
+			c.values = append(c.values, configValue{flag: key.Value, value: item.Value, line: item.Line})
+		}
+	default:
+		return fmt.Errorf("%s: option %q must be a value or a list of values", c.at(value.Line), key.Value)
+	}
+
+	return nil
+}
+
+// readOutputs reads the output files, with format specific options along with format and file of every output
+func (c *config) readOutputs(value *yaml.Node) error {
+	if value.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s: %s must be a list of output files", c.at(value.Line), configOutputsKey)
+	}
+
+	for _, item := range value.Content {
+		var (
+			format, file string
+			options      = make(map[string]string)
+		)
+
+		if item.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s: output file must be a mapping of %s, %s and options", c.at(item.Line),
+				configFormatKey, configFileKey)
+		}
+
+		for i := 0; i < len(item.Content); i += 2 {
+			key, option := item.Content[i], item.Content[i+1]
+			if option.Kind != yaml.ScalarNode {
+				return fmt.Errorf("%s: output option %q must be a value", c.at(option.Line), key.Value)
+			}
+
+			switch key.Value {
+			case configFormatKey:
+				format = option.Value
+			case configFileKey:
+				file = option.Value
+			default:
+				options[key.Value] = option.Value
+			}
+		}
+
+		if format == "" || file == "" {
+			return fmt.Errorf("%s: output file requires both %s and %s", c.at(item.Line), configFormatKey,
+				configFileKey)
+		}
+
+		var output outputs
+		if err := output.Set(format + outputFormatFilenameDelimiter + file); err != nil {
+			return fmt.Errorf("%s: invalid output file: %v", c.at(item.Line), err)
+		}
+
+		if err := output[0].setOptions(options); err != nil {
+			return fmt.Errorf("%s: invalid output file: %v", c.at(item.Line), err)
+		}
+
+		c.outputs = append(c.outputs, output...)
+	}
+
+	return nil
+}
+
+// inSection reports whether the flag is configurable in the section
+func inSection(section, name string) bool {
+	for _, f := range configSections[section] {
+		if f.String() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// optionNames returns the names of options in the section
+func optionNames(section string) string {
+	var names []string
+
+	for _, f := range configSections[section] {
+		names = append(names, f.String())
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// decodeTOML decodes the TOML config into the document of YAML nodes, so that it's validated as YAML config.
+// The keys keep their order in file, while their lines are unknown, as TOML decoder doesn't report them.
+func decodeTOML(data []byte, document *yaml.Node) error {
+	var (
+		values map[string]interface{}
+		order  = make(map[string]int)
+	)
+
+	metadata, err := toml.Decode(string(data), &values)
+	if err != nil {
+		return err
+	}
+
+	for i, key := range metadata.Keys() {
+		if _, ok := order[strings.Join(key, ".")]; !ok {
+			order[strings.Join(key, ".")] = i
+		}
+	}
+
+	*document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{tomlNode(values, "", order)}}
+
+	return nil
+}
+
+// tomlNode converts the decoded TOML value at the key to YAML node.
+// The tables are ordered by the order of keys in file, and the rest of the values are scalars.
+func tomlNode(value interface{}, key string, order map[string]int) *yaml.Node {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		var (
+			node = &yaml.Node{Kind: yaml.MappingNode}
+			keys = make([]string, 0, len(value))
+		)
+
+		for k := range value {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+		sort.SliceStable(keys, func(i, j int) bool {
+			return order[joinTOMLKey(key, keys[i])] < order[joinTOMLKey(key, keys[j])]
+		})
+
+		for _, k := range keys {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k},
+				tomlNode(value[k], joinTOMLKey(key, k), order))
+		}
+
+		return node
+	case []map[string]interface{}:
+		// Array of tables, such as outputs
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range value {
+			node.Content = append(node.Content, tomlNode(item, key, order))
+		}
+
+		return node
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range value {
+			node.Content = append(node.Content, tomlNode(item, key, order))
+		}
+
+		return node
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	case time.Time:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: value.Format(time.RFC3339)}
+	default:
+		// Integers, floats and booleans
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(value)}
+	}
+}
+
+// joinTOMLKey joins the key of table with the key in it, as in the keys of TOML metadata
+func joinTOMLKey(table, key string) string {
+	if table == "" {
+		return key
+	}
+
+	return table + "." + key
+}
@@ -0,0 +1,270 @@
+package flags
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+)
+
+const testConfig = `
+input:
+  input-sqlite-file: backups/places.sqlite
+filters:
+  tags: true
+  include-folder:
+    - toolbar/Work/**
+    - menu/Go
+checks:
+  check-workers: 2
+  check-timeout: 3s
+outputs:
+  - format: csv
+    file: bookmarks.csv
+    header: false
+  - format: json
+    file: bookmarks.json
+`
+
+const testTOMLConfig = `
+[input]
+input-sqlite-file = "backups/places.sqlite"
+
+[filters]
+tags = true
+include-folder = ["toolbar/Work/**", "menu/Go"]
+
+[checks]
+check-workers = 2
+check-timeout = "3s"
+
+[[outputs]]
+format = "csv"
+file = "bookmarks.csv"
+header = false
+
+[[outputs]]
+format = "json"
+file = "bookmarks.json"
+`
+
+func TestOperator_Parse_Config(t *testing.T) {
+	type want struct {
+		sqliteFile     string
+		tags           bool
+		includeFolders folderGlobs
+		workers        int
+		outputs        []OutputFile
+	}
+
+	var (
+		configOutputs = []OutputFile{
+			{Format: constants.CSVFormat, Filename: "bookmarks.csv", Options: map[string]string{"header": "false"}},
+			{Format: constants.JSONFormat, Filename: "bookmarks.json"},
+		}
+
+		// writeConfig writes the config file, and returns its name
+		writeConfig = func(t *testing.T, dir, name, content string) string {
+			filename := filepath.Join(dir, name)
+			assert.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o755), "Unexpected error from creating dir")
+			assert.NoError(t, os.WriteFile(filename, []byte(content), 0o644), "Unexpected error from writing config")
+
+			return filename
+		}
+	)
+
+	tests := []struct {
+		name    string
+		args    func(dir string) []string
+		env     map[string]string
+		files   map[string]string // Files relative to config home
+		want    want
+		wantErr string
+	}{
+		{
+			name: "Config-File",
+			args: func(dir string) []string { return []string{"--config", filepath.Join(dir, "run.yaml")} },
+			files: map[string]string{
+				"run.yaml": testConfig,
+			},
+			want: want{sqliteFile: "backups/places.sqlite", tags: true,
+				includeFolders: folderGlobs{"toolbar/Work/**", "menu/Go"}, workers: 2, outputs: configOutputs},
+		},
+		{
+			name: "TOML-Config-File",
+			args: func(dir string) []string { return []string{"--config", filepath.Join(dir, "run.toml")} },
+			files: map[string]string{
+				"run.toml": testTOMLConfig,
+			},
+			want: want{sqliteFile: "backups/places.sqlite", tags: true,
+				includeFolders: folderGlobs{"toolbar/Work/**", "menu/Go"}, workers: 2, outputs: configOutputs},
+		},
+		{
+			name: "Discovered-TOML-Config-File",
+			args: func(string) []string { return []string{} },
+			files: map[string]string{
+				"firefox-backups/config.toml": "outputs = [{format = \"yaml\", file = \"b.yaml\"}]\n" +
+					"[filters]\ntags = true\n",
+			},
+			want: want{sqliteFile: "places.sqlite", tags: true, includeFolders: folderGlobs{}, workers: 8,
+				outputs: []OutputFile{{Format: constants.YAMLFormat, Filename: "b.yaml"}}},
+		},
+		{
+			name: "Discovered-JSON-Config-File",
+			args: func(string) []string { return []string{} },
+			files: map[string]string{
+				"firefox-backups/config.json": `{"filters": {"tags": true}, "outputs": [{"format": "yaml", "file": "b.yaml"}]}`,
+			},
+			want: want{sqliteFile: "places.sqlite", tags: true, includeFolders: folderGlobs{}, workers: 8,
+				outputs: []OutputFile{{Format: constants.YAMLFormat, Filename: "b.yaml"}}},
+		},
+		{
+			name: "Flags-Override-Config-File",
+			args: func(dir string) []string {
+				return []string{"--config", filepath.Join(dir, "run.yaml"), "--check-workers", "4",
+					"--include-folder", "other/**", "--output-files", "table:bookmarks.txt"}
+			},
+			files: map[string]string{
+				"run.yaml": testConfig,
+			},
+			want: want{sqliteFile: "backups/places.sqlite", tags: true, includeFolders: folderGlobs{"other/**"},
+				workers: 4, outputs: []OutputFile{{Format: constants.TabularFormat, Filename: "bookmarks.txt"}}},
+		},
+		{
+			name: "Environment-Overrides-Flags-And-Config-File",
+			args: func(string) []string { return []string{"--check-workers", "4", "--include-folder", "other/**"} },
+			env: map[string]string{
+				"FIREFOX_BACKUPS_CHECK_WORKERS":  "6",
+				"FIREFOX_BACKUPS_INCLUDE_FOLDER": "mobile/**",
+				"FIREFOX_BACKUPS_TAGS":           "false",
+			},
+			files: map[string]string{
+				"firefox-backups/config.yml": testConfig,
+			},
+			want: want{sqliteFile: "backups/places.sqlite", tags: false, includeFolders: folderGlobs{"mobile/**"},
+				workers: 6, outputs: configOutputs},
+		},
+		{
+			name: "Failure-Unknown-Option",
+			args: func(string) []string { return []string{} },
+			files: map[string]string{
+				"firefox-backups/config.yaml": "filters:\n  tags: true\n  tagz: true\n",
+			},
+			wantErr: `config.yaml:3: unknown option "tagz" in section "filters"`,
+		},
+		{
+			name: "Failure-Invalid-Value",
+			args: func(string) []string { return []string{} },
+			files: map[string]string{
+				"firefox-backups/config.yaml": "checks:\n  check-workers: many\n",
+			},
+			wantErr: `config.yaml:2: invalid value "many" for check-workers`,
+		},
+		{
+			name: "Failure-List-Of-Single-Value",
+			args: func(string) []string { return []string{} },
+			files: map[string]string{
+				"firefox-backups/config.yaml": "input:\n  profile: [a, b]\n",
+			},
+			wantErr: `config.yaml:2: option "profile" takes a single value, not a list`,
+		},
+		{
+			name: "Failure-Unknown-Output-Option",
+			args: func(string) []string { return []string{} },
+			files: map[string]string{
+				"firefox-backups/config.yaml": "outputs:\n  - format: json\n    file: b.json\n    header: false\n",
+			},
+			wantErr: `config.yaml:2: invalid output file: unknown option "header" of json format`,
+		},
+		{
+			name: "Failure-Invalid-Front-Matter-Option",
+			args: func(string) []string { return []string{} },
+			files: map[string]string{
+				"firefox-backups/config.yaml": "outputs:\n  - format: markdown\n    file: b.md\n    front-matter: author\n",
+			},
+			wantErr: `config.yaml:2: invalid output file: invalid value "author" of option "front-matter"`,
+		},
+		{
+			name: "Failure-Invalid-Environment-Variable",
+			args: func(string) []string { return []string{} },
+			env: map[string]string{
+				"FIREFOX_BACKUPS_DEDUPE": "sometimes",
+			},
+			wantErr: `invalid value "sometimes" of environment variable FIREFOX_BACKUPS_DEDUPE`,
+		},
+		{
+			name: "Failure-Unknown-TOML-Option",
+			args: func(dir string) []string { return []string{"--config", filepath.Join(dir, "run.toml")} },
+			files: map[string]string{
+				"run.toml": "[filters]\ntags = true\ntagz = true\n",
+			},
+			wantErr: `run.toml: unknown option "tagz" in section "filters"`,
+		},
+		{
+			name: "Failure-Invalid-TOML-Value",
+			args: func(dir string) []string { return []string{"--config", filepath.Join(dir, "run.toml")} },
+			files: map[string]string{
+				"run.toml": "[checks]\ncheck-workers = \"many\"\n",
+			},
+			wantErr: `run.toml: invalid value "many" for check-workers`,
+		},
+		{
+			name: "Failure-Invalid-TOML",
+			args: func(dir string) []string { return []string{"--config", filepath.Join(dir, "run.toml")} },
+			files: map[string]string{
+				"run.toml": "[filters\ntags = true\n",
+			},
+			wantErr: `failed to parse config file`,
+		},
+		{
+			name: "Failure-Unsupported-Format",
+			args: func(dir string) []string { return []string{"--config", filepath.Join(dir, "run.ini")} },
+			files: map[string]string{
+				"run.ini": "[filters]\ntags = true\n",
+			},
+			wantErr: `unsupported format "ini" of config file`,
+		},
+		{
+			name:    "Failure-Missing-Config-File",
+			args:    func(dir string) []string { return []string{"--config", filepath.Join(dir, "missing.yaml")} },
+			wantErr: "failed to read config file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", dir)
+
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			for name, content := range tt.files {
+				writeConfig(t, dir, name, content)
+			}
+
+			operator := NewOperator(tt.args(dir))
+			operator.flagSet.Init("config", flag.ContinueOnError)
+			operator.flagSet.SetOutput(io.Discard)
+
+			flags, err := operator.Parse()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr, "Expected error from parse")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from parse")
+			assert.Equal(t, tt.want, want{
+				sqliteFile:     flags.SQLiteDBFilename,
+				tags:           flags.FilterTags,
+				includeFolders: flags.IncludeFolders,
+				workers:        flags.CheckWorkers,
+				outputs:        flags.OutputFiles,
+			}, "Mismatch of merged flags")
+		})
+	}
+}
@@ -2,9 +2,11 @@ package flags
 
 import (
 	"fmt"
+	"time"
 
 	pkgEncoding "github.com/vaguecoder/firefox-backups/pkg/encoding"
 	"github.com/vaguecoder/firefox-backups/pkg/filters"
+	"github.com/vaguecoder/firefox-backups/pkg/links"
 	pkgText "github.com/vaguecoder/firefox-backups/pkg/text"
 	"github.com/vaguecoder/firefox-backups/pkg/util"
 )
@@ -16,6 +18,20 @@ const (
 	rawFlagDefaultVal                  = false
 	filterIgnoreDefaultsFlagDefaultVal = false
 	filterDenormalizeFlagDefaultVal    = false
+	filterTagsFlagDefaultVal           = false
+	filterDedupeFlagDefaultVal         = false
+	dedupeKeepFlagDefaultVal           = `first`
+	dedupeFragmentFlagDefaultVal       = false
+	filterDeadLinksFlagDefaultVal      = false
+	filterRedirectsFlagDefaultVal      = false
+	checkWorkersFlagDefaultVal         = links.DefaultWorkers
+	checkIntervalFlagDefaultVal        = time.Second
+	checkTimeoutFlagDefaultVal         = 10 * time.Second
+	modeFlagDefaultVal                 = `bookmarks`
+	listProfilesFlagDefaultVal         = false
+	allProfilesFlagDefaultVal          = false
+	diffStdOutFormatFlagDefaultVal     = `table`
+	conflictPolicyFlagDefaultVal       = `newest`
 )
 
 var (
@@ -25,6 +41,38 @@ var (
 	appendAll  = util.AppendAll
 
 	// Flag descriptions
+	configFlagDesc = description[quotedString](
+		`Config file in YAML, JSON or TOML format, with sections input, filters, checks and outputs.`,
+		"",
+		[]string{
+			`Discovered as config.yaml, config.yml, config.json or config.toml in $XDG_CONFIG_HOME/firefox-backups/`,
+			`by default.`,
+			`Options are named as flags. Flags override the config file, and environment variables override both.`,
+			`Environment variables are the flag names in upper case, with FIREFOX_BACKUPS_ prefix and - as _.`,
+			`Eg. FIREFOX_BACKUPS_STDOUT_FORMAT=json`,
+			`Outputs are a list of format, file and format specific options, i.e., header of csv and table,`,
+			`front-matter of markdown and org, and fields of jsonl.`,
+		},
+	)
+	modeFlagDesc = description[quotedString](
+		`Data to export from places.sqlite.`,
+		modeFlagDefaultVal,
+		[]string{
+			`Available modes: ["bookmarks", "check-links", "history"].`,
+			`History mode exports the browsing history visits, and ignores the bookmark filters.`,
+			`Check-links mode exports the status, redirect target and error of every http and https bookmark,`,
+			`checked by HEAD request, falling back to GET. The bookmark filters are applied ahead of the checks.`,
+		},
+	)
+	historyRangeFlagDesc = description[quotedString](
+		`Range of visit dates in history mode, in format <from>..<to>.`,
+		"",
+		[]string{
+			`Either end is a date (2006-01-02) or a RFC3339 timestamp, and may be blank for open range.`,
+			`Start of range is inclusive. End of range is exclusive for timestamp, and inclusive for date.`,
+			`Eg. 2023-01-01..2023-03-31, 2023-03-01.., ..2023-03-04T11:37:06Z`,
+		},
+	)
 	silentFlagDesc = description(
 		`Discard all the app logs.`,
 		silentFlagDefaultVal,
@@ -35,6 +83,95 @@ var (
 		inputSQLiteFileFlagDefaultVal,
 		nil,
 	)
+	inputJSONLZ4FileFlagDesc = description[quotedString](
+		`Input Firefox bookmarks backup file path (bookmarkbackups/*.jsonlz4).`,
+		"",
+		[]string{`When provided, bookmarks are read from the backup file instead of --input-sqlite-file.`},
+	)
+	profileFlagDesc = description[quotedString](
+		`Name of the Firefox profile to read places.sqlite from.`,
+		"",
+		[]string{
+			`Profiles are discovered from profiles.ini in --profiles-root, and in the native, Flatpak and Snap locations.`,
+			`Can't be combined with --input-sqlite-file or --input-jsonlz4-file.`,
+		},
+	)
+	listProfilesFlagDesc = description(
+		`List the discovered Firefox profiles and exit.`,
+		listProfilesFlagDefaultVal,
+		[]string{`App logs are discarded while listing.`},
+	)
+	inputFileFlagDesc = description[quotedString](
+		`Input file of import command, in format <format>:<filename>.`,
+		"",
+		[]string{
+			fmt.Sprintf("Available formats: [%s], i.e., the exports of this tool, either raw or denormalized.",
+				pkgEncoding.AllDecoders),
+			`The tabular export must have the header.`,
+			`Eg. json:firefox-backups.json`,
+		},
+	)
+	targetSQLiteFileFlagDesc = description[quotedString](
+		`Target places.sqlite file of import command.`,
+		"",
+		[]string{`Firefox must be closed, as the file is written.`},
+	)
+	oldFlagDesc = description[quotedString](
+		`Old snapshot of diff command, in format <format>:<filename>.`,
+		"",
+		[]string{
+			fmt.Sprintf(`Available formats: [sqlite, jsonlz4, %s], i.e., places.sqlite, Firefox's backup, `+
+				`or the exports of this tool.`, pkgEncoding.AllDecoders),
+			`Eg. json:firefox-backups-2023-03-04.json`,
+		},
+	)
+	newFlagDesc = description[quotedString](
+		`New snapshot of diff command, in format <format>:<filename>.`,
+		"",
+		[]string{`Available formats are same as of --old.`, `Eg. sqlite:places.sqlite`},
+	)
+	diffStdOutFormatFlagDesc = description[quotedString](
+		`Stdout format of changes in diff command.`,
+		diffStdOutFormatFlagDefaultVal,
+		[]string{`Available formats: ["json", "table"].`, `App logs are written to stderr.`},
+	)
+	sourceFlagDesc = description[quotedString](
+		`Source of merge command, in format <format>:<filename>. Repeat the flag for each source.`,
+		"",
+		[]string{
+			`Available formats are same as of --old.`,
+			`The sources are ordered, i.e., the first source is preferred with --conflict-policy=prefer-first.`,
+			`Eg. --source sqlite:laptop/places.sqlite --source json:desktop.json`,
+		},
+	)
+	conflictPolicyFlagDesc = description[quotedString](
+		`Policy of merge command for the bookmarks of same URL with different title or folder.`,
+		conflictPolicyFlagDefaultVal,
+		[]string{
+			`Available policies: ["keep-both", "newest", "prefer-first"].`,
+			`newest keeps the most recently modified bookmark, prefer-first keeps the bookmark of the first source,`,
+			`and keep-both keeps all of them. The exact duplicates are always dropped.`,
+		},
+	)
+	importProfileFlagDesc = description[quotedString](
+		`Name of Firefox profile to import into, instead of --target-sqlite-file.`,
+		"",
+		nil,
+	)
+	allProfilesFlagDesc = description(
+		`Back up every discovered Firefox profile in one run.`,
+		allProfilesFlagDefaultVal,
+		[]string{
+			`Every output filename requires {profile} template, so the profiles don't overwrite each other.`,
+			`A profile name found in multiple roots is expanded as its directory name, eg. abc.default-release.`,
+			`A failing profile is reported, and the rest of the profiles are still backed up.`,
+		},
+	)
+	profilesRootFlagDesc = description[quotedString](
+		`Additional directory holding profiles.ini, searched ahead of the standard locations.`,
+		"",
+		nil,
+	)
 	rawFlagDesc = description(
 		"Fetch all bookmarks without filtering.",
 		rawFlagDefaultVal,
@@ -48,10 +185,26 @@ var (
 		`Stdout data format.`,
 		"",
 		appendAll(
-			fmt.Sprintf("Available formats: [%s].", pkgEncoding.AllEncoders),
+			fmt.Sprintf("Available formats: [%s].", pkgEncoding.AllEncoders.Without(pkgEncoding.FileEncoders)),
 			`Empty string "" for no bookmarks on stdout, i.e., to print app logs.`,
+			fmt.Sprintf("Formats written only to files, in --output-files: [%s].", pkgEncoding.FileEncoders),
 		),
 	)
+	frontMatterFlagDesc = description[quotedString](
+		`Fields of front matter written ahead of the bookmarks in markdown and org outputs, delimited by comma.`,
+		"",
+		[]string{
+			`Available fields: ["date", "profile"], i.e., the date of export and the name of the source profile.`,
+			`Overridden by front-matter option of the outputs in config file. Eg. date,profile`,
+		},
+	)
+	jsonlFieldsFlagDesc = description[quotedString](
+		`Fields of bookmarks written in jsonl outputs, in their order, delimited by comma. All the fields when empty.`,
+		"",
+		[]string{
+			`Overridden by fields option of the outputs in config file. Eg. url,title,tags`,
+		},
+	)
 	filterIgnoreDefaultsFlagDesc = description(
 		"Ignore the default mozilla bookmarks from result.",
 		filterIgnoreDefaultsFlagDefaultVal,
@@ -81,5 +234,90 @@ var (
 			}, true, whitespace(6)),
 		),
 	)
+	filterTagsFlagDesc = description(
+		"Resolve Firefox tags onto the tagged bookmarks.",
+		filterTagsFlagDefaultVal,
+		appendAll(
+			`false if --raw is enabled.`,
+			`Tag names are collected in tags field of bookmarks, and the tag folders and tag records are eliminated.`,
+			`Applied ahead of other filters, so the tag folders aren't denormalized as folders.`,
+		),
+	)
+	filterDedupeFlagDesc = description(
+		"Drop the duplicate bookmarks, i.e., of the same page.",
+		filterDedupeFlagDefaultVal,
+		appendAll(
+			`false if --raw is enabled.`,
+			`URLs are equal when they differ only in case of scheme or host, default port, trailing slash`,
+			`or utm_* and other tracking params. Eg. https://go.dev/doc/?utm_source=x is same as HTTPS://go.dev/doc`,
+			`The tags of the dropped duplicates are added to the kept bookmark.`,
+		),
+	)
+	dedupeKeepFlagDesc = description[quotedString](
+		`Bookmark to keep of every group of duplicates with --dedupe.`,
+		dedupeKeepFlagDefaultVal,
+		[]string{
+			`Available policies: ["first", "newest", "report"].`,
+			`newest keeps the most recently modified bookmark, and report keeps all and only logs the groups.`,
+		},
+	)
+	dedupeFragmentFlagDesc = description(
+		"Ignore the fragment of URLs with --dedupe, i.e., the anchor within the page.",
+		dedupeFragmentFlagDefaultVal,
+		[]string{`Eg. https://go.dev/doc#install is same as https://go.dev/doc`},
+	)
+	filterDeadLinksFlagDesc = description(
+		"Drop the bookmarks of dead links, i.e., failing requests, and pages not found (404) or gone (410).",
+		filterDeadLinksFlagDefaultVal,
+		[]string{`false if --raw is enabled.`, `Every link is requested, as in --mode=check-links.`},
+	)
+	filterRedirectsFlagDesc = description(
+		"Replace the URLs of bookmarks with their redirect targets, on permanent redirects (301, 308) to live pages.",
+		filterRedirectsFlagDefaultVal,
+		[]string{`false if --raw is enabled.`, `Every link is requested, as in --mode=check-links.`},
+	)
+	whereFlagDesc = description[quotedString](
+		`Keep only the bookmarks matching the expression over their fields.`,
+		"",
+		[]string{
+			`Fields: url, title, folder, guid, place-guid, keyword (strings), id, parent, type, position (integers),`,
+			`added, modified (dates) and tags. Strings are quoted, and dates are 2006-01-02 or RFC3339 timestamps.`,
+			`Operators: ==, !=, <, <=, >, >=, ~ and !~ (regular expression), joined by && and ||, negated by !.`,
+			`Eg. folder ~ "^toolbar/Work" && url !~ "localhost" && added > 2024-01-01`,
+		},
+	)
+	includeFolderFlagDesc = description[quotedString](
+		`Keep only the bookmarks in the folders matching the glob pattern. Repeat the flag for more patterns.`,
+		"",
+		[]string{
+			`Patterns match the folder paths, as in --denormalize, whether or not it's enabled.`,
+			`* and ? match within a folder title, and ** matches any count of nested folders.`,
+			`A matching folder includes its subtree, along with its ancestor folders to keep the hierarchy.`,
+			`Eg. toolbar/Work/**`,
+		},
+	)
+	excludeFolderFlagDesc = description[quotedString](
+		`Drop the bookmarks in the folders matching the glob pattern. Repeat the flag for more patterns.`,
+		"",
+		[]string{
+			`Patterns are as in --include-folder, and exclusion takes precedence over inclusion.`,
+			`Eg. other/Archive/* drops everything in Archive folder, but the folder itself.`,
+		},
+	)
+	checkWorkersFlagDesc = description(
+		`Count of concurrent requests while checking links.`,
+		checkWorkersFlagDefaultVal,
+		nil,
+	)
+	checkIntervalFlagDesc = description(
+		`Minimum interval between the requests to the same host while checking links.`,
+		checkIntervalFlagDefaultVal,
+		[]string{`Avoids rate limiting by the sites of many bookmarks. 0 for no limit.`},
+	)
+	checkTimeoutFlagDesc = description(
+		`Timeout of every request while checking links, including the redirects.`,
+		checkTimeoutFlagDefaultVal,
+		nil,
+	)
 	outputFilesFlagDesc = description("", &outputs{}, []string{})
 )
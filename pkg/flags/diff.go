@@ -0,0 +1,57 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+)
+
+// DiffFlags are the input flags of diff command
+type DiffFlags struct {
+	Old          Source                                     `json:"old"`
+	New          Source                                     `json:"new"`
+	StdOutFormat constants.Constant[constants.OutputFormat] `json:"stdout-format"`
+	Silent       bool                                       `json:"silent"`
+}
+
+// ParseDiff parses the input flags of diff command, i.e., the args after the command name
+func (o *Operator) ParseDiff() (*DiffFlags, error) {
+	var (
+		err          error
+		stdOutFormat string
+		flags        = DiffFlags{
+			Old:          Source{},
+			New:          Source{},
+			StdOutFormat: "",
+			Silent:       false,
+		}
+	)
+
+	o.flagSet.Var(&flags.Old, constants.OldFlag.String(), oldFlagDesc)
+	o.flagSet.Var(&flags.New, constants.NewFlag.String(), newFlagDesc)
+	o.flagSet.StringVar(&stdOutFormat, constants.StdOutFormatFlag.String(), diffStdOutFormatFlagDefaultVal,
+		diffStdOutFormatFlagDesc)
+	o.flagSet.BoolVar(&flags.Silent, constants.SilentFlag.String(), silentFlagDefaultVal, silentFlagDesc)
+
+	if err = o.flagSet.Parse(o.args); err != nil {
+		// When parsing of input flag arguments failed
+		return nil, fmt.Errorf("failed to parse input flag args of %s command: %v", constants.DiffCommand, err)
+	}
+
+	if flags.Old.Filename == "" || flags.New.Filename == "" {
+		// When either of the snapshots is missing
+		return nil, fmt.Errorf("%s command requires both --%s and --%s", constants.DiffCommand, constants.OldFlag,
+			constants.NewFlag)
+	}
+
+	switch flags.StdOutFormat = constants.Constant[constants.OutputFormat](stdOutFormat); flags.StdOutFormat {
+	case constants.TabularFormat, constants.JSONFormat:
+		// Human-readable table, or machine-readable JSON
+	default:
+		return nil, fmt.Errorf("invalid format %q to --%s flag of %s command (available formats: [%s, %s])",
+			stdOutFormat, constants.StdOutFormatFlag, constants.DiffCommand, constants.JSONFormat,
+			constants.TabularFormat)
+	}
+
+	return &flags, nil
+}
@@ -4,26 +4,61 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/vaguecoder/firefox-backups/pkg/constants"
 	"github.com/vaguecoder/firefox-backups/pkg/encoding"
 	pkgEncoding "github.com/vaguecoder/firefox-backups/pkg/encoding"
 	pkgEncodingCSV "github.com/vaguecoder/firefox-backups/pkg/encoding/csv"
+	pkgEncodingHTML "github.com/vaguecoder/firefox-backups/pkg/encoding/html"
 	pkgEncodingJSON "github.com/vaguecoder/firefox-backups/pkg/encoding/json"
+	pkgEncodingJSONL "github.com/vaguecoder/firefox-backups/pkg/encoding/jsonl"
+	pkgEncodingJSONLZ4 "github.com/vaguecoder/firefox-backups/pkg/encoding/jsonlz4"
+	pkgEncodingMarkdown "github.com/vaguecoder/firefox-backups/pkg/encoding/markdown"
+	pkgEncodingOrg "github.com/vaguecoder/firefox-backups/pkg/encoding/org"
+	pkgEncodingSite "github.com/vaguecoder/firefox-backups/pkg/encoding/site"
 	pkgEncodingTab "github.com/vaguecoder/firefox-backups/pkg/encoding/tabular"
 	pkgEncodingYAML "github.com/vaguecoder/firefox-backups/pkg/encoding/yaml"
+	_ "github.com/vaguecoder/firefox-backups/pkg/filters/dead-links"
+	_ "github.com/vaguecoder/firefox-backups/pkg/filters/dedupe"
 	_ "github.com/vaguecoder/firefox-backups/pkg/filters/denormalize"
 	_ "github.com/vaguecoder/firefox-backups/pkg/filters/ignore-defaults"
+	_ "github.com/vaguecoder/firefox-backups/pkg/filters/redirects"
+	_ "github.com/vaguecoder/firefox-backups/pkg/filters/tags"
+	"github.com/vaguecoder/firefox-backups/pkg/filters/where"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
 )
 
 type Flags struct {
-	SQLiteDBFilename     string           `json:"input-sqlite-file"`
-	RawOutput            bool             `json:"raw"`
-	Silent               bool             `json:"silent"`
-	OutputFiles          outputs          `json:"output-files"`
-	StdOutFormat         encoding.Encoder `json:"stdout-format"`
-	FilterIgnoreDefaults bool             `json:"ignore-defaults"`
-	FilterDenormalize    bool             `json:"denormalize"`
+	Config               string                               `json:"config"`
+	Mode                 constants.Constant[constants.Mode]   `json:"mode"`
+	HistoryRange         history.Range                        `json:"history-range"`
+	SQLiteDBFilename     string                               `json:"input-sqlite-file"`
+	JSONLZ4Filename      string                               `json:"input-jsonlz4-file"`
+	Profile              string                               `json:"profile"`
+	ListProfiles         bool                                 `json:"list-profiles"`
+	ProfilesRoot         string                               `json:"profiles-root"`
+	AllProfiles          bool                                 `json:"all-profiles"`
+	RawOutput            bool                                 `json:"raw"`
+	Silent               bool                                 `json:"silent"`
+	OutputFiles          outputs                              `json:"output-files"`
+	StdOutFormat         encoding.Encoder                     `json:"stdout-format"`
+	FrontMatter          FrontMatterFields                    `json:"front-matter"`
+	JSONLFields          JSONLFields                          `json:"jsonl-fields"`
+	FilterIgnoreDefaults bool                                 `json:"ignore-defaults"`
+	FilterDenormalize    bool                                 `json:"denormalize"`
+	FilterTags           bool                                 `json:"tags"`
+	FilterDedupe         bool                                 `json:"dedupe"`
+	DedupePolicy         constants.Constant[constants.Policy] `json:"dedupe-keep"`
+	DedupeIgnoreFragment bool                                 `json:"dedupe-ignore-fragment"`
+	FilterDeadLinks      bool                                 `json:"drop-dead-links"`
+	FilterRedirects      bool                                 `json:"rewrite-redirects"`
+	Where                string                               `json:"where"`
+	IncludeFolders       folderGlobs                          `json:"include-folder"`
+	ExcludeFolders       folderGlobs                          `json:"exclude-folder"`
+	CheckWorkers         int                                  `json:"check-workers"`
+	CheckHostInterval    time.Duration                        `json:"check-host-interval"`
+	CheckTimeout         time.Duration                        `json:"check-timeout"`
 }
 
 type Operator struct {
@@ -42,28 +77,77 @@ func (o *Operator) Parse() (*Flags, error) {
 	var (
 		err          error
 		stdOutFormat string
+		mode         string
+		dedupeKeep   string
+		historyRange historyRange
 
 		flags = Flags{
+			Config:               "",
+			Mode:                 constants.BookmarksMode,
+			HistoryRange:         history.Range{},
 			SQLiteDBFilename:     "",
+			JSONLZ4Filename:      "",
+			Profile:              "",
+			ListProfiles:         false,
+			ProfilesRoot:         "",
+			AllProfiles:          false,
 			RawOutput:            false,
 			Silent:               false,
 			OutputFiles:          []OutputFile{},
 			StdOutFormat:         nil,
+			FrontMatter:          FrontMatterFields{},
+			JSONLFields:          JSONLFields{},
 			FilterIgnoreDefaults: false,
 			FilterDenormalize:    false,
+			FilterTags:           false,
+			FilterDedupe:         false,
+			DedupePolicy:         constants.KeepFirstPolicy,
+			DedupeIgnoreFragment: false,
+			FilterDeadLinks:      false,
+			FilterRedirects:      false,
+			Where:                "",
+			IncludeFolders:       folderGlobs{},
+			ExcludeFolders:       folderGlobs{},
+			CheckWorkers:         checkWorkersFlagDefaultVal,
+			CheckHostInterval:    checkIntervalFlagDefaultVal,
+			CheckTimeout:         checkTimeoutFlagDefaultVal,
 		}
 		outputFiles = outputs{}
 	)
 
 	// General input flags
+	o.flagSet.StringVar(&flags.Config, constants.ConfigFlag.String(), "", configFlagDesc)
+	o.flagSet.StringVar(&mode, constants.ModeFlag.String(), modeFlagDefaultVal, modeFlagDesc)
+	o.flagSet.Var(&historyRange, constants.HistoryRangeFlag.String(), historyRangeFlagDesc)
 	o.flagSet.StringVar(&flags.SQLiteDBFilename, constants.InputSQLiteFileFlag.String(), "", inputSQLiteFileFlagDesc) // Lazy assignment of default value
+	o.flagSet.StringVar(&flags.JSONLZ4Filename, constants.InputJSONLZ4FileFlag.String(), "", inputJSONLZ4FileFlagDesc)
+	o.flagSet.StringVar(&flags.Profile, constants.ProfileFlag.String(), "", profileFlagDesc)
+	o.flagSet.BoolVar(&flags.ListProfiles, constants.ListProfilesFlag.String(), listProfilesFlagDefaultVal, listProfilesFlagDesc)
+	o.flagSet.BoolVar(&flags.AllProfiles, constants.AllProfilesFlag.String(), allProfilesFlagDefaultVal, allProfilesFlagDesc)
+	o.flagSet.StringVar(&flags.ProfilesRoot, constants.ProfilesRootFlag.String(), "", profilesRootFlagDesc)
 	o.flagSet.BoolVar(&flags.RawOutput, constants.RawFlag.String(), rawFlagDefaultVal, rawFlagDesc)
 	o.flagSet.BoolVar(&flags.Silent, constants.SilentFlag.String(), silentFlagDefaultVal, silentFlagDesc)
 	o.flagSet.StringVar(&stdOutFormat, constants.StdOutFormatFlag.String(), "", stdOutFormatFlagDesc) // Lazy assignment of default value
+	o.flagSet.Var(&flags.FrontMatter, constants.FrontMatterFlag.String(), frontMatterFlagDesc)
+	o.flagSet.Var(&flags.JSONLFields, constants.JSONLFieldsFlag.String(), jsonlFieldsFlagDesc)
 
 	// Filter input flag
 	o.flagSet.BoolVar(&flags.FilterIgnoreDefaults, constants.IgnoreDefaultsFlag.String(), filterIgnoreDefaultsFlagDefaultVal, filterIgnoreDefaultsFlagDesc)
 	o.flagSet.BoolVar(&flags.FilterDenormalize, constants.DenormalizeFilter.String(), filterDenormalizeFlagDefaultVal, filterDenormalizeFlagDesc)
+	o.flagSet.BoolVar(&flags.FilterTags, constants.TagsFlag.String(), filterTagsFlagDefaultVal, filterTagsFlagDesc)
+	o.flagSet.BoolVar(&flags.FilterDedupe, constants.DedupeFlag.String(), filterDedupeFlagDefaultVal, filterDedupeFlagDesc)
+	o.flagSet.StringVar(&dedupeKeep, constants.DedupeKeepFlag.String(), dedupeKeepFlagDefaultVal, dedupeKeepFlagDesc)
+	o.flagSet.BoolVar(&flags.DedupeIgnoreFragment, constants.DedupeFragmentFlag.String(), dedupeFragmentFlagDefaultVal, dedupeFragmentFlagDesc)
+	o.flagSet.BoolVar(&flags.FilterDeadLinks, constants.DropDeadLinksFlag.String(), filterDeadLinksFlagDefaultVal, filterDeadLinksFlagDesc)
+	o.flagSet.BoolVar(&flags.FilterRedirects, constants.RewriteRedirectsFlag.String(), filterRedirectsFlagDefaultVal, filterRedirectsFlagDesc)
+	o.flagSet.StringVar(&flags.Where, constants.WhereFlag.String(), "", whereFlagDesc)
+	o.flagSet.Var(&flags.IncludeFolders, constants.IncludeFolderFlag.String(), includeFolderFlagDesc)
+	o.flagSet.Var(&flags.ExcludeFolders, constants.ExcludeFolderFlag.String(), excludeFolderFlagDesc)
+
+	// Link checker input flags
+	o.flagSet.IntVar(&flags.CheckWorkers, constants.CheckWorkersFlag.String(), checkWorkersFlagDefaultVal, checkWorkersFlagDesc)
+	o.flagSet.DurationVar(&flags.CheckHostInterval, constants.CheckIntervalFlag.String(), checkIntervalFlagDefaultVal, checkIntervalFlagDesc)
+	o.flagSet.DurationVar(&flags.CheckTimeout, constants.CheckTimeoutFlag.String(), checkTimeoutFlagDefaultVal, checkTimeoutFlagDesc)
 
 	// Output file format input flag with custom implementation of flags.Value interface
 	o.flagSet.Var(&outputFiles, constants.OutputFiles.String(), outputFilesFlagDesc)
@@ -73,6 +157,98 @@ func (o *Operator) Parse() (*Flags, error) {
 		return nil, fmt.Errorf("failed to parse input flag args: %v", err)
 	}
 
+	// Config file and environment variables, ahead of the validations of the merged flags
+	if err = o.layer(&flags.Config, &outputFiles); err != nil {
+		return nil, err
+	}
+
+	switch flags.Mode = constants.Constant[constants.Mode](mode); flags.Mode {
+	case constants.BookmarksMode:
+		// Bookmarks mode
+	case constants.CheckLinksMode:
+		// Check-links mode
+	case constants.HistoryMode:
+		// History mode
+		if flags.JSONLZ4Filename != "" {
+			// When input is a bookmarks backup, which doesn't hold history
+			return nil, fmt.Errorf("--%s=%s requires places.sqlite input, not --%s", constants.ModeFlag,
+				constants.HistoryMode, constants.InputJSONLZ4FileFlag)
+		}
+	default:
+		// Unaccepted mode
+		return nil, fmt.Errorf("invalid mode '%s' to --%s flag (available modes: [%s, %s, %s])",
+			mode, constants.ModeFlag, constants.BookmarksMode, constants.CheckLinksMode, constants.HistoryMode)
+	}
+
+	if flags.CheckWorkers < 1 {
+		// When no worker would check the links
+		return nil, fmt.Errorf("--%s requires at least 1 worker, got %d", constants.CheckWorkersFlag,
+			flags.CheckWorkers)
+	}
+
+	if flags.CheckHostInterval < 0 || flags.CheckTimeout <= 0 {
+		// When the durations can't be waited for
+		return nil, fmt.Errorf("--%s can't be negative, and --%s must be positive", constants.CheckIntervalFlag,
+			constants.CheckTimeoutFlag)
+	}
+
+	flags.HistoryRange = history.Range(historyRange)
+
+	switch flags.DedupePolicy = constants.Constant[constants.Policy](dedupeKeep); flags.DedupePolicy {
+	case constants.KeepFirstPolicy, constants.NewestPolicy, constants.ReportPolicy:
+		// Known policies
+	default:
+		return nil, fmt.Errorf("invalid policy '%s' to --%s flag (available policies: [%s, %s, %s])",
+			dedupeKeep, constants.DedupeKeepFlag, constants.KeepFirstPolicy, constants.NewestPolicy,
+			constants.ReportPolicy)
+	}
+
+	if flags.Where != "" {
+		if _, err = where.Parse(flags.Where); err != nil {
+			// When the expression is invalid, it's reported ahead of reading the input
+			return nil, fmt.Errorf("invalid --%s expression: %v", constants.WhereFlag, err)
+		}
+	}
+
+	if flags.Profile != "" && (flags.SQLiteDBFilename != "" || flags.JSONLZ4Filename != "") {
+		// When the input is provided both as profile and as file
+		return nil, fmt.Errorf("--%s can't be combined with --%s or --%s", constants.ProfileFlag,
+			constants.InputSQLiteFileFlag, constants.InputJSONLZ4FileFlag)
+	}
+
+	if flags.AllProfiles && (flags.Profile != "" || flags.SQLiteDBFilename != "" || flags.JSONLZ4Filename != "") {
+		// When the input is provided both as all profiles and as a single input
+		return nil, fmt.Errorf("--%s can't be combined with --%s, --%s or --%s", constants.AllProfilesFlag,
+			constants.ProfileFlag, constants.InputSQLiteFileFlag, constants.InputJSONLZ4FileFlag)
+	}
+
+	for _, output := range outputFiles {
+		if !output.HasTemplate(ProfileTemplate) {
+			continue
+		}
+
+		if !flags.AllProfiles && flags.Profile == "" {
+			// When profile name is required in filename, but input isn't a profile
+			return nil, fmt.Errorf("%s in --%s requires --%s or --%s", ProfileTemplate, constants.OutputFiles,
+				constants.ProfileFlag, constants.AllProfilesFlag)
+		}
+	}
+
+	if flags.AllProfiles {
+		for _, output := range outputFiles {
+			if !output.HasTemplate(ProfileTemplate) {
+				// When every profile would overwrite the same file
+				return nil, fmt.Errorf("filename %q in --%s requires %s with --%s", output.Filename,
+					constants.OutputFiles, ProfileTemplate, constants.AllProfilesFlag)
+			}
+		}
+	}
+
+	if flags.ListProfiles {
+		// When the profiles be listed on stdout, the app logs should be suppressed
+		flags.Silent = true
+	}
+
 	if flags.SQLiteDBFilename == "" {
 		// Input filename is missing; assign default
 		// Lazy assignment to avoid printing of default value in default format
@@ -81,32 +257,76 @@ func (o *Operator) Parse() (*Flags, error) {
 
 	if stdOutFormat != "" {
 		// When flag --stdout-format is provided with a non-empty string
-		switch constants.Constant[constants.OutputFormat](stdOutFormat) {
-		case constants.CSVFormat:
-			// CSV format
-			flags.StdOutFormat = pkgEncodingCSV.NewEncoder(os.Stdout, true)
-		case constants.JSONFormat:
-			// JSON format
-			flags.StdOutFormat = pkgEncodingJSON.NewEncoder(os.Stdout)
-		case constants.TabularFormat:
-			// Table format
-			flags.StdOutFormat = pkgEncodingTab.NewEncoder(os.Stdout, true)
-		case constants.YAMLFormat:
-			// YAML format
-			flags.StdOutFormat = pkgEncodingYAML.NewEncoder(os.Stdout)
-		default:
-			// Unaccepted output format to stdout-flag
-			return nil, fmt.Errorf("invalid format '%s' to --%s flag (available formats: [%s])",
-				stdOutFormat, constants.StdOutFormatFlag, pkgEncoding.AllEncoders)
+		if flags.StdOutFormat, err = stdOutEncoder(stdOutFormat, flags.JSONLFields); err != nil {
+			return nil, err
+		}
+
+		if err = validateModeFormat(flags.Mode, constants.Constant[constants.OutputFormat](stdOutFormat)); err != nil {
+			// When the format can't hold the data of mode
+			return nil, fmt.Errorf("invalid --%s: %v", constants.StdOutFormatFlag, err)
 		}
 
 		// When the resultant bookmarks be printed on stdout, the app logs should be suppressed
 		flags.Silent = true
 	}
 
+	for _, output := range outputFiles {
+		if err = validateModeFormat(flags.Mode, output.Format); err != nil {
+			// When the output can't hold the data of mode, it's reported ahead of creating the file
+			return nil, fmt.Errorf("invalid output %q in --%s: %v", output.Filename, constants.OutputFiles, err)
+		}
+	}
+
 	// Append output format-filename sets after validation.
 	// It is validated and formatted at flags.Value interface level.
 	flags.OutputFiles = append(flags.OutputFiles, outputFiles...)
 
 	return &flags, nil
 }
+
+// stdOutEncoder returns the encoder of the format, writing to stdout
+func stdOutEncoder(stdOutFormat string, jsonlFields JSONLFields) (encoding.Encoder, error) {
+	switch constants.Constant[constants.OutputFormat](stdOutFormat) {
+	case constants.CSVFormat:
+		// CSV format
+		return pkgEncodingCSV.NewEncoder(os.Stdout, true), nil
+	case constants.JSONFormat:
+		// JSON format
+		return pkgEncodingJSON.NewEncoder(os.Stdout), nil
+	case constants.TabularFormat:
+		// Table format
+		return pkgEncodingTab.NewEncoder(os.Stdout, true), nil
+	case constants.YAMLFormat:
+		// YAML format
+		return pkgEncodingYAML.NewEncoder(os.Stdout), nil
+	case constants.HTMLFormat:
+		// HTML format
+		return pkgEncodingHTML.NewEncoder(os.Stdout), nil
+	case constants.JSONLZ4Format:
+		// Firefox's jsonlz4 backup format
+		return pkgEncodingJSONLZ4.NewEncoder(os.Stdout), nil
+	case constants.JSONTreeFormat:
+		// JSON format of folder hierarchy
+		return pkgEncodingJSON.NewTreeEncoder(os.Stdout), nil
+	case constants.YAMLTreeFormat:
+		// YAML format of folder hierarchy
+		return pkgEncodingYAML.NewTreeEncoder(os.Stdout), nil
+	case constants.MarkdownFormat:
+		// Markdown format of notes
+		return pkgEncodingMarkdown.NewEncoder(os.Stdout), nil
+	case constants.OrgFormat:
+		// Org-mode format of notes
+		return pkgEncodingOrg.NewEncoder(os.Stdout), nil
+	case constants.SiteFormat:
+		// Self-contained HTML page
+		return pkgEncodingSite.NewEncoder(os.Stdout), nil
+	case constants.JSONLFormat:
+		// JSON Lines format
+		return pkgEncodingJSONL.NewEncoder(os.Stdout, jsonlFields), nil
+	default:
+		// Unaccepted output format to stdout-flag
+		// Formats written only to files, i.e., sqlite-export, aren't available on stdout
+		return nil, fmt.Errorf("invalid format '%s' to --%s flag (available formats: [%s])",
+			stdOutFormat, constants.StdOutFormatFlag, pkgEncoding.AllEncoders.Without(pkgEncoding.FileEncoders))
+	}
+}
@@ -1 +1,539 @@
 package flags
+
+import (
+	"flag"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	pkgEncodingJSON "github.com/vaguecoder/firefox-backups/pkg/encoding/json"
+)
+
+func TestOperator_Parse_Profiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{
+			name:    "All-Profiles-With-Profile-Template",
+			args:    []string{"--all-profiles", "--output-files", "json:{profile}-{date}.json"},
+			wantErr: false,
+		},
+		{
+			name:    "Profile-With-Profile-Template",
+			args:    []string{"--profile", "default-release", "--output-files", "json:{profile}.json"},
+			wantErr: false,
+		},
+		{
+			name:    "Date-Template-Without-Profile",
+			args:    []string{"--output-files", "json:bookmarks-{date}.json"},
+			wantErr: false,
+		},
+		{
+			name:    "Failure-All-Profiles-Without-Profile-Template",
+			args:    []string{"--all-profiles", "--output-files", "json:bookmarks.json"},
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Profile-Template-Without-Profile",
+			args:    []string{"--output-files", "json:{profile}.json"},
+			wantErr: true,
+		},
+		{
+			name:    "Failure-All-Profiles-With-Profile",
+			args:    []string{"--all-profiles", "--profile", "default-release"},
+			wantErr: true,
+		},
+		{
+			name:    "Failure-All-Profiles-With-Input-File",
+			args:    []string{"--all-profiles", "--input-sqlite-file", "places.sqlite"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := NewOperator(tt.args).Parse()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from parse")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from parse")
+			assert.NotNil(t, flags, "Expected flags from parse")
+		})
+	}
+}
+
+func TestOperator_Parse_Dedupe(t *testing.T) {
+	type want struct {
+		filterDedupe   bool
+		policy         constants.Constant[constants.Policy]
+		ignoreFragment bool
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    want
+		wantErr bool
+	}{
+		{
+			name:    "Default-Policy",
+			args:    []string{"--dedupe"},
+			want:    want{filterDedupe: true, policy: "first"},
+			wantErr: false,
+		},
+		{
+			name:    "Report-Ignoring-Fragment",
+			args:    []string{"--dedupe", "--dedupe-keep", "report", "--dedupe-ignore-fragment"},
+			want:    want{filterDedupe: true, policy: "report", ignoreFragment: true},
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Unknown-Policy",
+			args:    []string{"--dedupe", "--dedupe-keep", "oldest"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := NewOperator(tt.args)
+			operator.flagSet.Init("dedupe", flag.ContinueOnError)
+			operator.flagSet.SetOutput(io.Discard)
+
+			flags, err := operator.Parse()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from parse")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from parse")
+			assert.Equal(t, tt.want, want{
+				filterDedupe:   flags.FilterDedupe,
+				policy:         flags.DedupePolicy,
+				ignoreFragment: flags.DedupeIgnoreFragment,
+			}, "Mismatch of dedupe flags")
+		})
+	}
+}
+
+func TestOperator_Parse_CheckLinks(t *testing.T) {
+	type want struct {
+		mode            constants.Constant[constants.Mode]
+		filterDeadLinks bool
+		filterRedirects bool
+		workers         int
+		interval        time.Duration
+		timeout         time.Duration
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    want
+		wantErr bool
+	}{
+		{
+			name:    "Defaults",
+			args:    []string{"--mode", "check-links"},
+			want:    want{mode: "check-links", workers: 8, interval: time.Second, timeout: 10 * time.Second},
+			wantErr: false,
+		},
+		{
+			name: "Filters-With-Checker-Flags",
+			args: []string{"--drop-dead-links", "--rewrite-redirects", "--check-workers", "2",
+				"--check-host-interval", "0", "--check-timeout", "3s"},
+			want: want{mode: "bookmarks", filterDeadLinks: true, filterRedirects: true, workers: 2,
+				interval: 0, timeout: 3 * time.Second},
+			wantErr: false,
+		},
+		{
+			name:    "Failure-No-Workers",
+			args:    []string{"--mode", "check-links", "--check-workers", "0"},
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Zero-Timeout",
+			args:    []string{"--mode", "check-links", "--check-timeout", "0s"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := NewOperator(tt.args)
+			operator.flagSet.Init("check-links", flag.ContinueOnError)
+			operator.flagSet.SetOutput(io.Discard)
+
+			flags, err := operator.Parse()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from parse")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from parse")
+			assert.Equal(t, tt.want, want{
+				mode:            flags.Mode,
+				filterDeadLinks: flags.FilterDeadLinks,
+				filterRedirects: flags.FilterRedirects,
+				workers:         flags.CheckWorkers,
+				interval:        flags.CheckHostInterval,
+				timeout:         flags.CheckTimeout,
+			}, "Mismatch of check-links flags")
+		})
+	}
+}
+
+func TestOperator_Parse_ModeFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "History-Supported-Formats",
+			args:    []string{"--mode", "history", "--stdout-format", "csv", "--output-files", "yaml:visits.yaml"},
+			wantErr: "",
+		},
+		{
+			name:    "Bookmarks-All-Formats",
+			args:    []string{"--stdout-format", "markdown", "--output-files", "html:bookmarks.html"},
+			wantErr: "",
+		},
+		{
+			name:    "Failure-History-Unsupported-Stdout-Format",
+			args:    []string{"--mode", "history", "--stdout-format", "html"},
+			wantErr: `invalid --stdout-format: format "html" isn't supported by --mode=history`,
+		},
+		{
+			name:    "Failure-Check-Links-Unsupported-Stdout-Format",
+			args:    []string{"--mode", "check-links", "--stdout-format", "jsonl"},
+			wantErr: `invalid --stdout-format: format "jsonl" isn't supported by --mode=check-links`,
+		},
+		{
+			name:    "Failure-History-Unsupported-Output-Format",
+			args:    []string{"--mode", "history", "--output-files", "json:visits.json,org:visits.org"},
+			wantErr: `invalid output "visits.org" in --output-files: format "org" isn't supported by --mode=history`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := NewOperator(tt.args)
+			operator.flagSet.Init("mode-formats", flag.ContinueOnError)
+			operator.flagSet.SetOutput(io.Discard)
+
+			_, err := operator.Parse()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr, "Expected error from parse")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from parse")
+		})
+	}
+}
+
+func TestOperator_Parse_Where(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Valid-Expression",
+			args: []string{"--where", `folder ~ "^toolbar/Work" && added > 2024-01-01`},
+			want: `folder ~ "^toolbar/Work" && added > 2024-01-01`,
+		},
+		{
+			name: "No-Expression",
+			args: []string{},
+			want: "",
+		},
+		{
+			name:    "Failure-Mismatched-Types",
+			args:    []string{"--where", `added > "yesterday"`},
+			wantErr: `invalid --where expression: column 9: mismatched types`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := NewOperator(tt.args)
+			operator.flagSet.Init("where", flag.ContinueOnError)
+			operator.flagSet.SetOutput(io.Discard)
+
+			flags, err := operator.Parse()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr, "Expected error from parse")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from parse")
+			assert.Equal(t, tt.want, flags.Where, "Mismatch of where expression")
+		})
+	}
+}
+
+func TestOperator_Parse_Folders(t *testing.T) {
+	type want struct {
+		includes folderGlobs
+		excludes folderGlobs
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    want
+		wantErr string
+	}{
+		{
+			name: "Repeated-Patterns",
+			args: []string{"--include-folder", "toolbar/Work/**", "--include-folder", "menu/Go",
+				"--exclude-folder", "other/Archive/*"},
+			want: want{
+				includes: folderGlobs{"toolbar/Work/**", "menu/Go"},
+				excludes: folderGlobs{"other/Archive/*"},
+			},
+		},
+		{
+			name: "No-Patterns",
+			args: []string{},
+			want: want{includes: folderGlobs{}, excludes: folderGlobs{}},
+		},
+		{
+			name:    "Failure-Invalid-Pattern",
+			args:    []string{"--exclude-folder", "other/[Archive"},
+			wantErr: `invalid folder pattern "other/[Archive"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := NewOperator(tt.args)
+			operator.flagSet.Init("folders", flag.ContinueOnError)
+			operator.flagSet.SetOutput(io.Discard)
+
+			flags, err := operator.Parse()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr, "Expected error from parse")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from parse")
+			assert.Equal(t, tt.want, want{includes: flags.IncludeFolders, excludes: flags.ExcludeFolders},
+				"Mismatch of folder patterns")
+		})
+	}
+}
+
+func TestOperator_ParseImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    InputFile
+		wantErr bool
+	}{
+		{
+			name:    "Target-File",
+			args:    []string{"--input-file", "json:firefox-backups.json", "--target-sqlite-file", "places.sqlite"},
+			want:    InputFile{Format: "json", Filename: "firefox-backups.json"},
+			wantErr: false,
+		},
+		{
+			name:    "Target-Profile-Filename-With-Delimiter",
+			args:    []string{"--input-file", "yaml:backups:2023.yaml", "--profile", "default-release"},
+			want:    InputFile{Format: "yaml", Filename: "backups:2023.yaml"},
+			wantErr: false,
+		},
+		{
+			name:    "Target-File-Tabular",
+			args:    []string{"--input-file", "table:firefox-backups.txt", "--target-sqlite-file", "places.sqlite"},
+			want:    InputFile{Format: "table", Filename: "firefox-backups.txt"},
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Missing-Input-File",
+			args:    []string{"--target-sqlite-file", "places.sqlite"},
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Missing-Target",
+			args:    []string{"--input-file", "json:firefox-backups.json"},
+			wantErr: true,
+		},
+		{
+			name: "Failure-Both-Targets",
+			args: []string{"--input-file", "json:firefox-backups.json", "--target-sqlite-file", "places.sqlite",
+				"--profile", "default-release"},
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Unsupported-Format",
+			args:    []string{"--input-file", "html:bookmarks.html", "--target-sqlite-file", "places.sqlite"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := NewOperator(tt.args)
+			operator.flagSet.Init("import", flag.ContinueOnError)
+			operator.flagSet.SetOutput(io.Discard)
+
+			flags, err := operator.ParseImport()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from parse")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from parse")
+			assert.Equal(t, tt.want, flags.InputFile, "Mismatch of input file")
+		})
+	}
+}
+
+func TestOperator_ParseDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    DiffFlags
+		wantErr bool
+	}{
+		{
+			name: "SQLite-Against-Export",
+			args: []string{"--old", "json:backups:2023.json", "--new", "sqlite:places.sqlite"},
+			want: DiffFlags{
+				Old:          Source{Format: "json", Filename: "backups:2023.json"},
+				New:          Source{Format: "sqlite", Filename: "places.sqlite"},
+				StdOutFormat: "table",
+			},
+			wantErr: false,
+		},
+		{
+			name: "JSON-Output",
+			args: []string{"--old", "jsonlz4:bookmarks.jsonlz4", "--new", "table:backups.txt", "--stdout-format", "json"},
+			want: DiffFlags{
+				Old:          Source{Format: "jsonlz4", Filename: "bookmarks.jsonlz4"},
+				New:          Source{Format: "table", Filename: "backups.txt"},
+				StdOutFormat: "json",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Missing-New",
+			args:    []string{"--old", "sqlite:places.sqlite"},
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Unsupported-Source-Format",
+			args:    []string{"--old", "html:bookmarks.html", "--new", "sqlite:places.sqlite"},
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Missing-Source-Format",
+			args:    []string{"--old", "places.sqlite", "--new", "sqlite:places.sqlite"},
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Unsupported-Output-Format",
+			args:    []string{"--old", "csv:old.csv", "--new", "csv:new.csv", "--stdout-format", "yaml"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := NewOperator(tt.args)
+			operator.flagSet.Init("diff", flag.ContinueOnError)
+			operator.flagSet.SetOutput(io.Discard)
+
+			flags, err := operator.ParseDiff()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from parse")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from parse")
+			assert.Equal(t, tt.want, *flags, "Mismatch of flags")
+		})
+	}
+}
+
+func TestOperator_ParseMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    MergeFlags
+		wantErr bool
+	}{
+		{
+			name: "Default-Policy",
+			args: []string{"--source", "sqlite:places.sqlite", "--source", "json:desktop.json",
+				"--output-files", "json:merged-{date}.json"},
+			want: MergeFlags{
+				Sources: sources{
+					{Format: "sqlite", Filename: "places.sqlite"},
+					{Format: "json", Filename: "desktop.json"},
+				},
+				ConflictPolicy: "newest",
+				OutputFiles:    outputs{{Format: "json", Filename: "merged-{date}.json"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Prefer-First-On-Stdout",
+			args: []string{"--source", "yaml:laptop.yaml", "--source", "jsonlz4:bookmarks.jsonlz4",
+				"--source", "csv:desktop.csv", "--conflict-policy", "prefer-first", "--stdout-format", "json"},
+			want: MergeFlags{
+				Sources: sources{
+					{Format: "yaml", Filename: "laptop.yaml"},
+					{Format: "jsonlz4", Filename: "bookmarks.jsonlz4"},
+					{Format: "csv", Filename: "desktop.csv"},
+				},
+				ConflictPolicy: "prefer-first",
+				OutputFiles:    outputs{},
+				StdOutFormat:   pkgEncodingJSON.NewEncoder(os.Stdout),
+				Silent:         true,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Single-Source",
+			args:    []string{"--source", "sqlite:places.sqlite", "--output-files", "json:merged.json"},
+			wantErr: true,
+		},
+		{
+			name: "Failure-Unknown-Policy",
+			args: []string{"--source", "sqlite:places.sqlite", "--source", "json:desktop.json",
+				"--conflict-policy", "oldest", "--output-files", "json:merged.json"},
+			wantErr: true,
+		},
+		{
+			name: "Failure-Profile-Template",
+			args: []string{"--source", "sqlite:places.sqlite", "--source", "json:desktop.json",
+				"--output-files", "json:{profile}.json"},
+			wantErr: true,
+		},
+		{
+			name:    "Failure-No-Output",
+			args:    []string{"--source", "sqlite:places.sqlite", "--source", "json:desktop.json"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := NewOperator(tt.args)
+			operator.flagSet.Init("merge", flag.ContinueOnError)
+			operator.flagSet.SetOutput(io.Discard)
+
+			flags, err := operator.ParseMerge()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from parse")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from parse")
+			assert.Equal(t, tt.want, *flags, "Mismatch of flags")
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package flags
+
+import (
+	"strings"
+
+	"github.com/vaguecoder/firefox-backups/pkg/filters/folders"
+)
+
+// folderGlobs is the list of glob patterns of folder paths, with the flag repeated for each pattern
+type folderGlobs []string
+
+func (f *folderGlobs) String() string {
+	return strings.Join(*f, " ")
+}
+
+func (f *folderGlobs) reset() {
+	*f = nil
+}
+
+func (f *folderGlobs) Set(value string) error {
+	if _, err := folders.NewGlob(value); err != nil {
+		return err
+	}
+
+	*f = append(*f, value)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// Fields of front matter of markdown and org outputs
+	frontMatterDate    = `date`
+	frontMatterProfile = `profile`
+
+	frontMatterDelimiter = `,`
+)
+
+// FrontMatterFields are the fields of front matter written in markdown and org outputs,
+// in format <field>[,<field>...], e.g., date,profile
+type FrontMatterFields struct {
+	Date    bool `json:"date"`    // Date of export
+	Profile bool `json:"profile"` // Name of the source profile
+}
+
+func (f *FrontMatterFields) String() string {
+	var fields []string
+
+	if f.Date {
+		fields = append(fields, frontMatterDate)
+	}
+
+	if f.Profile {
+		fields = append(fields, frontMatterProfile)
+	}
+
+	return strings.Join(fields, frontMatterDelimiter)
+}
+
+func (f *FrontMatterFields) Set(value string) error {
+	var fields FrontMatterFields
+
+	for _, field := range strings.Split(value, frontMatterDelimiter) {
+		switch strings.TrimSpace(field) {
+		case frontMatterDate:
+			fields.Date = true
+		case frontMatterProfile:
+			fields.Profile = true
+		case "":
+			// Blank for no front matter
+		default:
+			return fmt.Errorf("invalid front matter field %q (available fields: [%s, %s])", field,
+				frontMatterDate, frontMatterProfile)
+		}
+	}
+
+	*f = fields
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrontMatterFields_Set(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       FrontMatterFields
+		wantString string
+		wantErr    bool
+	}{
+		{
+			name:       "All-Fields",
+			input:      "profile, date",
+			want:       FrontMatterFields{Date: true, Profile: true},
+			wantString: "date,profile",
+			wantErr:    false,
+		},
+		{
+			name:       "Date-Only",
+			input:      "date",
+			want:       FrontMatterFields{Date: true},
+			wantString: "date",
+			wantErr:    false,
+		},
+		{
+			name:       "No-Fields",
+			input:      "",
+			want:       FrontMatterFields{},
+			wantString: "",
+			wantErr:    false,
+		},
+		{
+			name:    "Failure-Invalid-Field",
+			input:   "date,author",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f FrontMatterFields
+
+			err := f.Set(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from set")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from set")
+			assert.Equal(t, tt.want, f, "Mismatch of front matter fields")
+			assert.Equal(t, tt.wantString, f.String(), "Mismatch of front matter string")
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	pkgConstants "github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/history"
+)
+
+const (
+	historyRangeDelimiter = `..`
+	historyDateFormat     = `2006-01-02`
+)
+
+// historyRange is the flag value of visit date range, in format <from>..<to>.
+// Both ends accept either date (2006-01-02) or RFC3339 timestamp, and either end may be blank.
+// The date at the end of range is inclusive, i.e., the range is extended till the end of the day.
+type historyRange history.Range
+
+func (h *historyRange) String() string {
+	var from, to string
+
+	if !h.From.IsZero() {
+		from = h.From.Format(time.RFC3339)
+	}
+
+	if !h.To.IsZero() {
+		to = h.To.Format(time.RFC3339)
+	}
+
+	if from == "" && to == "" {
+		return ""
+	}
+
+	return from + historyRangeDelimiter + to
+}
+
+func (h *historyRange) Set(s string) error {
+	var (
+		err      error
+		from, to time.Time
+		isDate   bool
+	)
+
+	splits := strings.Split(s, historyRangeDelimiter)
+	if len(splits) != 2 {
+		// When the delimiter is missing or repeated
+		return fmt.Errorf("invalid range %q in format --%s=<from>%s<to>", s, pkgConstants.HistoryRangeFlag,
+			historyRangeDelimiter)
+	}
+
+	if from, _, err = parseHistoryDate(splits[0]); err != nil {
+		return fmt.Errorf("invalid start of range in --%s: %v", pkgConstants.HistoryRangeFlag, err)
+	}
+
+	if to, isDate, err = parseHistoryDate(splits[1]); err != nil {
+		return fmt.Errorf("invalid end of range in --%s: %v", pkgConstants.HistoryRangeFlag, err)
+	}
+
+	if isDate {
+		// When end of range is a date, the whole day is included
+		to = to.AddDate(0, 0, 1)
+	}
+
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return fmt.Errorf("start of range is not before end of range in --%s=%s", pkgConstants.HistoryRangeFlag, s)
+	}
+
+	h.From, h.To = from, to
+
+	return nil
+}
+
+// parseHistoryDate parses date or RFC3339 timestamp, and reports whether the input is a date.
+// Blank input is parsed as zero time.
+func parseHistoryDate(s string) (time.Time, bool, error) {
+	var (
+		err error
+		t   time.Time
+	)
+
+	if s = strings.TrimSpace(s); s == "" {
+		return time.Time{}, false, nil
+	}
+
+	if t, err = time.Parse(historyDateFormat, s); err == nil {
+		return t, true, nil
+	}
+
+	if t, err = time.Parse(time.RFC3339, s); err != nil {
+		return time.Time{}, false, fmt.Errorf("%q is neither a date (%s) nor a RFC3339 timestamp", s, historyDateFormat)
+	}
+
+	return t, false, nil
+}
@@ -0,0 +1,83 @@
+package flags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryRange_Set(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantFrom   time.Time
+		wantTo     time.Time
+		wantString string
+		wantErr    bool
+	}{
+		{
+			name:       "Dates",
+			input:      "2023-03-01..2023-03-04",
+			wantFrom:   time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			wantTo:     time.Date(2023, time.March, 5, 0, 0, 0, 0, time.UTC),
+			wantString: "2023-03-01T00:00:00Z..2023-03-05T00:00:00Z",
+			wantErr:    false,
+		},
+		{
+			name:       "Timestamps",
+			input:      "2023-03-04T11:37:06Z..2023-03-04T11:37:07Z",
+			wantFrom:   time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC),
+			wantTo:     time.Date(2023, time.March, 4, 11, 37, 7, 0, time.UTC),
+			wantString: "2023-03-04T11:37:06Z..2023-03-04T11:37:07Z",
+			wantErr:    false,
+		},
+		{
+			name:       "Open-End",
+			input:      "2023-03-01..",
+			wantFrom:   time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			wantTo:     time.Time{},
+			wantString: "2023-03-01T00:00:00Z..",
+			wantErr:    false,
+		},
+		{
+			name:       "Open-Both-Ends",
+			input:      "..",
+			wantFrom:   time.Time{},
+			wantTo:     time.Time{},
+			wantString: "",
+			wantErr:    false,
+		},
+		{
+			name:    "Failure-Missing-Delimiter",
+			input:   "2023-03-01",
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Invalid-Date",
+			input:   "2023-13-01..",
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Reversed-Range",
+			input:   "2023-03-04..2023-03-01",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h historyRange
+
+			err := h.Set(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from set")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from set")
+			assert.Equal(t, tt.wantFrom, h.From, "Mismatch of start of range")
+			assert.Equal(t, tt.wantTo, h.To, "Mismatch of end of range")
+			assert.Equal(t, tt.wantString, h.String(), "Mismatch of range string")
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+)
+
+// ImportFlags are the input flags of import command
+type ImportFlags struct {
+	InputFile        InputFile `json:"input-file"`
+	TargetSQLiteFile string    `json:"target-sqlite-file"`
+	Profile          string    `json:"profile"`
+	ProfilesRoot     string    `json:"profiles-root"`
+	Silent           bool      `json:"silent"`
+}
+
+// ParseImport parses the input flags of import command, i.e., the args after the command name
+func (o *Operator) ParseImport() (*ImportFlags, error) {
+	var (
+		err   error
+		flags = ImportFlags{
+			InputFile:        InputFile{},
+			TargetSQLiteFile: "",
+			Profile:          "",
+			ProfilesRoot:     "",
+			Silent:           false,
+		}
+	)
+
+	o.flagSet.Var(&flags.InputFile, constants.InputFileFlag.String(), inputFileFlagDesc)
+	o.flagSet.StringVar(&flags.TargetSQLiteFile, constants.TargetSQLiteFileFlag.String(), "", targetSQLiteFileFlagDesc)
+	o.flagSet.StringVar(&flags.Profile, constants.ProfileFlag.String(), "", importProfileFlagDesc)
+	o.flagSet.StringVar(&flags.ProfilesRoot, constants.ProfilesRootFlag.String(), "", profilesRootFlagDesc)
+	o.flagSet.BoolVar(&flags.Silent, constants.SilentFlag.String(), silentFlagDefaultVal, silentFlagDesc)
+
+	if err = o.flagSet.Parse(o.args); err != nil {
+		// When parsing of input flag arguments failed
+		return nil, fmt.Errorf("failed to parse input flag args of %s command: %v", constants.ImportCommand, err)
+	}
+
+	if flags.InputFile.Filename == "" {
+		// When there is nothing to import
+		return nil, fmt.Errorf("missing --%s in %s command", constants.InputFileFlag, constants.ImportCommand)
+	}
+
+	if (flags.TargetSQLiteFile == "") == (flags.Profile == "") {
+		// When the target is either missing, or provided both as profile and as file
+		return nil, fmt.Errorf("%s command requires either --%s or --%s", constants.ImportCommand,
+			constants.TargetSQLiteFileFlag, constants.ProfileFlag)
+	}
+
+	return &flags, nil
+}
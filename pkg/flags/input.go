@@ -0,0 +1,47 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+
+	pkgConstants "github.com/vaguecoder/firefox-backups/pkg/constants"
+	pkgEncoding "github.com/vaguecoder/firefox-backups/pkg/encoding"
+)
+
+// InputFile is the format and filename of input file, in format <format>:<filename>
+type InputFile struct {
+	Format   pkgConstants.Constant[pkgConstants.OutputFormat] `json:"format"`
+	Filename string                                           `json:"filename"`
+}
+
+func (i *InputFile) String() string {
+	if i.Filename == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s%s%s", i.Format, outputFormatFilenameDelimiter, i.Filename)
+}
+
+func (i *InputFile) Set(s string) error {
+	// Only the first delimiter separates format, as the filename may have the delimiter
+	splits := strings.SplitN(s, outputFormatFilenameDelimiter, 2)
+	if len(splits) < 2 || splits[0] == "" || splits[1] == "" {
+		// When either format or filename is missing
+		return fmt.Errorf("missing argument in format --%s=<format>%s<filename>", pkgConstants.InputFileFlag,
+			outputFormatFilenameDelimiter)
+	}
+
+	format := pkgConstants.Constant[pkgConstants.OutputFormat](splits[0])
+
+	switch format {
+	case pkgConstants.CSVFormat, pkgConstants.JSONFormat, pkgConstants.TabularFormat, pkgConstants.YAMLFormat:
+		// Formats of exports having decoders, holding all the fields of bookmarks
+	default:
+		return fmt.Errorf("invalid input format in --%s=<format>%s<filename> (allowed formats: %v)",
+			pkgConstants.InputFileFlag, outputFormatFilenameDelimiter, pkgEncoding.AllDecoders)
+	}
+
+	i.Format, i.Filename = format, splits[1]
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package flags
+
+import (
+	"strings"
+
+	"github.com/vaguecoder/firefox-backups/pkg/encoding/jsonl"
+)
+
+// jsonlFieldsDelimiter delimits the fields of jsonl outputs
+const jsonlFieldsDelimiter = `,`
+
+// JSONLFields are the names of bookmark fields written in jsonl outputs, in their order,
+// in format <field>[,<field>...], e.g., url,title,tags. All the fields are written when empty.
+type JSONLFields []string
+
+func (f *JSONLFields) String() string {
+	return strings.Join(*f, jsonlFieldsDelimiter)
+}
+
+func (f *JSONLFields) Set(value string) error {
+	var fields JSONLFields
+
+	for _, field := range strings.Split(value, jsonlFieldsDelimiter) {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	if err := jsonl.ValidateFields(fields); err != nil {
+		return err
+	}
+
+	*f = fields
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONLFields_Set(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       JSONLFields
+		wantString string
+		wantErr    bool
+	}{
+		{
+			name:       "Selected-Fields",
+			input:      "url, title,tags",
+			want:       JSONLFields{"url", "title", "tags"},
+			wantString: "url,title,tags",
+			wantErr:    false,
+		},
+		{
+			name:       "No-Fields",
+			input:      "",
+			want:       nil,
+			wantString: "",
+			wantErr:    false,
+		},
+		{
+			name:    "Failure-Unknown-Field",
+			input:   "url,href",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f JSONLFields
+
+			err := f.Set(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from set")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from set")
+			assert.Equal(t, tt.want, f, "Mismatch of jsonl fields")
+			assert.Equal(t, tt.wantString, f.String(), "Mismatch of jsonl fields string")
+		})
+	}
+}
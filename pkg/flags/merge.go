@@ -0,0 +1,89 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/encoding"
+)
+
+// minMergeSources is the minimum count of sources to merge
+const minMergeSources = 2
+
+// MergeFlags are the input flags of merge command
+type MergeFlags struct {
+	Sources        sources                              `json:"sources"`
+	ConflictPolicy constants.Constant[constants.Policy] `json:"conflict-policy"`
+	OutputFiles    outputs                              `json:"output-files"`
+	StdOutFormat   encoding.Encoder                     `json:"stdout-format"`
+	Silent         bool                                 `json:"silent"`
+}
+
+// ParseMerge parses the input flags of merge command, i.e., the args after the command name
+func (o *Operator) ParseMerge() (*MergeFlags, error) {
+	var (
+		err            error
+		stdOutFormat   string
+		conflictPolicy string
+		flags          = MergeFlags{
+			Sources:        sources{},
+			ConflictPolicy: constants.NewestPolicy,
+			OutputFiles:    outputs{},
+			StdOutFormat:   nil,
+			Silent:         false,
+		}
+	)
+
+	o.flagSet.Var(&flags.Sources, constants.SourceFlag.String(), sourceFlagDesc)
+	o.flagSet.StringVar(&conflictPolicy, constants.ConflictPolicyFlag.String(), conflictPolicyFlagDefaultVal,
+		conflictPolicyFlagDesc)
+	o.flagSet.Var(&flags.OutputFiles, constants.OutputFiles.String(), outputFilesFlagDesc)
+	o.flagSet.StringVar(&stdOutFormat, constants.StdOutFormatFlag.String(), "", stdOutFormatFlagDesc)
+	o.flagSet.BoolVar(&flags.Silent, constants.SilentFlag.String(), silentFlagDefaultVal, silentFlagDesc)
+
+	if err = o.flagSet.Parse(o.args); err != nil {
+		// When parsing of input flag arguments failed
+		return nil, fmt.Errorf("failed to parse input flag args of %s command: %v", constants.MergeCommand, err)
+	}
+
+	if len(flags.Sources) < minMergeSources {
+		// When there is nothing to merge with
+		return nil, fmt.Errorf("%s command requires at least %d --%s", constants.MergeCommand, minMergeSources,
+			constants.SourceFlag)
+	}
+
+	switch flags.ConflictPolicy = constants.Constant[constants.Policy](conflictPolicy); flags.ConflictPolicy {
+	case constants.NewestPolicy, constants.PreferFirstPolicy, constants.KeepBothPolicy:
+		// Known policies
+	default:
+		return nil, fmt.Errorf("invalid policy '%s' to --%s flag (available policies: [%s, %s, %s])",
+			conflictPolicy, constants.ConflictPolicyFlag, constants.KeepBothPolicy, constants.NewestPolicy,
+			constants.PreferFirstPolicy)
+	}
+
+	for _, output := range flags.OutputFiles {
+		if output.HasTemplate(ProfileTemplate) {
+			// When profile name is required in filename, but the merged bookmarks aren't of a profile
+			return nil, fmt.Errorf("%s in --%s isn't supported by %s command", ProfileTemplate, constants.OutputFiles,
+				constants.MergeCommand)
+		}
+	}
+
+	if stdOutFormat != "" {
+		// When flag --stdout-format is provided with a non-empty string
+		if flags.StdOutFormat, err = stdOutEncoder(stdOutFormat, nil); err != nil {
+			return nil, err
+		}
+
+		// When the merged bookmarks be printed on stdout, the app logs should be suppressed
+		flags.Silent = true
+	}
+
+	if len(flags.OutputFiles) == 0 && flags.StdOutFormat == nil {
+		// When the merged bookmarks would be discarded
+		return nil, fmt.Errorf("%s command requires --%s or --%s", constants.MergeCommand, constants.OutputFiles,
+			constants.StdOutFormatFlag)
+	}
+
+	return &flags, nil
+}
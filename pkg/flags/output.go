@@ -3,7 +3,9 @@ package flags
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	pkgConstants "github.com/vaguecoder/firefox-backups/pkg/constants"
 	pkgEncoding "github.com/vaguecoder/firefox-backups/pkg/encoding"
@@ -12,11 +14,145 @@ import (
 const (
 	outputFormatFilenameDelimiter = `:`
 	outputFilesDelimiter          = `,`
+
+	// Templates in output filenames, expanded before creating the files
+	ProfileTemplate    = `{profile}`
+	DateTemplate       = `{date}`
+	templateDateFormat = `2006-01-02`
+
+	// Options of output files, set in config file
+	headerOption      = `header`
+	frontMatterOption = `front-matter`
+	fieldsOption      = `fields`
 )
 
+// outputOptions are the options of output formats
+var outputOptions = map[pkgConstants.Constant[pkgConstants.OutputFormat]][]string{
+	pkgConstants.CSVFormat:      {headerOption},
+	pkgConstants.TabularFormat:  {headerOption},
+	pkgConstants.MarkdownFormat: {frontMatterOption},
+	pkgConstants.OrgFormat:      {frontMatterOption},
+	pkgConstants.JSONLFormat:    {fieldsOption},
+}
+
+// modeFormats are the output formats supported by the modes exporting other than bookmarks.
+// Bookmarks mode supports all the formats.
+var modeFormats = map[pkgConstants.Constant[pkgConstants.Mode]][]pkgConstants.Constant[pkgConstants.OutputFormat]{
+	pkgConstants.HistoryMode: {pkgConstants.CSVFormat, pkgConstants.JSONFormat, pkgConstants.TabularFormat,
+		pkgConstants.YAMLFormat},
+	pkgConstants.CheckLinksMode: {pkgConstants.CSVFormat, pkgConstants.JSONFormat, pkgConstants.TabularFormat,
+		pkgConstants.YAMLFormat},
+}
+
+// validateModeFormat validates the output format to be supported by the mode
+func validateModeFormat(mode pkgConstants.Constant[pkgConstants.Mode],
+	format pkgConstants.Constant[pkgConstants.OutputFormat]) error {
+	formats, ok := modeFormats[mode]
+	if !ok {
+		// When the mode supports all the formats
+		return nil
+	}
+
+	for _, supported := range formats {
+		if format == supported {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("format %q isn't supported by --%s=%s (supported formats: %v)", format,
+		pkgConstants.ModeFlag, mode, formats)
+}
+
 type OutputFile struct {
 	Format   pkgConstants.Constant[pkgConstants.OutputFormat]
 	Filename string
+	Options  map[string]string // Format specific options, set in config file
+}
+
+// Header reports whether the header row be written, for csv and table formats. It's enabled by default.
+func (o OutputFile) Header() bool {
+	header, err := strconv.ParseBool(o.Options[headerOption])
+
+	return header || err != nil
+}
+
+// FrontMatter returns the fields of front matter of the output, or the fields in flags when it's not set
+func (o OutputFile) FrontMatter(flagFields FrontMatterFields) FrontMatterFields {
+	value, ok := o.Options[frontMatterOption]
+	if !ok {
+		return flagFields
+	}
+
+	// Error is ignored, as the option is validated on setting
+	var fields FrontMatterFields
+	_ = fields.Set(value)
+
+	return fields
+}
+
+// JSONLFields returns the fields of jsonl output, or the fields in flags when it's not set
+func (o OutputFile) JSONLFields(flagFields JSONLFields) JSONLFields {
+	value, ok := o.Options[fieldsOption]
+	if !ok {
+		return flagFields
+	}
+
+	// Error is ignored, as the option is validated on setting
+	var fields JSONLFields
+	_ = fields.Set(value)
+
+	return fields
+}
+
+// setOptions validates the options against the format, and sets them
+func (o *OutputFile) setOptions(options map[string]string) error {
+	for name, value := range options {
+		var known bool
+		for _, option := range outputOptions[o.Format] {
+			known = known || option == name
+		}
+
+		if !known {
+			return fmt.Errorf("unknown option %q of %s format (options: %v)", name, o.Format, outputOptions[o.Format])
+		}
+
+		switch name {
+		case headerOption:
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("invalid value %q of option %q, expected true or false", value, name)
+			}
+		case frontMatterOption:
+			var fields FrontMatterFields
+			if err := fields.Set(value); err != nil {
+				return fmt.Errorf("invalid value %q of option %q: %v", value, name, err)
+			}
+		case fieldsOption:
+			var fields JSONLFields
+			if err := fields.Set(value); err != nil {
+				return fmt.Errorf("invalid value %q of option %q: %v", value, name, err)
+			}
+		}
+	}
+
+	if len(options) > 0 {
+		o.Options = options
+	}
+
+	return nil
+}
+
+// ExpandFilename returns the filename with templates replaced by the profile name and the date.
+// Path separators in profile name are replaced, so the profile name can't change the directory.
+func (o OutputFile) ExpandFilename(profile string, date time.Time) string {
+	profile = strings.NewReplacer("/", "_", `\`, "_").Replace(profile)
+
+	return strings.NewReplacer(ProfileTemplate, profile, DateTemplate, date.Format(templateDateFormat)).
+		Replace(o.Filename)
+}
+
+// HasTemplate reports whether the filename has the template
+func (o OutputFile) HasTemplate(template string) bool {
+	return strings.Contains(o.Filename, template)
 }
 
 type outputs []OutputFile
@@ -37,6 +173,10 @@ func (o *outputs) String() string {
 	return strings.Join(outputs, ",")
 }
 
+func (o *outputs) reset() {
+	*o = nil
+}
+
 func (o *outputs) Set(s string) error {
 	var (
 		index                    int
@@ -101,7 +241,10 @@ func (o *outputs) Set(s string) error {
 
 		// Input validation (2/2): File format validation
 		switch format {
-		case pkgConstants.CSVFormat, pkgConstants.JSONFormat, pkgConstants.TabularFormat, pkgConstants.YAMLFormat:
+		case pkgConstants.CSVFormat, pkgConstants.JSONFormat, pkgConstants.TabularFormat, pkgConstants.YAMLFormat,
+			pkgConstants.HTMLFormat, pkgConstants.JSONLZ4Format, pkgConstants.JSONTreeFormat, pkgConstants.YAMLTreeFormat,
+			pkgConstants.MarkdownFormat, pkgConstants.OrgFormat, pkgConstants.SiteFormat,
+			pkgConstants.SQLiteExportFormat, pkgConstants.JSONLFormat:
 			output.Format = format
 		default:
 			return fmt.Errorf("invalid output format in --%s=<format>%s<filename> (allowed formats: %v)",
@@ -1 +1,87 @@
 package flags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+)
+
+func TestOutputFile_ExpandFilename(t *testing.T) {
+	date := time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filename string
+		profile  string
+		want     string
+	}{
+		{
+			name:     "Without-Templates",
+			filename: "bookmarks.json",
+			profile:  "default-release",
+			want:     "bookmarks.json",
+		},
+		{
+			name:     "Profile-Template",
+			filename: "bookmarks-{profile}.json",
+			profile:  "default-release",
+			want:     "bookmarks-default-release.json",
+		},
+		{
+			name:     "Profile-And-Date-Templates",
+			filename: "backups/{date}/{profile}.json",
+			profile:  "default-release",
+			want:     "backups/2023-03-04/default-release.json",
+		},
+		{
+			name:     "Repeated-Templates",
+			filename: "{profile}-{profile}-{date}.json",
+			profile:  "work",
+			want:     "work-work-2023-03-04.json",
+		},
+		{
+			name:     "Path-Separators-In-Profile",
+			filename: "{profile}.json",
+			profile:  "../work/other",
+			want:     ".._work_other.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := OutputFile{
+				Format:   constants.JSONFormat,
+				Filename: tt.filename,
+			}
+
+			assert.Equal(t, tt.want, o.ExpandFilename(tt.profile, date), "Mismatch of expanded filename")
+		})
+	}
+}
+
+func TestOutputFile_Header(t *testing.T) {
+	assert.True(t, OutputFile{Format: constants.CSVFormat}.Header(), "Expected header by default")
+	assert.False(t, OutputFile{Format: constants.CSVFormat, Options: map[string]string{"header": "false"}}.Header(),
+		"Expected no header when disabled")
+}
+
+func TestOutputFile_FrontMatter(t *testing.T) {
+	flagFields := FrontMatterFields{Date: true}
+
+	assert.Equal(t, flagFields, OutputFile{Format: constants.MarkdownFormat}.FrontMatter(flagFields),
+		"Expected front matter of flags by default")
+	assert.Equal(t, FrontMatterFields{Profile: true},
+		OutputFile{Format: constants.OrgFormat, Options: map[string]string{"front-matter": "profile"}}.FrontMatter(flagFields),
+		"Expected front matter of the output option")
+}
+
+func TestOutputFile_JSONLFields(t *testing.T) {
+	flagFields := JSONLFields{"url"}
+
+	assert.Equal(t, flagFields, OutputFile{Format: constants.JSONLFormat}.JSONLFields(flagFields),
+		"Expected fields of flags by default")
+	assert.Equal(t, JSONLFields{"title", "tags"},
+		OutputFile{Format: constants.JSONLFormat, Options: map[string]string{"fields": "title,tags"}}.JSONLFields(flagFields),
+		"Expected fields of the output option")
+}
@@ -0,0 +1,82 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	pkgEncoding "github.com/vaguecoder/firefox-backups/pkg/encoding"
+)
+
+// Source is a source of bookmarks for diff and merge commands, in format <format>:<filename>.
+// The format is sqlite for places.sqlite, jsonlz4 for Firefox's backup,
+// or any of the formats having decoders, i.e., the exports of this tool.
+type Source struct {
+	Format   constants.Constant[constants.OutputFormat] `json:"format"`
+	Filename string                                     `json:"filename"`
+}
+
+func (s *Source) String() string {
+	if s.Filename == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s%s%s", s.Format, outputFormatFilenameDelimiter, s.Filename)
+}
+
+func (s *Source) Set(value string) error {
+	// Only the first delimiter separates format, as the filename may have the delimiter
+	splits := strings.SplitN(value, outputFormatFilenameDelimiter, 2)
+	if len(splits) < 2 || splits[0] == "" || splits[1] == "" {
+		// When either format or filename is missing
+		return fmt.Errorf("missing argument in format <format>%s<filename>", outputFormatFilenameDelimiter)
+	}
+
+	format := constants.Constant[constants.OutputFormat](splits[0])
+
+	switch format {
+	case constants.SQLiteFormat, constants.JSONLZ4Format, constants.CSVFormat, constants.JSONFormat,
+		constants.TabularFormat, constants.YAMLFormat:
+		// Formats of places.sqlite, Firefox's backup and exports having decoders
+	default:
+		return fmt.Errorf("invalid source format %q (allowed formats: %s, %s, %v)", format, constants.SQLiteFormat,
+			constants.JSONLZ4Format, pkgEncoding.AllDecoders)
+	}
+
+	s.Format, s.Filename = format, splits[1]
+
+	return nil
+}
+
+// InputFile returns the source as input file, for the formats having decoders
+func (s *Source) InputFile() InputFile {
+	return InputFile{
+		Format:   s.Format,
+		Filename: s.Filename,
+	}
+}
+
+// sources is the list of sources, with the flag repeated for each source
+type sources []Source
+
+func (s *sources) String() string {
+	var list []string
+
+	for _, source := range *s {
+		list = append(list, source.String())
+	}
+
+	return strings.Join(list, " ")
+}
+
+func (s *sources) Set(value string) error {
+	var source Source
+
+	if err := source.Set(value); err != nil {
+		return err
+	}
+
+	*s = append(*s, source)
+
+	return nil
+}
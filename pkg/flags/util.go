@@ -2,19 +2,22 @@ package flags
 
 import (
 	"fmt"
+	"time"
 )
 
 // flagTypes holds a selective list of types that are used as default values
 // in input flag's descriptions.
 //
 //  1. bool - Boolean flags are used for just true/false.
-//  2. quotedString - Is a wrapper over string typed flags that encloses the result in quotes.
-//  3. *outputs - Is a slice of set of output file format and filename.
+//  2. int - Integer flags, such as counts.
+//  3. quotedString - Is a wrapper over string typed flags that encloses the result in quotes.
+//  4. time.Duration - Duration flags, printed in Go's duration format, e.g., 1m30s.
+//  5. *outputs - Is a slice of set of output file format and filename.
 //     Reference of outputs is required as one of the other function on outputs
 //     i.e., Set() requires pointer receiver to update. Hence, outputs instance
 //     usually is used with pointer.
 type flagTypes interface {
-	bool | quotedString | *outputs
+	bool | int | quotedString | time.Duration | *outputs
 }
 
 // quotedString encloses string in quotes
@@ -0,0 +1,74 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// timeFormat is the format of timestamps in visits table
+	timeFormat = time.RFC3339
+)
+
+// Visit is a browsing history record, i.e., a visit in moz_historyvisits
+// along with the URL and title of the visited place in moz_places
+type Visit struct {
+	ID        int       `json:"id" yaml:"id"`
+	FromVisit int       `json:"from-visit" yaml:"from-visit"`
+	URL       string    `json:"url" yaml:"url"`
+	Title     string    `json:"title" yaml:"title"`
+	VisitDate time.Time `json:"visit-date" yaml:"visit-date"`
+	VisitType int       `json:"visit-type" yaml:"visit-type"`
+}
+
+// Range is the range of visit dates, From being inclusive and To being exclusive.
+// Zero From or To leaves the range open on the respective end.
+type Range struct {
+	From time.Time `json:"from" yaml:"from"`
+	To   time.Time `json:"to" yaml:"to"`
+}
+
+// VisitsTable parses the visits data to 2D string table
+func VisitsTable(visits []Visit, enableHeader bool) [][]string {
+	if len(visits) == 0 {
+		// When no visits
+		return nil
+	}
+
+	var (
+		sheet [][]string
+
+		// Header for table based on field order, uppercased
+		header = []string{"URL", "TITLE", "VISIT-DATE", "VISIT-TYPE", "ID", "FROM-VISIT"}
+	)
+
+	if enableHeader {
+		// When header toggle is enabled
+		sheet = append(sheet, header)
+		sheet = append(sheet, headerUnderline(header))
+	}
+
+	for _, v := range visits {
+		// Append record to result
+		sheet = append(sheet, []string{
+			strings.TrimSpace(v.URL),       // Trim leading and trailing whitespace from URL
+			strings.TrimSpace(v.Title),     // Trim leading and trailing whitespace from title
+			v.VisitDate.Format(timeFormat), // Formatted timestamp of visit
+			fmt.Sprint(v.VisitType),        // No whitespace trimming required for visit type as int is parsed as string
+			fmt.Sprint(v.ID),               // No whitespace trimming required for ID as int is parsed as string
+			fmt.Sprint(v.FromVisit),        // No whitespace trimming required for referring visit ID as int is parsed as string
+		})
+	}
+
+	return sheet
+}
+
+func headerUnderline(header []string) []string {
+	underline := []string{}
+	for _, title := range header {
+		underline = append(underline, strings.Repeat("-", len(title)))
+	}
+
+	return underline
+}
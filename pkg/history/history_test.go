@@ -0,0 +1,103 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVisitsTable(t *testing.T) {
+	type args struct {
+		visits       []Visit
+		enableHeader bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want [][]string
+	}{
+		{
+			name: "Empty-Visits-Header-Enabled",
+			args: args{
+				visits:       []Visit{},
+				enableHeader: true,
+			},
+			want: [][]string(nil),
+		},
+		{
+			name: "Valid-Visits-Header-Disabled",
+			args: args{
+				visits: []Visit{
+					{
+						ID:        2,
+						FromVisit: 1,
+						URL:       " https://github.com/vaguecoder ",
+						Title:     "Vague Coder ",
+						VisitDate: time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC),
+						VisitType: 1,
+					},
+				},
+				enableHeader: false,
+			},
+			want: [][]string{
+				{
+					"https://github.com/vaguecoder",
+					"Vague Coder",
+					"2023-03-04T11:37:06Z",
+					"1",
+					"2",
+					"1",
+				},
+			},
+		},
+		{
+			name: "Valid-Visits-Header-Enabled",
+			args: args{
+				visits: []Visit{
+					{
+						ID:        1,
+						FromVisit: 0,
+						URL:       "https://github.com/random",
+						Title:     "",
+						VisitDate: time.Date(2023, time.March, 4, 11, 37, 6, 0, time.UTC),
+						VisitType: 2,
+					},
+				},
+				enableHeader: true,
+			},
+			want: [][]string{
+				{
+					"URL",
+					"TITLE",
+					"VISIT-DATE",
+					"VISIT-TYPE",
+					"ID",
+					"FROM-VISIT",
+				},
+				{
+					"---",
+					"-----",
+					"----------",
+					"----------",
+					"--",
+					"----------",
+				},
+				{
+					"https://github.com/random",
+					"",
+					"2023-03-04T11:37:06Z",
+					"2",
+					"1",
+					"0",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VisitsTable(tt.args.visits, tt.args.enableHeader); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VisitsTable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
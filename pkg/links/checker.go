@@ -0,0 +1,330 @@
+package links
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+)
+
+const (
+	// DefaultWorkers is the default count of concurrent requests
+	DefaultWorkers = 8
+
+	// maxRedirects is the maximum count of redirects followed for a link
+	maxRedirects = 10
+
+	// userAgent is sent with every request, as some sites reject the requests without one
+	userAgent = `firefox-backups link checker (+https://github.com/vaguecoder/firefox-backups)`
+)
+
+// result is the health of a URL, shared by all the bookmarks of the URL
+type result struct {
+	status      int
+	redirectURL string
+	permanent   bool
+	err         string
+}
+
+// Checker checks the health of bookmark URLs over HTTP, with a bounded pool of workers
+// and a minimum interval between the requests to the same host. Every URL is checked
+// once, and the results are cached for the later checks, e.g., of multiple filters.
+type Checker struct {
+	client   *http.Client
+	workers  int
+	interval time.Duration
+	logger   logs.Logger
+
+	mutex   sync.Mutex
+	results map[string]result
+}
+
+// NewChecker initiates new Checker sending the requests with the client. The client is copied,
+// and the copy doesn't follow the redirects, as the checker follows them to find the redirect kinds.
+// Nil client is the zero client of net/http, without timeout.
+func NewChecker(ctx context.Context, client *http.Client) *Checker {
+	var noRedirectClient http.Client
+
+	if client != nil {
+		noRedirectClient = *client
+	}
+
+	noRedirectClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &Checker{
+		client:   &noRedirectClient,
+		workers:  DefaultWorkers,
+		interval: 0,
+		logger:   logs.FromContext(ctx),
+		results:  make(map[string]result),
+	}
+}
+
+// Workers sets the count of concurrent requests, at least one.
+// Both receiver and return value are of same type to implement builder's pattern.
+func (c *Checker) Workers(workers int) *Checker {
+	if workers > 0 {
+		c.workers = workers
+	}
+
+	return c
+}
+
+// HostInterval sets the minimum interval between the requests to the same host.
+// Both receiver and return value are of same type to implement builder's pattern.
+func (c *Checker) HostInterval(interval time.Duration) *Checker {
+	c.interval = interval
+
+	return c
+}
+
+// Check checks the URLs of the bookmarks, sending HEAD request and falling back to GET
+// when the server rejects HEAD. The redirects are followed up to 10 hops.
+// It returns the links of the bookmarks with http and https URLs, in order of bookmarks.
+// The other records, e.g., folders and place: queries, are skipped.
+func (c *Checker) Check(ctx context.Context, bookmarks []bookmark.Bookmark) []Link {
+	var (
+		links   []Link
+		pending []string
+		queued  = map[string]bool{}
+	)
+
+	c.mutex.Lock()
+	for _, b := range bookmarks {
+		if b.URL == nil || !isHTTP(*b.URL) {
+			continue
+		}
+
+		if _, ok := c.results[*b.URL]; !ok && !queued[*b.URL] {
+			queued[*b.URL] = true
+			pending = append(pending, *b.URL)
+		}
+	}
+	c.mutex.Unlock()
+
+	c.logger.Info().Int("count", len(pending)).Int("workers", c.workers).Dur("host-interval", c.interval).
+		Msg("Checking links")
+
+	c.checkAll(ctx, pending)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var dead, redirects int
+	for _, b := range bookmarks {
+		if b.URL == nil || !isHTTP(*b.URL) {
+			continue
+		}
+
+		r := c.results[*b.URL]
+		link := Link{
+			URL:         *b.URL,
+			Title:       b.Title,
+			Folder:      b.Folder,
+			GUID:        b.GUID,
+			Status:      r.status,
+			RedirectURL: r.redirectURL,
+			Permanent:   r.permanent,
+			Error:       r.err,
+		}
+
+		if link.IsDead() {
+			dead++
+		} else if link.IsPermanentRedirect() {
+			redirects++
+		}
+
+		links = append(links, link)
+	}
+
+	c.logger.Info().Int("count", len(links)).Int("dead", dead).Int("permanent-redirects", redirects).
+		Msg("Checked links")
+
+	return links
+}
+
+// checkAll checks the URLs with the pool of workers, and caches the results
+func (c *Checker) checkAll(ctx context.Context, pending []string) {
+	var (
+		wg      sync.WaitGroup
+		queue   = make(chan string)
+		limiter = newHostLimiter(c.interval)
+	)
+
+	for i := 0; i < c.workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for rawURL := range queue {
+				r := c.check(ctx, limiter, rawURL)
+
+				c.mutex.Lock()
+				c.results[rawURL] = r
+				c.mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, rawURL := range pending {
+		queue <- rawURL
+	}
+
+	close(queue)
+	wg.Wait()
+}
+
+// check checks the URL, following the redirects
+func (c *Checker) check(ctx context.Context, limiter *hostLimiter, rawURL string) result {
+	var (
+		r       = result{permanent: true}
+		current = rawURL
+	)
+
+	for hop := 0; ; hop++ {
+		status, location, err := c.request(ctx, limiter, current)
+		if err != nil {
+			r.err = err.Error()
+			break
+		}
+
+		r.status = status
+
+		if !isRedirect(status) || location == "" {
+			break
+		}
+
+		if hop == maxRedirects {
+			// When the redirects loop, or the chain is too long
+			r.err = fmt.Sprintf("stopped after %d redirects", maxRedirects)
+			break
+		}
+
+		next, err := url.Parse(current)
+		if err == nil {
+			next, err = next.Parse(location)
+		}
+
+		if err != nil {
+			r.err = fmt.Sprintf("invalid redirect location %q: %v", location, err)
+			break
+		}
+
+		r.permanent = r.permanent && (status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect)
+		current = next.String()
+		r.redirectURL = current
+	}
+
+	if r.redirectURL == "" {
+		r.permanent = false
+	}
+
+	c.logger.Debug().Str("url", rawURL).Int("status", r.status).Str("redirect-url", r.redirectURL).
+		Str("error", r.err).Msg("Checked link")
+
+	return r
+}
+
+// request sends HEAD request to the URL, falling back to GET when the server rejects HEAD,
+// and returns the status and the redirect location, if any
+func (c *Checker) request(ctx context.Context, limiter *hostLimiter, rawURL string) (int, string, error) {
+	status, location, err := c.send(ctx, limiter, http.MethodHead, rawURL)
+	if err == nil && status >= http.StatusBadRequest {
+		// Many servers don't implement HEAD, or respond differently to it
+		return c.send(ctx, limiter, http.MethodGet, rawURL)
+	}
+
+	return status, location, err
+}
+
+// send sends the request of the method, waiting for the interval of the host
+func (c *Checker) send(ctx context.Context, limiter *hostLimiter, method, rawURL string) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+
+	if err = limiter.wait(ctx, req.URL.Host); err != nil {
+		return 0, "", err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+
+	// The body isn't required, only the status and headers
+	defer resp.Body.Close()
+
+	return resp.StatusCode, resp.Header.Get("Location"), nil
+}
+
+// hostLimiter holds the time of next allowed request to every host
+type hostLimiter struct {
+	interval time.Duration
+	mutex    sync.Mutex
+	next     map[string]time.Time
+}
+
+// newHostLimiter initiates new hostLimiter with the minimum interval between the requests to a host
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{
+		interval: interval,
+		next:     make(map[string]time.Time),
+	}
+}
+
+// wait blocks until the request to the host is allowed, or the context is done
+func (h *hostLimiter) wait(ctx context.Context, host string) error {
+	if h.interval <= 0 {
+		return ctx.Err()
+	}
+
+	h.mutex.Lock()
+	now := time.Now()
+	at := h.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	h.next[host] = at.Add(h.interval)
+	h.mutex.Unlock()
+
+	timer := time.NewTimer(at.Sub(now))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRedirect reports whether the status is of redirect with location
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+// isHTTP reports whether the URL is of http or https scheme, i.e., is checkable
+func isHTTP(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
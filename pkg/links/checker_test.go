@@ -0,0 +1,174 @@
+package links
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+// newServer starts the stand-in server of the bookmarked sites, counting the requests
+func newServer(t *testing.T, requests *int32) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/moved-again", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/moved-again", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusPermanentRedirect)
+	})
+	mux.HandleFunc("/found", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusFound)
+	})
+	mux.HandleFunc("/moved-to-gone", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/gone", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusMovedPermanently)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestChecker_Check(t *testing.T) {
+	var (
+		requests int32
+		ptrStr   = util.PtrStr
+		server   = newServer(t, &requests)
+	)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		want Link
+	}{
+		{
+			name: "Live",
+			url:  server.URL + "/ok",
+			want: Link{Status: http.StatusOK},
+		},
+		{
+			name: "Gone",
+			url:  server.URL + "/gone",
+			want: Link{Status: http.StatusGone},
+		},
+		{
+			name: "Fallback-To-GET",
+			url:  server.URL + "/get-only",
+			want: Link{Status: http.StatusOK},
+		},
+		{
+			name: "Permanent-Redirects",
+			url:  server.URL + "/moved",
+			want: Link{Status: http.StatusOK, RedirectURL: server.URL + "/ok", Permanent: true},
+		},
+		{
+			name: "Temporary-Redirect",
+			url:  server.URL + "/found",
+			want: Link{Status: http.StatusOK, RedirectURL: server.URL + "/ok", Permanent: false},
+		},
+		{
+			name: "Permanent-Redirect-To-Gone",
+			url:  server.URL + "/moved-to-gone",
+			want: Link{Status: http.StatusGone, RedirectURL: server.URL + "/gone", Permanent: true},
+		},
+		{
+			name: "Redirect-Loop",
+			url:  server.URL + "/loop",
+			want: Link{Status: http.StatusMovedPermanently, RedirectURL: server.URL + "/loop", Permanent: true,
+				Error: "stopped after 10 redirects"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := logs.SilentLogger(context.Background())
+
+			got := NewChecker(ctx, server.Client()).Check(ctx, []bookmark.Bookmark{
+				{Title: tt.name, URL: ptrStr(tt.url), GUID: "bookmarkgd01", Folder: "toolbar"},
+			})
+
+			tt.want.URL, tt.want.Title, tt.want.GUID, tt.want.Folder = tt.url, tt.name, "bookmarkgd01", "toolbar"
+			assert.Equal(t, []Link{tt.want}, got, "Mismatch of checked links")
+		})
+	}
+
+	t.Run("Refused-Connection", func(t *testing.T) {
+		ctx, _ := logs.SilentLogger(context.Background())
+
+		got := NewChecker(ctx, nil).Check(ctx, []bookmark.Bookmark{{URL: ptrStr(closed.URL + "/ok")}})
+
+		assert.Len(t, got, 1, "Mismatch of count of links")
+		assert.True(t, got[0].IsDead(), "Expected dead link on refused connection")
+	})
+}
+
+func TestChecker_Check_Skips_And_Caches(t *testing.T) {
+	var (
+		requests int32
+		ptrStr   = util.PtrStr
+		server   = newServer(t, &requests)
+	)
+
+	ctx, _ := logs.SilentLogger(context.Background())
+	checker := NewChecker(ctx, server.Client()).Workers(4)
+
+	bookmarks := []bookmark.Bookmark{
+		{Title: "toolbar", Type: bookmark.TypeFolder},
+		{Title: "Recent Tags", URL: ptrStr("place:type=6&sort=14&maxResults=10")},
+		{Title: "Live", URL: ptrStr(server.URL + "/ok")},
+		{Title: "Live Again", URL: ptrStr(server.URL + "/ok")},
+	}
+
+	got := checker.Check(ctx, bookmarks)
+	assert.Len(t, got, 2, "Expected only the http links")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "Expected one request per URL")
+
+	checker.Check(ctx, bookmarks)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "Expected cached result on second check")
+}
+
+func TestChecker_HostInterval(t *testing.T) {
+	var (
+		requests int32
+		ptrStr   = util.PtrStr
+		server   = newServer(t, &requests)
+		interval = 50 * time.Millisecond
+	)
+
+	ctx, _ := logs.SilentLogger(context.Background())
+
+	start := time.Now()
+	NewChecker(ctx, server.Client()).Workers(3).HostInterval(interval).Check(ctx, []bookmark.Bookmark{
+		{URL: ptrStr(server.URL + "/ok")},
+		{URL: ptrStr(server.URL + "/ok?page=2")},
+		{URL: ptrStr(server.URL + "/ok?page=3")},
+	})
+
+	assert.GreaterOrEqual(t, time.Since(start), 2*interval, "Expected requests to same host spaced by interval")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests), "Mismatch of count of requests")
+}
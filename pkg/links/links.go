@@ -0,0 +1,77 @@
+package links
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Link is the health of a bookmark's URL, as checked over HTTP
+type Link struct {
+	URL         string `json:"url" yaml:"url"`
+	Title       string `json:"title" yaml:"title"`
+	Folder      string `json:"folder" yaml:"folder"`
+	GUID        string `json:"guid" yaml:"guid"`
+	Status      int    `json:"status" yaml:"status"`
+	RedirectURL string `json:"redirect-url" yaml:"redirect-url"`
+	Permanent   bool   `json:"permanent" yaml:"permanent"`
+	Error       string `json:"error" yaml:"error"`
+}
+
+// IsDead reports whether the link is rotten, i.e., the request failed,
+// e.g., unknown host or refused connection, or the page is not found or gone.
+func (l Link) IsDead() bool {
+	return l.Error != "" || l.Status == http.StatusNotFound || l.Status == http.StatusGone
+}
+
+// IsPermanentRedirect reports whether the link redirects to a live page
+// only by permanent redirects, i.e., 301 and 308, so the redirect target can replace the URL
+func (l Link) IsPermanentRedirect() bool {
+	return l.RedirectURL != "" && l.Permanent && l.Error == "" && l.Status >= 200 && l.Status < 300
+}
+
+// LinksTable parses the links data to 2D string table
+func LinksTable(links []Link, enableHeader bool) [][]string {
+	if len(links) == 0 {
+		// When no links
+		return nil
+	}
+
+	var (
+		sheet [][]string
+
+		// Header for table based on field order, uppercased
+		header = []string{"URL", "TITLE", "FOLDER", "STATUS", "REDIRECT-URL", "PERMANENT", "ERROR", "GUID"}
+	)
+
+	if enableHeader {
+		// When header toggle is enabled
+		sheet = append(sheet, header)
+		sheet = append(sheet, headerUnderline(header))
+	}
+
+	for _, l := range links {
+		// Append record to result
+		sheet = append(sheet, []string{
+			strings.TrimSpace(l.URL),    // Trim leading and trailing whitespace from URL
+			strings.TrimSpace(l.Title),  // Trim leading and trailing whitespace from title
+			strings.TrimSpace(l.Folder), // Trim leading and trailing whitespace from folder path
+			fmt.Sprint(l.Status),        // No whitespace trimming required for status as int is parsed as string
+			l.RedirectURL,               // Redirect target is resolved by the checker, no trimming required
+			fmt.Sprint(l.Permanent),     // No whitespace trimming required for bool parsed as string
+			l.Error,                     // Error message of the failed request
+			strings.TrimSpace(l.GUID),   // Trim leading and trailing whitespace from GUID
+		})
+	}
+
+	return sheet
+}
+
+func headerUnderline(header []string) []string {
+	underline := []string{}
+	for _, title := range header {
+		underline = append(underline, strings.Repeat("-", len(title)))
+	}
+
+	return underline
+}
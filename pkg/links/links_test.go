@@ -0,0 +1,97 @@
+package links
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLink_IsDead(t *testing.T) {
+	tests := []struct {
+		name string
+		link Link
+		want bool
+	}{
+		{name: "Live", link: Link{Status: 200}, want: false},
+		{name: "Not-Found", link: Link{Status: 404}, want: true},
+		{name: "Gone", link: Link{Status: 410}, want: true},
+		{name: "Forbidden", link: Link{Status: 403}, want: false},
+		{name: "Failed-Request", link: Link{Error: "connection refused"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.link.IsDead(); got != tt.want {
+				t.Errorf("IsDead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLink_IsPermanentRedirect(t *testing.T) {
+	tests := []struct {
+		name string
+		link Link
+		want bool
+	}{
+		{name: "No-Redirect", link: Link{Status: 200}, want: false},
+		{name: "Permanent", link: Link{Status: 200, RedirectURL: "https://go.dev/", Permanent: true}, want: true},
+		{name: "Temporary", link: Link{Status: 200, RedirectURL: "https://go.dev/", Permanent: false}, want: false},
+		{name: "Permanent-To-Dead", link: Link{Status: 404, RedirectURL: "https://go.dev/x", Permanent: true},
+			want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.link.IsPermanentRedirect(); got != tt.want {
+				t.Errorf("IsPermanentRedirect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinksTable(t *testing.T) {
+	links := []Link{
+		{URL: "http://golang.org/ ", Title: " Go", Folder: "toolbar", Status: 200, RedirectURL: "https://go.dev/",
+			Permanent: true, GUID: "bookmarkgd01"},
+	}
+
+	tests := []struct {
+		name         string
+		links        []Link
+		enableHeader bool
+		want         [][]string
+	}{
+		{
+			name:         "No-Links",
+			links:        nil,
+			enableHeader: true,
+			want:         nil,
+		},
+		{
+			name:         "With-Header",
+			links:        links,
+			enableHeader: true,
+			want: [][]string{
+				{"URL", "TITLE", "FOLDER", "STATUS", "REDIRECT-URL", "PERMANENT", "ERROR", "GUID"},
+				{"---", "-----", "------", "------", "------------", "---------", "-----", "----"},
+				{"http://golang.org/", "Go", "toolbar", "200", "https://go.dev/", "true", "", "bookmarkgd01"},
+			},
+		},
+		{
+			name:         "Without-Header",
+			links:        links,
+			enableHeader: false,
+			want: [][]string{
+				{"http://golang.org/", "Go", "toolbar", "200", "https://go.dev/", "true", "", "bookmarkgd01"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LinksTable(tt.links, tt.enableHeader); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LinksTable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
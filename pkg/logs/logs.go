@@ -74,6 +74,12 @@ func FromContext(ctx context.Context) Logger {
 	return newLogger(os.Stdout, defaultLevel)
 }
 
+// WithLogger returns the context with the logger, replacing the logger if any.
+// This is used to propagate sub-loggers created with additional fields.
+func WithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, key{}, logger)
+}
+
 // newLogger internally creates a new logger instance enabling
 // timestamp, caller info and specified level
 func newLogger(out io.Writer, level level) Logger {
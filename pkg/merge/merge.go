@@ -0,0 +1,292 @@
+package merge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/urls"
+)
+
+// Result is the summary of merge
+type Result struct {
+	Folders    int `json:"folders"`
+	Bookmarks  int `json:"bookmarks"`
+	Duplicates int `json:"duplicates"`
+	Conflicts  int `json:"conflicts"`
+}
+
+// Merger combines the bookmarks of multiple sources, e.g., profiles or exports, into one tree
+type Merger struct {
+	policy  constants.Constant[constants.Policy]
+	options urls.Options
+	sources [][]bookmark.Bookmark
+	logger  logs.Logger
+}
+
+// node is a folder or a bookmark of the merged tree, along with its order of appearance in sources
+type node struct {
+	record    bookmark.Bookmark
+	seq       int
+	synthetic bool // Folder created from the path of records, in absence of the folder record
+	children  []*node
+}
+
+// candidate is a bookmark of a source, along with its order of appearance in sources
+type candidate struct {
+	bookmark.Located
+	seq int
+}
+
+// NewMerger initiates new Merger with the conflict policy
+func NewMerger(ctx context.Context, policy constants.Constant[constants.Policy]) *Merger {
+	return &Merger{
+		policy: policy,
+		// Same as the dedupe filter's, so that the merged and the deduplicated bookmarks agree on duplicates
+		options: urls.Options{
+			DropTrackingParams: true,
+			DropTrailingSlash:  true,
+		},
+		sources: nil,
+		logger:  logs.FromContext(ctx),
+	}
+}
+
+// Source appends the bookmarks of a source to merger. The sources are ordered,
+// i.e., the first source is preferred with prefer-first policy.
+// Both receiver and return value are of same type to implement builder's pattern.
+func (m *Merger) Source(bookmarks []bookmark.Bookmark) *Merger {
+	m.sources = append(m.sources, bookmarks)
+
+	return m
+}
+
+// Merge combines the sources into one tree of raw records, with new IDs, parents and positions.
+// The folders are united by their paths, and the bookmarks are deduplicated by normalized URL,
+// with the tracking params and the trailing slash dropped, as by the dedupe filter.
+// The bookmarks of the same URL conflict when their titles or folders differ, and the conflict policy
+// decides which of them is kept:
+//  1. newest - The most recently modified bookmark, by last-modified time.
+//  2. prefer-first - The bookmark of the first source having it.
+//  3. keep-both - All the distinct bookmarks, and only the exact duplicates are dropped.
+//
+// The tags of the dropped duplicates are added to the kept bookmark.
+// The separators and the tag folders are not merged.
+func (m *Merger) Merge() ([]bookmark.Bookmark, Result, error) {
+	var (
+		result     Result
+		seq        int
+		groups     = map[string][]*candidate{}
+		groupOrder []string
+
+		root    = &node{record: bookmark.Bookmark{GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder}}
+		folders = map[string]*node{"": root}
+	)
+
+	switch m.policy {
+	case constants.NewestPolicy, constants.PreferFirstPolicy, constants.KeepBothPolicy:
+		// Known policies
+	default:
+		return nil, Result{}, fmt.Errorf("invalid conflict policy %q (available policies: [%s, %s, %s])", m.policy,
+			constants.KeepBothPolicy, constants.NewestPolicy, constants.PreferFirstPolicy)
+	}
+
+	for _, source := range m.sources {
+		for _, located := range bookmark.Locate(source) {
+			seq++
+
+			switch current := (&bookmark.Node{Bookmark: located.Bookmark}); {
+			case current.IsFolder():
+				folder := ensureFolder(folders, bookmark.JoinPath(located.Path, located.Title), seq)
+				if folder.synthetic {
+					// When the folder was created from a path, the first folder record takes its place
+					folder.record, folder.synthetic = located.Bookmark, false
+				}
+
+				mergeFolderDates(&folder.record, located.Bookmark)
+			case current.IsSeparator(), located.URL == nil:
+				// Separators are positional, and don't survive merge of multiple folders
+			default:
+				key := m.options.Normalize(*located.URL)
+				if _, ok := groups[key]; !ok {
+					groupOrder = append(groupOrder, key)
+				}
+
+				groups[key] = append(groups[key], &candidate{Located: located, seq: seq})
+			}
+		}
+	}
+
+	for _, key := range groupOrder {
+		kept, duplicates, isConflict := m.resolve(groups[key])
+
+		result.Duplicates += duplicates
+		if isConflict {
+			result.Conflicts++
+		}
+
+		for _, c := range kept {
+			path := c.Path
+			if path == "" {
+				// Bookmarks can't be top-level in Firefox, so the orphans are kept in unfiled root
+				path = bookmark.UnfiledRoot
+			}
+
+			folder := ensureFolder(folders, path, c.seq)
+			folder.children = append(folder.children, &node{record: c.Bookmark, seq: c.seq})
+		}
+	}
+
+	records := flatten(root)
+
+	for _, b := range records {
+		if b.URL == nil {
+			result.Folders++
+		} else {
+			result.Bookmarks++
+		}
+	}
+
+	// Places root isn't counted as folder
+	result.Folders--
+
+	m.logger.Info().Interface("result", result).Stringer("policy", m.policy).Int("sources", len(m.sources)).
+		Msg("Successfully merged bookmarks")
+
+	return records, result, nil
+}
+
+// resolve picks the bookmarks to keep, from the bookmarks of the same normalized URL,
+// as per conflict policy. It returns the count of dropped duplicates, and whether the bookmarks conflict.
+func (m *Merger) resolve(group []*candidate) ([]*candidate, int, bool) {
+	var (
+		variants     = map[string]*candidate{}
+		variantOrder []*candidate
+	)
+
+	// Exact duplicates, i.e., of same title in same folder, are always merged
+	for _, c := range group {
+		key := bookmark.JoinPath(c.Path, strings.TrimSpace(c.Title))
+
+		if kept, ok := variants[key]; ok {
+			*kept = merged(m.policy, kept, c)
+			continue
+		}
+
+		copied := *c
+		variants[key] = &copied
+		variantOrder = append(variantOrder, &copied)
+	}
+
+	isConflict := len(variantOrder) > 1
+
+	if m.policy == constants.KeepBothPolicy || !isConflict {
+		return variantOrder, len(group) - len(variantOrder), isConflict
+	}
+
+	kept := variantOrder[0]
+	for _, c := range variantOrder[1:] {
+		*kept = merged(m.policy, kept, c)
+	}
+
+	return []*candidate{kept}, len(group) - 1, isConflict
+}
+
+// merged returns the candidate to keep among the kept candidate and the later candidate, as per policy.
+// The tags of both the candidates are kept, and so is the keyword of the dropped candidate in absence of one.
+func merged(policy constants.Constant[constants.Policy], kept, later *candidate) candidate {
+	winner, dropped := *kept, later
+	if policy == constants.NewestPolicy && later.LastModified.After(kept.LastModified) {
+		// When the later candidate is more recently modified
+		winner, dropped = *later, kept
+	}
+
+	winner.Tags = bookmark.UnionTags(kept.Tags, later.Tags)
+
+	if winner.Keyword == "" {
+		winner.Keyword = dropped.Keyword
+	}
+
+	return winner
+}
+
+// ensureFolder returns the folder of the path in merged tree, creating the missing folders in path
+func ensureFolder(folders map[string]*node, path string, seq int) *node {
+	if folder, ok := folders[path]; ok {
+		return folder
+	}
+
+	parentPath, title := "", path
+	if i := strings.LastIndex(path, bookmark.FolderPathDelimiter); i >= 0 {
+		parentPath, title = path[:i], path[i+1:]
+	}
+
+	parent := ensureFolder(folders, parentPath, seq)
+	folder := &node{
+		record:    bookmark.Bookmark{Title: title, Type: bookmark.TypeFolder},
+		seq:       seq,
+		synthetic: true,
+	}
+
+	if parentPath == "" {
+		// Top-level folders are Firefox's built-in roots
+		folder.record.GUID = bookmark.RootGUIDs[title]
+	}
+
+	folders[path] = folder
+	parent.children = append(parent.children, folder)
+
+	return folder
+}
+
+// flatten lists the records of merged tree in depth-first order, assigning IDs, parents and positions.
+// The GUIDs duplicated across sources are cleared, except for the first record having it.
+func flatten(root *node) []bookmark.Bookmark {
+	var (
+		merged []bookmark.Bookmark
+		walk   func(n *node, parent, position int)
+
+		guids = map[string]bool{}
+	)
+
+	walk = func(n *node, parent, position int) {
+		record := n.record
+		record.ID, record.Parent, record.Position = len(merged)+1, parent, position
+		record.Folder = ""
+
+		if guids[record.GUID] {
+			// When another record already holds the GUID
+			record.GUID = ""
+		}
+
+		guids[record.GUID] = record.GUID != ""
+		merged = append(merged, record)
+
+		sort.SliceStable(n.children, func(i, j int) bool {
+			return n.children[i].seq < n.children[j].seq
+		})
+
+		for i, child := range n.children {
+			walk(child, record.ID, i)
+		}
+	}
+
+	walk(root, 0, 0)
+
+	return merged
+}
+
+// mergeFolderDates keeps the earliest date of addition and the latest date of modification of the folder
+func mergeFolderDates(folder *bookmark.Bookmark, other bookmark.Bookmark) {
+	if !other.DateAdded.IsZero() && (folder.DateAdded.IsZero() || other.DateAdded.Before(folder.DateAdded)) {
+		folder.DateAdded = other.DateAdded
+	}
+
+	if other.LastModified.After(folder.LastModified) {
+		folder.LastModified = other.LastModified
+	}
+}
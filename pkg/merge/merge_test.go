@@ -0,0 +1,228 @@
+package merge
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vaguecoder/firefox-backups/pkg/bookmark"
+	"github.com/vaguecoder/firefox-backups/pkg/constants"
+	"github.com/vaguecoder/firefox-backups/pkg/logs"
+	"github.com/vaguecoder/firefox-backups/pkg/util"
+)
+
+var ptrStr = util.PtrStr
+
+// source returns the places root, toolbar and unfiled roots, and the records under them
+func source(records ...bookmark.Bookmark) []bookmark.Bookmark {
+	return append([]bookmark.Bookmark{
+		{ID: 1, Parent: 0, GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder},
+		{ID: 2, Parent: 1, GUID: "toolbar_____", Title: bookmark.ToolbarRoot, Type: bookmark.TypeFolder},
+		{ID: 3, Parent: 1, GUID: "unfiled_____", Title: bookmark.UnfiledRoot, Type: bookmark.TypeFolder, Position: 1},
+	}, records...)
+}
+
+// outline returns the records as "<path>|<title>|<url>|<tags>" lines, with path rebuilt from the parents
+func outline(records []bookmark.Bookmark) []string {
+	var (
+		lines []string
+		paths = map[int]string{}
+	)
+
+	for _, b := range records {
+		path := paths[b.Parent]
+		if b.Parent != 0 {
+			// Places root isn't in the paths
+			paths[b.ID] = path + "/" + b.Title
+		}
+
+		url := ""
+		if b.URL != nil {
+			url = *b.URL
+		}
+
+		lines = append(lines, fmt.Sprintf("%s|%s|%s|%v", path, b.Title, url, b.Tags))
+	}
+
+	return lines
+}
+
+func TestMerger_Merge(t *testing.T) {
+	type args struct {
+		policy  constants.Constant[constants.Policy]
+		sources [][]bookmark.Bookmark
+	}
+
+	var (
+		older = time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+		newer = time.Date(2023, time.March, 4, 0, 0, 0, 0, time.UTC)
+
+		laptop = source(
+			bookmark.Bookmark{ID: 10, Parent: 2, GUID: "folder-guid1", Title: "GitHub", Type: bookmark.TypeFolder},
+			bookmark.Bookmark{ID: 11, Parent: 10, GUID: "bookmarkgd01", Title: "Vague Coder",
+				URL: ptrStr("https://github.com/vaguecoder"), Type: bookmark.TypeBookmark, LastModified: older,
+				Tags: []string{"code"}},
+			bookmark.Bookmark{ID: 12, Parent: 10, GUID: "separator001", Type: bookmark.TypeSeparator, Position: 1},
+		)
+		desktop = source(
+			bookmark.Bookmark{ID: 20, Parent: 2, GUID: "folder-guid2", Title: "GitHub", Type: bookmark.TypeFolder},
+			bookmark.Bookmark{ID: 21, Parent: 20, GUID: "bookmarkgd02", Title: "VagueCoder on GitHub",
+				URL: ptrStr("HTTPS://GitHub.com:443/vaguecoder"), Type: bookmark.TypeBookmark, LastModified: newer,
+				Tags: []string{"profile"}},
+			bookmark.Bookmark{ID: 22, Parent: 3, GUID: "bookmarkgd03", Title: "Go", URL: ptrStr("https://go.dev/"),
+				Type: bookmark.TypeBookmark},
+		)
+		denormalized = []bookmark.Bookmark{
+			{Title: "Go", URL: ptrStr("https://go.dev"), Folder: "unfiled", Type: bookmark.TypeBookmark},
+			{Title: "Gorilla Mux", URL: ptrStr("https://github.com/gorilla/mux"), Folder: "toolbar/GitHub/Forks",
+				Type: bookmark.TypeBookmark},
+		}
+	)
+
+	tests := []struct {
+		name       string
+		args       args
+		want       []string
+		wantResult Result
+		wantErr    bool
+	}{
+		{
+			name: "Newest",
+			args: args{
+				policy:  constants.NewestPolicy,
+				sources: [][]bookmark.Bookmark{laptop, desktop},
+			},
+			want: []string{
+				"|||[]",
+				"|toolbar||[]",
+				"/toolbar|GitHub||[]",
+				"/toolbar/GitHub|VagueCoder on GitHub|HTTPS://GitHub.com:443/vaguecoder|[code profile]",
+				"|unfiled||[]",
+				"/unfiled|Go|https://go.dev/|[]",
+			},
+			wantResult: Result{Folders: 3, Bookmarks: 2, Duplicates: 1, Conflicts: 1},
+		},
+		{
+			name: "Prefer-First",
+			args: args{
+				policy:  constants.PreferFirstPolicy,
+				sources: [][]bookmark.Bookmark{laptop, desktop},
+			},
+			want: []string{
+				"|||[]",
+				"|toolbar||[]",
+				"/toolbar|GitHub||[]",
+				"/toolbar/GitHub|Vague Coder|https://github.com/vaguecoder|[code profile]",
+				"|unfiled||[]",
+				"/unfiled|Go|https://go.dev/|[]",
+			},
+			wantResult: Result{Folders: 3, Bookmarks: 2, Duplicates: 1, Conflicts: 1},
+		},
+		{
+			name: "Keep-Both",
+			args: args{
+				policy:  constants.KeepBothPolicy,
+				sources: [][]bookmark.Bookmark{laptop, desktop},
+			},
+			want: []string{
+				"|||[]",
+				"|toolbar||[]",
+				"/toolbar|GitHub||[]",
+				"/toolbar/GitHub|Vague Coder|https://github.com/vaguecoder|[code]",
+				"/toolbar/GitHub|VagueCoder on GitHub|HTTPS://GitHub.com:443/vaguecoder|[profile]",
+				"|unfiled||[]",
+				"/unfiled|Go|https://go.dev/|[]",
+			},
+			wantResult: Result{Folders: 3, Bookmarks: 3, Duplicates: 0, Conflicts: 1},
+		},
+		{
+			name: "Denormalized-Source-Folders-By-Path",
+			args: args{
+				policy:  constants.KeepBothPolicy,
+				sources: [][]bookmark.Bookmark{desktop, denormalized},
+			},
+			want: []string{
+				"|||[]",
+				"|toolbar||[]",
+				"/toolbar|GitHub||[]",
+				"/toolbar/GitHub|VagueCoder on GitHub|HTTPS://GitHub.com:443/vaguecoder|[profile]",
+				"/toolbar/GitHub|Forks||[]",
+				"/toolbar/GitHub/Forks|Gorilla Mux|https://github.com/gorilla/mux|[]",
+				"|unfiled||[]",
+				"/unfiled|Go|https://go.dev/|[]",
+			},
+			wantResult: Result{Folders: 4, Bookmarks: 3, Duplicates: 1, Conflicts: 0},
+		},
+		{
+			name: "Tracking-Params-And-Trailing-Slash",
+			args: args{
+				policy: constants.PreferFirstPolicy,
+				sources: [][]bookmark.Bookmark{
+					source(bookmark.Bookmark{ID: 10, Parent: 3, GUID: "bookmarkgd04", Title: "Go Docs",
+						URL: ptrStr("https://go.dev/doc/?utm_source=newsletter"), Type: bookmark.TypeBookmark}),
+					source(bookmark.Bookmark{ID: 10, Parent: 3, GUID: "bookmarkgd05", Title: "Go Docs",
+						URL: ptrStr("https://go.dev/doc"), Type: bookmark.TypeBookmark}),
+				},
+			},
+			want: []string{
+				"|||[]",
+				"|toolbar||[]",
+				"|unfiled||[]",
+				"/unfiled|Go Docs|https://go.dev/doc/?utm_source=newsletter|[]",
+			},
+			wantResult: Result{Folders: 2, Bookmarks: 1, Duplicates: 1, Conflicts: 0},
+		},
+		{
+			name: "Invalid-Policy",
+			args: args{
+				policy:  "oldest",
+				sources: [][]bookmark.Bookmark{laptop},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := logs.SilentLogger(context.Background())
+
+			merger := NewMerger(ctx, tt.args.policy)
+			for _, s := range tt.args.sources {
+				merger = merger.Source(s)
+			}
+
+			got, result, err := merger.Merge()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from merge")
+				return
+			}
+
+			assert.NoError(t, err, "Unexpected error from merge")
+			assert.Equal(t, tt.want, outline(got), "Mismatch of merged tree")
+			assert.Equal(t, tt.wantResult, result, "Mismatch of merge result")
+		})
+	}
+}
+
+func TestMerger_Merge_IDs(t *testing.T) {
+	ctx, _ := logs.SilentLogger(context.Background())
+
+	got, _, err := NewMerger(ctx, constants.KeepBothPolicy).
+		Source([]bookmark.Bookmark{
+			{ID: 7, Parent: 3, GUID: "bookmarkgd01", Title: "A", URL: ptrStr("https://a.example/"), Folder: "menu"},
+			{ID: 8, Parent: 3, GUID: "bookmarkgd01", Title: "B", URL: ptrStr("https://b.example/"), Folder: "menu"},
+		}).
+		Merge()
+	assert.NoError(t, err, "Unexpected error from merge")
+
+	want := []bookmark.Bookmark{
+		{ID: 1, Parent: 0, Position: 0, GUID: bookmark.PlacesRootGUID, Type: bookmark.TypeFolder},
+		{ID: 2, Parent: 1, Position: 0, GUID: "menu________", Title: bookmark.MenuRoot, Type: bookmark.TypeFolder},
+		{ID: 3, Parent: 2, Position: 0, GUID: "bookmarkgd01", Title: "A", URL: ptrStr("https://a.example/")},
+		{ID: 4, Parent: 2, Position: 1, GUID: "", Title: "B", URL: ptrStr("https://b.example/")},
+	}
+
+	assert.Equal(t, want, got, "Mismatch of IDs, parents, positions or GUIDs")
+}
@@ -13,6 +13,20 @@ type DBConnection struct {
 	mock.Mock
 }
 
+// Close provides a mock function with given fields:
+func (_m *DBConnection) Close() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Query provides a mock function with given fields: query, args
 func (_m *DBConnection) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	var _ca []interface{}
@@ -0,0 +1,266 @@
+package mozlz4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// magic is the magic number at the start of mozlz4 files
+	magic = "mozLz40\x00"
+
+	// headerSize is the size of magic number followed by
+	// uint32 little-endian size of decompressed data
+	headerSize = len(magic) + 4
+
+	// minMatch is the minimum length of a match in LZ4 block format
+	minMatch = 4
+
+	// extendedLength is the value of 4-bit length in token
+	// denoting the length continues in following bytes
+	extendedLength = 15
+
+	// Compression constraints of LZ4 block format:
+	// 	1. The last match must start at least 12 bytes before the end of block.
+	// 	2. The last 5 bytes are always literals.
+	// 	3. The match offset is a 16-bit value.
+	matchFindLimit = 12
+	lastLiterals   = 5
+	maxOffset      = 0xffff
+
+	// hashLog is the number of bits of hash table indexes for match search
+	hashLog = 16
+
+	// maxRatio is the maximum decompressed bytes per byte of LZ4 block,
+	// as every byte of extended length adds at most 255 to a match
+	maxRatio = 0xff
+)
+
+// Compress encodes the data as mozlz4, i.e., the magic number, the size of
+// data and the LZ4 block, as read by Firefox from bookmarkbackups/*.jsonlz4 files.
+func Compress(data []byte) []byte {
+	compressed := make([]byte, headerSize, headerSize+len(data)+len(data)/0xff+16)
+
+	copy(compressed, magic)
+	binary.LittleEndian.PutUint32(compressed[len(magic):headerSize], uint32(len(data)))
+
+	return compressBlock(compressed, data)
+}
+
+// Decompress decodes the mozlz4 data, i.e., the magic number, the size of
+// decompressed data and the LZ4 block, as written by Firefox in
+// bookmarkbackups/*.jsonlz4 files.
+func Decompress(data []byte) ([]byte, error) {
+	if len(data) < headerSize || !bytes.Equal(data[:len(magic)], []byte(magic)) {
+		// When the data doesn't begin with mozlz4 header
+		return nil, fmt.Errorf("invalid mozlz4 header")
+	}
+
+	size := binary.LittleEndian.Uint32(data[len(magic):headerSize])
+	if uint64(size) > uint64(len(data)-headerSize)*maxRatio {
+		// When the size in header can't be decompressed from the block, i.e., corrupted header.
+		// The size is checked ahead of the allocation of decompressed data.
+		return nil, fmt.Errorf("size %d in header is implausible for the block of %d bytes",
+			size, len(data)-headerSize)
+	}
+
+	decompressed, err := decompressBlock(data[headerSize:], int(size))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress LZ4 block: %v", err)
+	}
+
+	if len(decompressed) != int(size) {
+		// When the block doesn't match the size in header
+		return nil, fmt.Errorf("decompressed size %d doesn't match the size %d in header",
+			len(decompressed), size)
+	}
+
+	return decompressed, nil
+}
+
+// compressBlock encodes src as LZ4 block sequences of literals and matches,
+// and appends them to dst. Matches are searched greedily on the hash of 4 bytes.
+func compressBlock(dst, src []byte) []byte {
+	var (
+		index, anchor, reference, matchLen int
+		sequence                           uint32
+		hash                               uint32
+
+		// Positions of the last occurrence of hashes, offset by 1 as 0 is missing
+		table = make([]int, 1<<hashLog)
+		limit = len(src) - matchFindLimit
+	)
+
+	for index < limit {
+		sequence = binary.LittleEndian.Uint32(src[index:])
+		hash = (sequence * 2654435761) >> (32 - hashLog)
+
+		reference = table[hash] - 1
+		table[hash] = index + 1
+
+		if reference < 0 || index-reference > maxOffset ||
+			binary.LittleEndian.Uint32(src[reference:]) != sequence {
+			// When there's no match for the current position
+			index++
+			continue
+		}
+
+		// Extend the match as long as it leaves last literals
+		matchLen = minMatch
+		for index+matchLen < len(src)-lastLiterals && src[reference+matchLen] == src[index+matchLen] {
+			matchLen++
+		}
+
+		dst = appendSequence(dst, src[anchor:index], index-reference, matchLen)
+
+		index += matchLen
+		anchor = index
+	}
+
+	// The last sequence has only literals
+	return appendSequence(dst, src[anchor:], 0, 0)
+}
+
+// appendSequence appends a sequence of token, literals and match to dst.
+// A zero matchLen denotes the last sequence, which has no match.
+func appendSequence(dst, literals []byte, offset, matchLen int) []byte {
+	var (
+		token      byte
+		literalLen = len(literals)
+	)
+
+	if literalLen >= extendedLength {
+		token = extendedLength << 4
+	} else {
+		token = byte(literalLen) << 4
+	}
+
+	if matchLen > 0 {
+		if matchLen-minMatch >= extendedLength {
+			token |= extendedLength
+		} else {
+			token |= byte(matchLen - minMatch)
+		}
+	}
+
+	dst = append(dst, token)
+	if literalLen >= extendedLength {
+		dst = appendExtendedLength(dst, literalLen-extendedLength)
+	}
+	dst = append(dst, literals...)
+
+	if matchLen == 0 {
+		// When it is the last sequence
+		return dst
+	}
+
+	dst = append(dst, byte(offset), byte(offset>>8))
+	if matchLen-minMatch >= extendedLength {
+		dst = appendExtendedLength(dst, matchLen-minMatch-extendedLength)
+	}
+
+	return dst
+}
+
+// appendExtendedLength appends the length exceeding the token as
+// bytes of 255 followed by the remainder
+func appendExtendedLength(dst []byte, length int) []byte {
+	for ; length >= 0xff; length -= 0xff {
+		dst = append(dst, 0xff)
+	}
+
+	return append(dst, byte(length))
+}
+
+// decompressBlock decodes the LZ4 block sequences of literals and matches,
+// failing as soon as the decompressed data exceeds the size
+func decompressBlock(src []byte, size int) ([]byte, error) {
+	var (
+		err                         error
+		index, literalLen, matchLen int
+		offset, matchStart          int
+		token                       byte
+
+		dst = make([]byte, 0, size)
+	)
+
+	for index < len(src) {
+		token = src[index]
+		index++
+
+		// Literals
+		literalLen = int(token >> 4)
+		if literalLen == extendedLength {
+			if literalLen, index, err = readExtendedLength(src, index, literalLen); err != nil {
+				return nil, err
+			}
+		}
+
+		if index+literalLen > len(src) {
+			return nil, fmt.Errorf("literals of length %d overflow the block at %d", literalLen, index)
+		}
+
+		if len(dst)+literalLen > size {
+			return nil, fmt.Errorf("literals of length %d at %d exceed the size %d", literalLen, index, size)
+		}
+
+		dst = append(dst, src[index:index+literalLen]...)
+		index += literalLen
+
+		if index == len(src) {
+			// When the sequence is the last one, it has only literals
+			break
+		}
+
+		// Match
+		if index+2 > len(src) {
+			return nil, fmt.Errorf("missing match offset at %d", index)
+		}
+
+		offset = int(src[index]) | int(src[index+1])<<8
+		index += 2
+
+		matchLen = int(token & 0x0f)
+		if matchLen == extendedLength {
+			if matchLen, index, err = readExtendedLength(src, index, matchLen); err != nil {
+				return nil, err
+			}
+		}
+		matchLen += minMatch
+
+		matchStart = len(dst) - offset
+		if offset == 0 || matchStart < 0 {
+			return nil, fmt.Errorf("invalid match offset %d at %d", offset, index)
+		}
+
+		if len(dst)+matchLen > size {
+			return nil, fmt.Errorf("match of length %d at %d exceeds the size %d", matchLen, index, size)
+		}
+
+		// Copy byte by byte as the match can overlap the bytes being copied
+		for i := 0; i < matchLen; i++ {
+			dst = append(dst, dst[matchStart+i])
+		}
+	}
+
+	return dst, nil
+}
+
+// readExtendedLength adds up the bytes following the token to the length,
+// until a byte other than 255 is read
+func readExtendedLength(src []byte, index, length int) (int, int, error) {
+	for {
+		if index >= len(src) {
+			return 0, 0, fmt.Errorf("incomplete length at %d", index)
+		}
+
+		b := src[index]
+		index++
+		length += int(b)
+
+		if b != 0xff {
+			return length, index, nil
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package mozlz4
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withHeader prefixes the LZ4 block with mozlz4 magic number and decompressed size
+func withHeader(size uint32, block []byte) []byte {
+	data := []byte(magic)
+	data = append(data, byte(size), byte(size>>8), byte(size>>16), byte(size>>24))
+
+	return append(data, block...)
+}
+
+func TestDecompress(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name:    "Literals-Only",
+			data:    withHeader(5, []byte{0x50, 'h', 'e', 'l', 'l', 'o'}),
+			want:    []byte("hello"),
+			wantErr: false,
+		},
+		{
+			name: "Overlapping-Match",
+			// Literal "ab", then match of length 6 at offset 2, then last literal "!"
+			data:    withHeader(9, []byte{0x22, 'a', 'b', 0x02, 0x00, 0x10, '!'}),
+			want:    []byte("abababab!"),
+			wantErr: false,
+		},
+		{
+			name: "Extended-Literal-Length",
+			data: withHeader(20, append([]byte{0xf0, 0x05}, []byte("01234567890123456789")...)),
+			want: []byte("01234567890123456789"),
+		},
+		{
+			name:    "Empty-Data",
+			data:    withHeader(0, []byte{}),
+			want:    []byte{},
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Invalid-Magic",
+			data:    append([]byte("mozLz41\x00"), 0x05, 0x00, 0x00, 0x00, 0x50, 'h', 'e', 'l', 'l', 'o'),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Short-Header",
+			data:    []byte("mozLz4"),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Size-Mismatch",
+			data:    withHeader(6, []byte{0x50, 'h', 'e', 'l', 'l', 'o'}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Literals-Overflow",
+			data:    withHeader(5, []byte{0x50, 'h', 'e'}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Implausible-Size",
+			data:    withHeader(0xffffffff, []byte{0x50, 'h', 'e', 'l', 'l', 'o'}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Literals-Exceed-Size",
+			data:    withHeader(4, []byte{0x50, 'h', 'e', 'l', 'l', 'o'}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Match-Exceeds-Size",
+			data:    withHeader(5, []byte{0x22, 'a', 'b', 0x02, 0x00, 0x10, '!'}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Invalid-Offset",
+			data:    withHeader(9, []byte{0x22, 'a', 'b', 0x05, 0x00, 0x10, '!'}),
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decompress(tt.data)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from decompress")
+			} else {
+				assert.NoError(t, err, "Unexpected error from decompress")
+			}
+
+			assert.Equal(t, tt.want, got, "Mismatch of decompressed data")
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "Empty-Data",
+			data: []byte{},
+		},
+		{
+			name: "Short-Data",
+			data: []byte("hello"),
+		},
+		{
+			name: "Repetitive-Data",
+			data: bytes.Repeat([]byte(`{"guid":"menu________","title":"menu","typeCode":2},`), 500),
+		},
+		{
+			name: "Long-Run",
+			data: bytes.Repeat([]byte{'a'}, 70000),
+		},
+		{
+			name: "Non-Repetitive-Data",
+			data: func() []byte {
+				data := make([]byte, 1000)
+				for i := range data {
+					data[i] = byte(i * 7 % 251)
+				}
+				return data
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compressed := Compress(tt.data)
+			assert.Equal(t, []byte(magic), compressed[:len(magic)], "Mismatch of magic number")
+
+			got, err := Decompress(compressed)
+			assert.NoError(t, err, "Unexpected error from decompress")
+			assert.Equal(t, tt.data, got, "Mismatch of round-trip data")
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package profiles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// iniSection is a named section of an INI file with its key-value pairs
+type iniSection struct {
+	name   string
+	values map[string]string
+}
+
+// parseINI parses the INI files written by Firefox, i.e., profiles.ini and installs.ini.
+// The sections are returned in the order of the file. Blank lines and comments are skipped.
+func parseINI(r io.Reader) ([]iniSection, error) {
+	var (
+		line     string
+		lineNum  int
+		index    int
+		sections []iniSection
+		current  *iniSection
+
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		lineNum++
+		line = strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#"):
+			// Blank lines and comments
+			continue
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("unterminated section header at line %d: %q", lineNum, line)
+			}
+
+			sections = append(sections, iniSection{
+				name:   strings.TrimSpace(line[1 : len(line)-1]),
+				values: map[string]string{},
+			})
+			current = &sections[len(sections)-1]
+		default:
+			if index = strings.Index(line, "="); index < 0 {
+				return nil, fmt.Errorf("missing '=' at line %d: %q", lineNum, line)
+			}
+
+			if current == nil {
+				return nil, fmt.Errorf("key outside of section at line %d: %q", lineNum, line)
+			}
+
+			current.values[strings.TrimSpace(line[:index])] = strings.TrimSpace(line[index+1:])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read INI: %v", err)
+	}
+
+	return sections, nil
+}
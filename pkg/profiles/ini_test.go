@@ -0,0 +1,72 @@
+package profiles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseINI(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []iniSection
+		wantErr bool
+	}{
+		{
+			name: "Valid-Case",
+			input: strings.Join([]string{
+				`; comment`,
+				`[Profile0]`,
+				`Name = default-release`,
+				`Path=Profiles/abc.default-release`,
+				``,
+				`# comment`,
+				`[General]`,
+				`StartWithLastProfile=1`,
+			}, "\n"),
+			want: []iniSection{
+				{name: "Profile0", values: map[string]string{"Name": "default-release", "Path": "Profiles/abc.default-release"}},
+				{name: "General", values: map[string]string{"StartWithLastProfile": "1"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Empty-Input",
+			input:   "",
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Unterminated-Section",
+			input:   "[Profile0\nName=default",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Missing-Equals",
+			input:   "[Profile0]\nName",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Failure-Key-Outside-Section",
+			input:   "Name=default",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseINI(strings.NewReader(tt.input))
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from parse")
+			} else {
+				assert.NoError(t, err, "Unexpected error from parse")
+			}
+
+			assert.Equal(t, tt.want, got, "Mismatch of sections")
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	profilesINI  = `profiles.ini`
+	installsINI  = `installs.ini`
+	placesDBFile = `places.sqlite`
+
+	// Section name prefix of profiles in profiles.ini
+	profileSectionPrefix = `Profile`
+)
+
+// Profile is a Firefox profile listed in profiles.ini
+type Profile struct {
+	Name      string `json:"name" yaml:"name"`
+	Path      string `json:"path" yaml:"path"`
+	Root      string `json:"root" yaml:"root"`
+	IsDefault bool   `json:"default" yaml:"default"`
+}
+
+// PlacesFile returns the path of places.sqlite in the profile directory
+func (p Profile) PlacesFile() string {
+	return filepath.Join(p.Path, placesDBFile)
+}
+
+// DefaultRoots returns the standard locations of Firefox's profiles.ini on Linux,
+// relative to the home directory: native package, Flatpak and Snap respectively
+func DefaultRoots(home string) []string {
+	return []string{
+		filepath.Join(home, ".mozilla", "firefox"),
+		filepath.Join(home, ".var", "app", "org.mozilla.firefox", ".mozilla", "firefox"),
+		filepath.Join(home, "snap", "firefox", "common", ".mozilla", "firefox"),
+	}
+}
+
+// Operator discovers the profiles in the roots, i.e., directories holding profiles.ini
+type Operator struct {
+	roots []string
+}
+
+// NewOperator initializes new Operator on the roots.
+// The roots are searched in order, and the roots without profiles.ini are skipped.
+// A root provided more than once, by the same cleaned path, is searched once.
+func NewOperator(roots ...string) *Operator {
+	var (
+		uniqueRoots []string
+		seen        = make(map[string]bool, len(roots))
+	)
+
+	for _, root := range roots {
+		if root = filepath.Clean(root); !seen[root] {
+			seen[root] = true
+			uniqueRoots = append(uniqueRoots, root)
+		}
+	}
+
+	return &Operator{
+		roots: uniqueRoots,
+	}
+}
+
+// List returns the profiles in all the roots, in the order of roots and profiles.ini.
+// A profile directory listed in more than one root is returned once, from the earliest root.
+func (o *Operator) List() ([]Profile, error) {
+	var (
+		err          error
+		rootProfiles []Profile
+		profiles     []Profile
+
+		paths = map[string]bool{}
+	)
+
+	for _, root := range o.roots {
+		rootProfiles, err = listRoot(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list profiles in %q: %v", root, err)
+		}
+
+		for _, profile := range rootProfiles {
+			if path := filepath.Clean(profile.Path); !paths[path] {
+				paths[path] = true
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+
+	return profiles, nil
+}
+
+// UniqueNames returns the names of the profiles, in their order, to tell the profiles apart in filenames.
+// A name shared by the profiles of multiple roots, e.g., default-release of native and Flatpak installs,
+// is replaced by the name of the profile directory, e.g., abc.default-release.
+func UniqueNames(profiles []Profile) []string {
+	var (
+		names  = make([]string, len(profiles))
+		counts = make(map[string]int, len(profiles))
+	)
+
+	for _, profile := range profiles {
+		counts[profile.Name]++
+	}
+
+	for i, profile := range profiles {
+		names[i] = profile.Name
+		if counts[profile.Name] > 1 {
+			names[i] = filepath.Base(profile.Path)
+		}
+	}
+
+	return names
+}
+
+// Find returns the profile by name. When the name is found in multiple roots,
+// the profile in the earliest root is returned.
+func (o *Operator) Find(name string) (Profile, error) {
+	var (
+		names []string
+
+		profiles, err = o.List()
+	)
+
+	if err != nil {
+		return Profile{}, err
+	}
+
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+
+		names = append(names, profile.Name)
+	}
+
+	if len(profiles) == 0 {
+		return Profile{}, fmt.Errorf("profile %q not found, no profiles in [%s]", name, strings.Join(o.roots, ", "))
+	}
+
+	return Profile{}, fmt.Errorf("profile %q not found (available profiles: [%s])", name, strings.Join(names, ", "))
+}
+
+// listRoot parses profiles.ini and installs.ini in the root.
+// The profile is marked default if an installation of Firefox defaults to it,
+// or in absence of installations, if profiles.ini marks it as default.
+func listRoot(root string) ([]Profile, error) {
+	var (
+		err      error
+		path     string
+		sections []iniSection
+		installs []iniSection
+		profiles []Profile
+
+		installDefaults = map[string]bool{}
+		legacyDefaults  = map[string]bool{}
+	)
+
+	sections, err = readINI(filepath.Join(root, profilesINI))
+	if errors.Is(err, fs.ErrNotExist) {
+		// When the root has no profiles, i.e., Firefox isn't installed in this location
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// The defaults of installations are in both installs.ini and Install sections of profiles.ini.
+	// installs.ini is optional, as older versions of Firefox don't write it.
+	installs, err = readINI(filepath.Join(root, installsINI))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	for _, section := range append(installs, sections...) {
+		if section.values["Default"] == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(section.name, profileSectionPrefix):
+			// Profile sections have Default=1 for the legacy default profile
+			if section.values["Default"] == "1" {
+				legacyDefaults[section.values["Path"]] = true
+			}
+		case isKnownSection(section.name):
+			// General sections don't point to profiles
+		default:
+			// Install sections of profiles.ini, and the sections named by install hash in installs.ini
+			installDefaults[section.values["Default"]] = true
+		}
+	}
+
+	for _, section := range sections {
+		if !strings.HasPrefix(section.name, profileSectionPrefix) || section.values["Path"] == "" {
+			continue
+		}
+
+		path = section.values["Path"]
+		profile := Profile{
+			Name: section.values["Name"],
+			Path: path,
+			Root: root,
+		}
+
+		if len(installDefaults) > 0 {
+			profile.IsDefault = installDefaults[path]
+		} else {
+			profile.IsDefault = legacyDefaults[path]
+		}
+
+		if section.values["IsRelative"] != "0" {
+			// Relative paths are relative to the root, and always use forward slash
+			profile.Path = filepath.Join(root, filepath.FromSlash(path))
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+// isKnownSection reports whether the section of profiles.ini is neither a profile nor an installation
+func isKnownSection(name string) bool {
+	return name == "General" || name == "BackgroundTasksProfiles"
+}
+
+// readINI opens and parses the INI file
+func readINI(filename string) ([]iniSection, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sections, err := parseINI(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %v", filename, err)
+	}
+
+	return sections, nil
+}
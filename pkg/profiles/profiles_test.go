@@ -0,0 +1,188 @@
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRoot writes the INI files in a new root directory.
+// Blank content skips the respective file.
+func writeRoot(t *testing.T, profilesContent, installsContent string) string {
+	root := t.TempDir()
+
+	if profilesContent != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(root, profilesINI), []byte(profilesContent), 0600),
+			"Unexpected error while writing profiles.ini")
+	}
+
+	if installsContent != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(root, installsINI), []byte(installsContent), 0600),
+			"Unexpected error while writing installs.ini")
+	}
+
+	return root
+}
+
+func TestOperator_List(t *testing.T) {
+	const profilesContent = `[Install4F96D1932A9F858E]
+Default=Profiles/abc.default-release
+Locked=1
+
+[Profile1]
+Name=default
+IsRelative=1
+Path=Profiles/xyz.default
+Default=1
+
+[Profile0]
+Name=default-release
+IsRelative=1
+Path=Profiles/abc.default-release
+
+[Profile2]
+Name=work
+IsRelative=0
+Path=/opt/firefox/work
+
+[General]
+StartWithLastProfile=1
+Version=2
+`
+
+	var (
+		modernRoot  = writeRoot(t, profilesContent, "[4F96D1932A9F858E]\nDefault=Profiles/abc.default-release\nLocked=1\n")
+		legacyRoot  = writeRoot(t, "[Profile0]\nName=default\nIsRelative=1\nPath=abc.default\nDefault=1\n", "")
+		invalidRoot = writeRoot(t, "[Profile0\n", "")
+		missingRoot = filepath.Join(t.TempDir(), "missing")
+		modernWant  = []Profile{
+			{Name: "default", Path: filepath.Join(modernRoot, "Profiles", "xyz.default"), Root: modernRoot, IsDefault: false},
+			{Name: "default-release", Path: filepath.Join(modernRoot, "Profiles", "abc.default-release"), Root: modernRoot, IsDefault: true},
+			{Name: "work", Path: "/opt/firefox/work", Root: modernRoot, IsDefault: false},
+		}
+	)
+
+	tests := []struct {
+		name    string
+		roots   []string
+		want    []Profile
+		wantErr bool
+	}{
+		{
+			name:    "Install-Defaults",
+			roots:   []string{modernRoot},
+			want:    modernWant,
+			wantErr: false,
+		},
+		{
+			name:  "Legacy-Default-And-Missing-Root",
+			roots: []string{missingRoot, legacyRoot},
+			want: []Profile{
+				{Name: "default", Path: filepath.Join(legacyRoot, "abc.default"), Root: legacyRoot, IsDefault: true},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Duplicate-Roots",
+			roots: []string{modernRoot, modernRoot + string(filepath.Separator), legacyRoot, modernRoot},
+			want: append(append([]Profile{}, modernWant...), Profile{Name: "default",
+				Path: filepath.Join(legacyRoot, "abc.default"), Root: legacyRoot, IsDefault: true}),
+			wantErr: false,
+		},
+		{
+			name:    "No-Roots",
+			roots:   []string{},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Invalid-Profiles-INI",
+			roots:   []string{modernRoot, invalidRoot},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewOperator(tt.roots...).List()
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from list")
+			} else {
+				assert.NoError(t, err, "Unexpected error from list")
+			}
+
+			assert.Equal(t, tt.want, got, "Mismatch of profiles")
+		})
+	}
+}
+
+func TestUniqueNames(t *testing.T) {
+	list := []Profile{
+		{Name: "default-release", Path: "/home/user/.mozilla/firefox/abc.default-release"},
+		{Name: "work", Path: "/home/user/.mozilla/firefox/xyz.work"},
+		{Name: "default-release", Path: "/home/user/.var/app/org.mozilla.firefox/.mozilla/firefox/def.default-release"},
+	}
+
+	assert.Equal(t, []string{"abc.default-release", "work", "def.default-release"}, UniqueNames(list),
+		"Mismatch of unique names")
+	assert.Equal(t, []string{}, UniqueNames(nil), "Mismatch of unique names of no profiles")
+}
+
+func TestOperator_Find(t *testing.T) {
+	var (
+		firstRoot  = writeRoot(t, "[Profile0]\nName=default\nPath=first.default\n", "")
+		secondRoot = writeRoot(t, "[Profile0]\nName=default\nPath=second.default\n[Profile1]\nName=work\nPath=work\n", "")
+	)
+
+	tests := []struct {
+		name    string
+		roots   []string
+		profile string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Earliest-Root-First",
+			roots:   []string{firstRoot, secondRoot},
+			profile: "default",
+			want:    filepath.Join(firstRoot, "first.default", "places.sqlite"),
+			wantErr: false,
+		},
+		{
+			name:    "Later-Root",
+			roots:   []string{firstRoot, secondRoot},
+			profile: "work",
+			want:    filepath.Join(secondRoot, "work", "places.sqlite"),
+			wantErr: false,
+		},
+		{
+			name:    "Failure-Unknown-Profile",
+			roots:   []string{firstRoot, secondRoot},
+			profile: "personal",
+			want:    "places.sqlite",
+			wantErr: true,
+		},
+		{
+			name:    "Failure-No-Profiles",
+			roots:   []string{},
+			profile: "default",
+			want:    "places.sqlite",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewOperator(tt.roots...).Find(tt.profile)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error from find")
+			} else {
+				assert.NoError(t, err, "Unexpected error from find")
+			}
+
+			assert.Equal(t, tt.want, got.PlacesFile(), "Mismatch of places.sqlite path")
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package urls
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Default ports of the schemes, dropped from the normalized URLs
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ftp":   "21",
+}
+
+// trackingParamPrefix is the prefix of Google Analytics' campaign params, e.g., utm_source
+const trackingParamPrefix = `utm_`
+
+// trackingParams are the query params added by ad networks, mail campaigns and analytics
+// for tracking the clicks. They don't change the page.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"gclsrc":  true,
+	"dclid":   true,
+	"msclkid": true,
+	"yclid":   true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+	"mkt_tok": true,
+}
+
+// Options are the optional rules of normalization, on top of the rules of Normalize
+type Options struct {
+	DropTrackingParams bool // Drop utm_* and other tracking params from query
+	DropTrailingSlash  bool // Drop the trailing slash of path, e.g., /docs/ is same as /docs
+	DropFragment       bool // Drop the fragment, i.e., the anchor within the page
+}
+
+// Normalize returns the URL in a canonical form, so the URLs of the same page
+// written differently compare equal. The scheme and host are lowercased,
+// the default port of the scheme is dropped, and the empty path of URLs with
+// host is written as "/". The URLs that can't be parsed are only trimmed.
+func Normalize(raw string) string {
+	return Options{}.Normalize(raw)
+}
+
+// Normalize returns the URL in a canonical form as of package level Normalize,
+// applying the optional rules enabled in the options as well. The query params
+// are sorted by key when the tracking params are dropped.
+func (o Options) Normalize(raw string) string {
+	raw = strings.TrimSpace(raw)
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		// When the URL isn't absolute, e.g., invalid records, it is left as is
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+
+	if host, port, err := net.SplitHostPort(u.Host); err == nil && port == defaultPorts[u.Scheme] {
+		// When the port is default of the scheme, e.g., 443 of https
+		u.Host = host
+		if strings.Contains(host, ":") {
+			// IPv6 address requires brackets
+			u.Host = "[" + host + "]"
+		}
+	}
+
+	if u.Host != "" && u.Path == "" && u.Opaque == "" {
+		// http://example.com and http://example.com/ are the same page
+		u.Path = "/"
+	}
+
+	if o.DropTrailingSlash && u.Opaque == "" {
+		// The root path is dropped as well, so http://example.com/ is same as http://example.com
+		u.Path = strings.TrimSuffix(u.Path, "/")
+		u.RawPath = strings.TrimSuffix(u.RawPath, "/")
+	}
+
+	if o.DropTrackingParams && u.RawQuery != "" {
+		// When the query is malformed, it is left as is
+		if query, err := url.ParseQuery(u.RawQuery); err == nil {
+			for key := range query {
+				if isTrackingParam(key) {
+					query.Del(key)
+				}
+			}
+
+			u.RawQuery = query.Encode()
+		}
+	}
+
+	if o.DropTrackingParams && u.RawQuery == "" {
+		// Dangling "?" of the query without params
+		u.ForceQuery = false
+	}
+
+	if o.DropFragment {
+		u.Fragment, u.RawFragment = "", ""
+	}
+
+	return u.String()
+}
+
+// isTrackingParam reports whether the query param is for tracking the clicks
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+
+	return strings.HasPrefix(key, trackingParamPrefix) || trackingParams[key]
+}
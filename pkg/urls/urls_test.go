@@ -0,0 +1,137 @@
+package urls
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "Empty-URL",
+			raw:  "",
+			want: "",
+		},
+		{
+			name: "Already-Normalized",
+			raw:  "https://github.com/vaguecoder",
+			want: "https://github.com/vaguecoder",
+		},
+		{
+			name: "Case-Of-Scheme-And-Host",
+			raw:  "HTTPS://GitHub.COM/VagueCoder",
+			want: "https://github.com/VagueCoder",
+		},
+		{
+			name: "Default-Port",
+			raw:  "https://github.com:443/vaguecoder",
+			want: "https://github.com/vaguecoder",
+		},
+		{
+			name: "Non-Default-Port",
+			raw:  "http://localhost:8080/",
+			want: "http://localhost:8080/",
+		},
+		{
+			name: "IPv6-Default-Port",
+			raw:  "http://[::1]:80/index.html",
+			want: "http://[::1]/index.html",
+		},
+		{
+			name: "Empty-Path",
+			raw:  " https://go.dev ",
+			want: "https://go.dev/",
+		},
+		{
+			name: "Opaque-URL",
+			raw:  "about:blank",
+			want: "about:blank",
+		},
+		{
+			name: "Relative-URL",
+			raw:  "no scheme here",
+			want: "no scheme here",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.raw), "Mismatch of normalized URL")
+		})
+	}
+}
+
+func TestOptions_Normalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		raw     string
+		want    string
+	}{
+		{
+			name:    "No-Options",
+			options: Options{},
+			raw:     "https://go.dev/doc/?utm_source=newsletter#install",
+			want:    "https://go.dev/doc/?utm_source=newsletter#install",
+		},
+		{
+			name:    "Tracking-Params",
+			options: Options{DropTrackingParams: true},
+			raw:     "https://go.dev/doc/?utm_source=newsletter&UTM_Medium=mail&v=2&fbclid=abc&a=1",
+			want:    "https://go.dev/doc/?a=1&v=2",
+		},
+		{
+			name:    "Only-Tracking-Params",
+			options: Options{DropTrackingParams: true},
+			raw:     "https://go.dev/?gclid=abc",
+			want:    "https://go.dev/",
+		},
+		{
+			name:    "Dangling-Query",
+			options: Options{DropTrackingParams: true},
+			raw:     "https://go.dev/?",
+			want:    "https://go.dev/",
+		},
+		{
+			name:    "Trailing-Slash",
+			options: Options{DropTrailingSlash: true},
+			raw:     "https://go.dev/doc/",
+			want:    "https://go.dev/doc",
+		},
+		{
+			name:    "Trailing-Slash-Of-Root",
+			options: Options{DropTrailingSlash: true},
+			raw:     "HTTPS://go.dev:443/",
+			want:    "https://go.dev",
+		},
+		{
+			name:    "Fragment",
+			options: Options{DropFragment: true},
+			raw:     "https://go.dev/doc/#install",
+			want:    "https://go.dev/doc/",
+		},
+		{
+			name:    "All-Options",
+			options: Options{DropTrackingParams: true, DropTrailingSlash: true, DropFragment: true},
+			raw:     "https://GO.dev/doc/?utm_campaign=go#install",
+			want:    "https://go.dev/doc",
+		},
+		{
+			name:    "Opaque-URL",
+			options: Options{DropTrackingParams: true, DropTrailingSlash: true, DropFragment: true},
+			raw:     "mailto:gopher@go.dev",
+			want:    "mailto:gopher@go.dev",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.options.Normalize(tt.raw), "Mismatch of normalized URL")
+		})
+	}
+}